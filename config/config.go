@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/haninamaryia/tax-calculator/internal/core"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -20,7 +21,13 @@ const (
 
 // Config holds all configurations
 type Config struct {
-	App App
+	App             App
+	RuleSets        []core.RuleSet        `mapstructure:"ruleSets"`
+	PrescribedRates []core.PrescribedRate `mapstructure:"prescribedRates"`
+	CarryRules      []core.CarryRule      `mapstructure:"carryRules"`
+	Rounding        []core.RoundingPolicy `mapstructure:"rounding"`
+	Projection      Projection
+	Data            Data
 }
 
 // App represents application-specific configurations
@@ -30,6 +37,21 @@ type App struct {
 	Debug   bool   `mapstructure:"debug"`
 }
 
+// Projection configures how schedules of years not published upstream are
+// indexed: by the CPI series in CPIFile where it covers the year, by
+// InflationRate otherwise
+type Projection struct {
+	InflationRate float64 `mapstructure:"inflationRate"`
+	CPIFile       string  `mapstructure:"cpiFile"`
+}
+
+// Data locates the local files holding the tax parameters the tax API does
+// not serve
+type Data struct {
+	CorporateFile string `mapstructure:"corporateFile"`
+	SalesTaxFile  string `mapstructure:"salesTaxFile"`
+}
+
 // TODO: use zerolog here too
 // GetConfig initializes and returns the config
 func GetConfig() *Config {
@@ -65,6 +87,17 @@ func GetConfig() *Config {
 		logger.WithError(err).Fatal("Error while unmarshalling config")
 	}
 
+	// Fail fast on rules that cannot be built
+	if err := core.ValidateRuleSets(c.RuleSets); err != nil {
+		logger.WithError(err).Fatal("Invalid tax rule configuration")
+	}
+	if err := core.ValidatePrescribedRates(c.PrescribedRates); err != nil {
+		logger.WithError(err).Fatal("Invalid prescribed interest rates")
+	}
+	if err := core.ValidateRoundingPolicies(c.Rounding); err != nil {
+		logger.WithError(err).Fatal("Invalid rounding policies")
+	}
+
 	// Optionally, configure logging path from the config if debug is enabled
 	if c.App.Debug {
 		logger.SetLevel(logrus.DebugLevel)
@@ -117,6 +150,9 @@ func bindDefault(v *viper.Viper) *viper.Viper {
 	v.SetDefault("App.Port", 8080)
 	v.SetDefault("App.Debug", false)
 	v.SetDefault("App.LogPath", "/tmp/tax-calculator")
+
+	// Projection defaults
+	v.SetDefault("Projection.InflationRate", 0.02)
 	return v
 }
 
@@ -133,6 +169,14 @@ func bindEnv(v *viper.Viper) *viper.Viper {
 	v.BindEnv("App.Port", "TAX_CALCULATOR_APP_PORT")
 	v.BindEnv("App.Debug", "TAX_CALCULATOR_APP_DEBUG")
 	v.BindEnv("App.LogPath", "TAX_CALCULATOR_APP_LOG_PATH")
+
+	// Projection environment variables
+	v.BindEnv("Projection.InflationRate", "TAX_CALCULATOR_PROJECTION_INFLATION_RATE")
+	v.BindEnv("Projection.CPIFile", "TAX_CALCULATOR_PROJECTION_CPI_FILE")
+
+	// Data environment variables
+	v.BindEnv("Data.CorporateFile", "TAX_CALCULATOR_DATA_CORPORATE_FILE")
+	v.BindEnv("Data.SalesTaxFile", "TAX_CALCULATOR_DATA_SALES_TAX_FILE")
 	return v
 }
 
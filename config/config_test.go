@@ -2,8 +2,10 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/haninamaryia/tax-calculator/internal/core"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -12,6 +14,7 @@ func TestGetConfig(t *testing.T) {
 		name        string
 		envVars     map[string]string
 		unsetEnv    []string
+		configFile  string
 		expectedCfg Config
 	}{
 		{
@@ -22,10 +25,10 @@ func TestGetConfig(t *testing.T) {
 				"TAX_CALCULATOR_APP_LOG_PATH",
 				"CONFIG_PATH",
 			},
-			expectedCfg: Config{App{Port: 8080,
+			expectedCfg: Config{App: App{Port: 8080,
 				Debug:   false,
 				LogPath: "/tmp/tax-calculator",
-			}},
+			}, Projection: Projection{InflationRate: 0.02}},
 		},
 		{
 			name: "WithEnvVars",
@@ -33,9 +36,95 @@ func TestGetConfig(t *testing.T) {
 				"TAX_CALCULATOR_APP_PORT":  "8080",
 				"TAX_CALCULATOR_APP_DEBUG": "true",
 			},
-			expectedCfg: Config{App{Port: 8080,
+			expectedCfg: Config{App: App{Port: 8080,
 				Debug:   true,
 				LogPath: "/tmp/tax-calculator"},
+				Projection: Projection{InflationRate: 0.02},
+			},
+		},
+		{
+			name: "WithRuleSetsFromFile",
+			unsetEnv: []string{
+				"TAX_CALCULATOR_APP_PORT",
+				"TAX_CALCULATOR_APP_DEBUG",
+				"TAX_CALCULATOR_APP_LOG_PATH",
+			},
+			configFile: `
+[App]
+Port = 9090
+
+[[RuleSets]]
+Jurisdiction = "ON"
+Year = 2022
+
+  [[RuleSets.Rules]]
+  Type = "brackets"
+
+  [[RuleSets.Rules]]
+  Type = "surtax"
+  Name = "ontario surtax"
+
+    [[RuleSets.Rules.Tiers]]
+    Threshold = 4991
+    Rate = 0.20
+
+    [[RuleSets.Rules.Tiers]]
+    Threshold = 6387
+    Rate = 0.36
+
+  [[RuleSets.Rules]]
+  Type = "credit"
+  Name = "basic personal amount"
+  Rate = 0.0505
+  Amount = 11141
+
+[[PrescribedRates]]
+EffectiveFrom = "2023-01-01"
+Rate = 0.09
+
+[[CarryRules]]
+Year = 2022
+NonCapitalBack = 3
+NonCapitalForward = 20
+CapitalBack = 3
+CapitalForward = -1
+
+[[Rounding]]
+Jurisdiction = "ON"
+Year = 2022
+Step = "total"
+Method = "truncate"
+Increment = 1
+
+[Projection]
+InflationRate = 0.025
+CPIFile = "/etc/tax-calculator/cpi.csv"
+
+[Data]
+CorporateFile = "/etc/tax-calculator/corporate.csv"
+SalesTaxFile = "/etc/tax-calculator/sales_tax.csv"
+`,
+			expectedCfg: Config{
+				App: App{Port: 9090,
+					Debug:   false,
+					LogPath: "/tmp/tax-calculator"},
+				RuleSets: []core.RuleSet{{
+					Jurisdiction: "ON",
+					Year:         2022,
+					Rules: []core.RuleSpec{
+						{Type: "brackets"},
+						{Type: "surtax", Name: "ontario surtax", Tiers: []core.RuleTier{
+							{Threshold: 4991, Rate: 0.20},
+							{Threshold: 6387, Rate: 0.36},
+						}},
+						{Type: "credit", Name: "basic personal amount", Rate: 0.0505, Amount: 11141},
+					},
+				}},
+				PrescribedRates: []core.PrescribedRate{{EffectiveFrom: "2023-01-01", Rate: 0.09}},
+				CarryRules:      []core.CarryRule{{Year: 2022, NonCapitalBack: 3, NonCapitalForward: 20, CapitalBack: 3, CapitalForward: -1}},
+				Rounding:        []core.RoundingPolicy{{Jurisdiction: "ON", Year: 2022, Step: "total", Method: "truncate", Increment: 1}},
+				Projection:      Projection{InflationRate: 0.025, CPIFile: "/etc/tax-calculator/cpi.csv"},
+				Data:            Data{CorporateFile: "/etc/tax-calculator/corporate.csv", SalesTaxFile: "/etc/tax-calculator/sales_tax.csv"},
 			},
 		},
 	}
@@ -50,6 +139,11 @@ func TestGetConfig(t *testing.T) {
 				os.Setenv(k, v)
 				defer os.Unsetenv(k) // Clean up
 			}
+			if tt.configFile != "" {
+				path := filepath.Join(t.TempDir(), "config.toml")
+				assert.NoError(t, os.WriteFile(path, []byte(tt.configFile), 0o644))
+				t.Setenv("CONFIG_PATH", path)
+			}
 
 			// Reset CLI args to simulate config path flag
 			//os.Args = []string{"cmd", "--CONFIG_PATH=./doesnotexist.toml"}
@@ -60,6 +154,12 @@ func TestGetConfig(t *testing.T) {
 			assert.Equal(t, tt.expectedCfg.App.Port, cfg.App.Port)
 			assert.Equal(t, tt.expectedCfg.App.Debug, cfg.App.Debug)
 			assert.Equal(t, tt.expectedCfg.App.LogPath, cfg.App.LogPath)
+			assert.Equal(t, tt.expectedCfg.RuleSets, cfg.RuleSets)
+			assert.Equal(t, tt.expectedCfg.PrescribedRates, cfg.PrescribedRates)
+			assert.Equal(t, tt.expectedCfg.CarryRules, cfg.CarryRules)
+			assert.Equal(t, tt.expectedCfg.Rounding, cfg.Rounding)
+			assert.Equal(t, tt.expectedCfg.Projection, cfg.Projection)
+			assert.Equal(t, tt.expectedCfg.Data, cfg.Data)
 		})
 	}
 }
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// RuleAMT is the alternative minimum tax rule type
+const RuleAMT = "amt"
+
+// Which tax applies after comparing regular tax with the AMT
+const (
+	AMTAppliesRegular = "regular"
+	AMTAppliesAMT     = "amt"
+)
+
+// AMTResult reports the alternative minimum tax computation. CarryForward is
+// the credit available in future years: what was brought in and not used,
+// plus any AMT paid above regular tax this year.
+type AMTResult struct {
+	AdjustedTaxableIncome float64 `json:"adjusted_taxable_income"`
+	Exemption             float64 `json:"exemption"`
+	Rate                  float64 `json:"rate"`
+	MinimumTax            float64 `json:"minimum_tax"`
+	RegularTax            float64 `json:"regular_tax"`
+	Applies               string  `json:"applies"`
+	CarryForwardUsed      float64 `json:"carryforward_used"`
+	CarryForward          float64 `json:"carryforward"`
+}
+
+func init() {
+	RegisterRuleType(RuleAMT, func(spec RuleSpec) (TaxRule, error) {
+		if err := validateRate(spec.Rate); err != nil {
+			return nil, err
+		}
+		if spec.Threshold < 0 {
+			return nil, errors.New("exemption must be non-negative")
+		}
+		inclusion := spec.Inclusion
+		if inclusion == 0 {
+			inclusion = 1
+		}
+		if inclusion < 0 || inclusion > 1 {
+			return nil, fmt.Errorf("inclusion %v must be between 0 and 1", spec.Inclusion)
+		}
+		return amtRule{name: spec.Name, rate: spec.Rate, exemption: spec.Threshold, inclusion: inclusion, prorate: spec.Prorate}, nil
+	})
+}
+
+// amtRule computes adjusted taxable income with its own capital gains
+// inclusion rate and exemption, taxes it at a flat rate and compares the
+// result with the regular tax. When AMT is higher the difference is paid and
+// carried forward; when regular tax is higher, credit carried in from prior
+// years reduces it down to the AMT.
+//
+// Unlike minimum_tax, adjusted taxable income starts from the taxable income
+// and swaps the regular capital gains inclusion for the rule's own, and the
+// regular tax it is compared with leaves out clawbacks, which recover
+// benefits rather than tax income.
+type amtRule struct {
+	name      string
+	rate      float64
+	exemption float64
+	inclusion float64
+	prorate   bool
+}
+
+func (r amtRule) Name() string { return r.name }
+func (r amtRule) Phase() Phase { return PhaseMinimum }
+
+func (r amtRule) Apply(c *Computation) {
+	// Taxable income with the capital gains included in full, then at the
+	// rule's inclusion rate
+	income := c.Income + c.ExcludedCapitalGains - c.Request.CapitalGains
+	ati := r.adjustedIncome(c)
+	exemption := c.Prorated(r.exemption, r.prorate)
+	minimumTax := math.Max(0, ati-exemption) * r.rate
+	regularTax := regularTax(c)
+
+	c.Trace.Addf(StageMinimum, fmt.Sprintf("%.2f + %.2f × %.4f = %.2f", income, c.Request.CapitalGains, r.inclusion, ati), ati,
+		"Adjusted taxable income for %s", r.name)
+	c.Trace.Addf(StageMinimum, fmt.Sprintf("max(0, %.2f - %.2f) × %.4f = %.2f", ati, exemption, r.rate, minimumTax), minimumTax,
+		"Minimum tax for %s", r.name)
+
+	result := &AMTResult{
+		AdjustedTaxableIncome: ati,
+		Exemption:             exemption,
+		Rate:                  r.rate,
+		MinimumTax:            minimumTax,
+		RegularTax:            regularTax,
+	}
+
+	carriedIn := math.Max(0, c.Request.AMTCarryForward)
+	if minimumTax > regularTax {
+		topUp := minimumTax - regularTax
+		result.Applies = AMTAppliesAMT
+		result.CarryForward = carriedIn + topUp
+		c.Trace.Addf(StageMinimum, fmt.Sprintf("%.2f - %.2f = %.2f", minimumTax, regularTax, topUp), topUp,
+			"AMT exceeds regular tax, difference is payable and carried forward")
+		c.AddLine(r, RuleAMT, topUp)
+	} else {
+		used := math.Min(carriedIn, regularTax-minimumTax)
+		result.Applies = AMTAppliesRegular
+		result.CarryForwardUsed = used
+		result.CarryForward = carriedIn - used
+		c.Trace.Addf(StageCredit, fmt.Sprintf("min(%.2f, %.2f - %.2f) = %.2f", carriedIn, regularTax, minimumTax, used), -used,
+			"AMT carry-forward credit applied")
+		c.AddLine(r, RuleAMT, -used)
+	}
+
+	c.AMT = result
+}
+
+// adjustedIncome returns the taxable income with the capital gains included
+// at the rule's rate instead of the regular one
+func (r amtRule) adjustedIncome(c *Computation) float64 {
+	return c.Income + c.ExcludedCapitalGains - c.Request.CapitalGains + c.Request.CapitalGains*r.inclusion
+}
+
+// regularTax returns the tax the AMT is compared with: every line so far
+// except clawbacks
+func regularTax(c *Computation) float64 {
+	return c.TotalTax - c.LineTotal(RuleClawback)
+}
+
+// Kinks returns the exemption, the income above which the minimum tax
+// starts, and the incomes at which the minimum tax crosses the regular tax
+func (r amtRule) Kinks(k KinkContext) []float64 {
+	kinks := sortedKinks(append([]float64{r.exemption}, k.Kinks...))
+	return append([]float64{r.exemption}, crossings(kinks, func(income float64) float64 {
+		c := k.Before(income)
+		return math.Max(0, r.adjustedIncome(c)-r.exemption)*r.rate - regularTax(c)
+	})...)
+}
+
+// addWeighted accumulates another AMT result scaled by weight, used when a
+// year is split between several schedules
+func (a *AMTResult) addWeighted(other AMTResult, weight float64) {
+	a.AdjustedTaxableIncome += other.AdjustedTaxableIncome * weight
+	a.Exemption += other.Exemption * weight
+	a.Rate += other.Rate * weight
+	a.MinimumTax += other.MinimumTax * weight
+	a.RegularTax += other.RegularTax * weight
+	a.CarryForwardUsed += other.CarryForwardUsed * weight
+	a.CarryForward += other.CarryForward * weight
+	a.Applies = AMTAppliesRegular
+	if a.MinimumTax > a.RegularTax {
+		a.Applies = AMTAppliesAMT
+	}
+}
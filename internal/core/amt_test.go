@@ -0,0 +1,210 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAMTRule(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	pipeline, err := NewPipeline([]RuleSpec{
+		{Type: RuleCapitalGains, Rate: 0.5},
+		{Type: RuleBrackets},
+		{Type: RuleAMT, Rate: 0.15, Threshold: 40000},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		req           TaxRequest
+		expectedTotal float64
+		expectedAMT   AMTResult
+	}{
+		{
+			name:          "Large capital gains trigger AMT",
+			req:           TaxRequest{Income: 30000, CapitalGains: 200000},
+			expectedTotal: 28500,
+			expectedAMT: AMTResult{
+				AdjustedTaxableIncome: 230000,
+				Exemption:             40000,
+				Rate:                  0.15,
+				MinimumTax:            28500,
+				RegularTax:            24000,
+				Applies:               AMTAppliesAMT,
+				CarryForward:          4500,
+			},
+		},
+		{
+			name:          "Regular tax applies and uses the carry-forward",
+			req:           TaxRequest{Income: 100000, AMTCarryForward: 3000},
+			expectedTotal: 15000,
+			expectedAMT: AMTResult{
+				AdjustedTaxableIncome: 100000,
+				Exemption:             40000,
+				Rate:                  0.15,
+				MinimumTax:            9000,
+				RegularTax:            18000,
+				Applies:               AMTAppliesRegular,
+				CarryForwardUsed:      3000,
+				CarryForward:          0,
+			},
+		},
+		{
+			name:          "Carry-forward cannot reduce tax below AMT",
+			req:           TaxRequest{Income: 100000, AMTCarryForward: 20000},
+			expectedTotal: 9000,
+			expectedAMT: AMTResult{
+				AdjustedTaxableIncome: 100000,
+				Exemption:             40000,
+				Rate:                  0.15,
+				MinimumTax:            9000,
+				RegularTax:            18000,
+				Applies:               AMTAppliesRegular,
+				CarryForwardUsed:      9000,
+				CarryForward:          11000,
+			},
+		},
+		{
+			name:          "Carry-forward kept when AMT applies again",
+			req:           TaxRequest{Income: 30000, CapitalGains: 200000, AMTCarryForward: 1000},
+			expectedTotal: 28500,
+			expectedAMT: AMTResult{
+				AdjustedTaxableIncome: 230000,
+				Exemption:             40000,
+				Rate:                  0.15,
+				MinimumTax:            28500,
+				RegularTax:            24000,
+				Applies:               AMTAppliesAMT,
+				CarryForward:          5500,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := pipeline.Compute(tt.req, brackets, 1, nil)
+
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+			if assert.NotNil(t, result.AMT) {
+				assert.Equal(t, tt.expectedAMT.Applies, result.AMT.Applies)
+				assert.InDelta(t, tt.expectedAMT.AdjustedTaxableIncome, result.AMT.AdjustedTaxableIncome, 0.0001)
+				assert.InDelta(t, tt.expectedAMT.MinimumTax, result.AMT.MinimumTax, 0.0001)
+				assert.InDelta(t, tt.expectedAMT.RegularTax, result.AMT.RegularTax, 0.0001)
+				assert.InDelta(t, tt.expectedAMT.CarryForwardUsed, result.AMT.CarryForwardUsed, 0.0001)
+				assert.InDelta(t, tt.expectedAMT.CarryForward, result.AMT.CarryForward, 0.0001)
+				assert.Equal(t, tt.expectedAMT.Exemption, result.AMT.Exemption)
+				assert.Equal(t, tt.expectedAMT.Rate, result.AMT.Rate)
+			}
+		})
+	}
+}
+
+func TestAMTRule_Pipeline(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+
+	tests := []struct {
+		name            string
+		rules           []RuleSpec
+		req             TaxRequest
+		expectedTotal   float64
+		expectedATI     float64
+		expectedRegular float64
+	}{
+		{
+			name: "Clawback is not regular tax",
+			rules: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleClawback, Rate: 0.1, Threshold: 50000},
+				{Type: RuleAMT, Rate: 0.25, Threshold: 20000},
+			},
+			req:             TaxRequest{Income: 100000},
+			expectedTotal:   18000 + 5000 + 2000,
+			expectedATI:     100000,
+			expectedRegular: 18000,
+		},
+		{
+			name: "Income deductions reduce adjusted taxable income",
+			rules: []RuleSpec{
+				{Type: RuleSelfEmployment, Rate: 0.1},
+				{Type: RuleBrackets},
+				{Type: RuleAMT, Rate: 0.15, Threshold: 40000},
+			},
+			req:             TaxRequest{BusinessIncome: 100000},
+			expectedTotal:   17000 + 10000,
+			expectedATI:     95000,
+			expectedRegular: 17000,
+		},
+		{
+			name: "Gains without an inclusion rule",
+			rules: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleAMT, Rate: 0.15, Threshold: 40000, Inclusion: 0.8},
+			},
+			req:             TaxRequest{Income: 10000, CapitalGains: 100000},
+			expectedTotal:   20000,
+			expectedATI:     90000,
+			expectedRegular: 20000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline(tt.rules)
+			assert.NoError(t, err)
+
+			result := pipeline.Compute(tt.req, brackets, 1, nil)
+
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+			if assert.NotNil(t, result.AMT) {
+				assert.InDelta(t, tt.expectedATI, result.AMT.AdjustedTaxableIncome, 0.0001)
+				assert.InDelta(t, tt.expectedRegular, result.AMT.RegularTax, 0.0001)
+			}
+		})
+	}
+}
+
+func TestAMTRule_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		spec RuleSpec
+	}{
+		{name: "Rate above one", spec: RuleSpec{Type: RuleAMT, Rate: 1.2}},
+		{name: "Negative exemption", spec: RuleSpec{Type: RuleAMT, Rate: 0.15, Threshold: -1}},
+		{name: "Inclusion above one", spec: RuleSpec{Type: RuleAMT, Rate: 0.15, Inclusion: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRule(tt.spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCapitalGainsInclusion(t *testing.T) {
+	brackets := []TaxBracket{{Min: 0, Max: 0, Rate: 0.2}}
+	req := TaxRequest{Income: 10000, CapitalGains: 50000}
+
+	// Without an inclusion rule gains are taxed in full
+	full := DefaultPipeline().Compute(req, brackets, 1, nil)
+	assert.InDelta(t, 12000, full.TotalTax, 0.0001)
+	assert.InDelta(t, 0.2, full.EffectiveRate, 0.0001)
+	assert.Nil(t, full.AMT)
+
+	pipeline, err := NewPipeline([]RuleSpec{{Type: RuleCapitalGains, Rate: 0.5}, {Type: RuleBrackets}})
+	assert.NoError(t, err)
+
+	trace := NewTrace(true)
+	half := pipeline.Compute(req, brackets, 1, trace)
+	assert.InDelta(t, 7000, half.TotalTax, 0.0001)
+	assert.InDelta(t, 7000.0/60000, half.EffectiveRate, 0.0001)
+	assert.Equal(t, StageDeduction, trace.Steps()[0].Stage)
+	assert.InDelta(t, 25000, trace.Steps()[0].Value, 0.0001)
+}
@@ -0,0 +1,84 @@
+package core
+
+import "fmt"
+
+type (
+	AveragingYear struct {
+		Year   int     `json:"year"`
+		Income float64 `json:"income"`
+	}
+
+	// AveragingRequest lists the incomes of the block of years averaged
+	// under the election
+	AveragingRequest struct {
+		Jurisdiction string          `json:"jurisdiction,omitempty"`
+		Years        []AveragingYear `json:"years"`
+	}
+
+	AveragingYearResult struct {
+		Year           int         `json:"year"`
+		Income         float64     `json:"income"`
+		AveragedIncome float64     `json:"averaged_income"`
+		StandardTax    float64     `json:"standard_tax"`
+		AveragedTax    float64     `json:"averaged_tax"`
+		Saving         float64     `json:"saving"`
+		Projection     *Projection `json:"projection,omitempty"`
+	}
+
+	// AveragingResult compares the tax of the block with and without the
+	// election. Beneficial is set when averaging lowers the total tax.
+	AveragingResult struct {
+		AverageIncome float64               `json:"average_income"`
+		Years         []AveragingYearResult `json:"years"`
+		StandardTax   float64               `json:"standard_tax"`
+		AveragedTax   float64               `json:"averaged_tax"`
+		Saving        float64               `json:"saving"`
+		Beneficial    bool                  `json:"beneficial"`
+	}
+)
+
+// ComputeAveraging taxes every year of the block on the average income of
+// the block, each with its own schedule and rules, and compares the total
+// with the tax on the actual incomes
+func ComputeAveraging(req AveragingRequest, years []TaxYear) (AveragingResult, error) {
+	if len(req.Years) != len(years) {
+		return AveragingResult{}, fmt.Errorf("expected %d tax years, got %d", len(req.Years), len(years))
+	}
+	if len(req.Years) == 0 {
+		return AveragingResult{}, nil
+	}
+
+	var total float64
+	for _, year := range req.Years {
+		total += year.Income
+	}
+	average := total / float64(len(req.Years))
+
+	result := AveragingResult{AverageIncome: average}
+	for i, year := range req.Years {
+		standard, err := years[i].Tax(TaxRequest{Income: year.Income, Jurisdiction: req.Jurisdiction})
+		if err != nil {
+			return AveragingResult{}, fmt.Errorf("tax year %d: %w", year.Year, err)
+		}
+		averaged, err := years[i].Tax(TaxRequest{Income: average, Jurisdiction: req.Jurisdiction})
+		if err != nil {
+			return AveragingResult{}, fmt.Errorf("tax year %d: %w", year.Year, err)
+		}
+
+		result.Years = append(result.Years, AveragingYearResult{
+			Year:           year.Year,
+			Income:         year.Income,
+			AveragedIncome: average,
+			StandardTax:    standard,
+			AveragedTax:    averaged,
+			Saving:         standard - averaged,
+			Projection:     years[i].Projection,
+		})
+		result.StandardTax += standard
+		result.AveragedTax += averaged
+	}
+	result.Saving = result.StandardTax - result.AveragedTax
+	result.Beneficial = result.Saving > 0
+
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeAveraging(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 50000, Rate: 0.2},
+		{Min: 50000, Max: 0, Rate: 0.4},
+	}
+	taxYear := func(year int, b []TaxBracket) TaxYear {
+		return TaxYear{Year: year, Schedules: []TaxSchedule{WholeYearSchedule(year, b)}, Pipeline: DefaultPipeline()}
+	}
+
+	tests := []struct {
+		name               string
+		req                AveragingRequest
+		years              []TaxYear
+		expectedAverage    float64
+		expectedStandard   float64
+		expectedAveraged   float64
+		expectedBeneficial bool
+	}{
+		{
+			name: "Volatile income benefits from averaging",
+			req: AveragingRequest{Years: []AveragingYear{
+				{Year: 2020, Income: 10000},
+				{Year: 2021, Income: 10000},
+				{Year: 2022, Income: 100000},
+			}},
+			years:              []TaxYear{taxYear(2020, brackets), taxYear(2021, brackets), taxYear(2022, brackets)},
+			expectedAverage:    40000,
+			expectedStandard:   28000,
+			expectedAveraged:   18000,
+			expectedBeneficial: true,
+		},
+		{
+			name: "Each year keeps its own schedule",
+			req: AveragingRequest{Years: []AveragingYear{
+				{Year: 2021, Income: 20000},
+				{Year: 2022, Income: 40000},
+			}},
+			years: []TaxYear{
+				taxYear(2021, []TaxBracket{{Min: 0, Max: 0, Rate: 0.1}}),
+				taxYear(2022, []TaxBracket{{Min: 0, Max: 0, Rate: 0.2}}),
+			},
+			expectedAverage:    30000,
+			expectedStandard:   2000 + 8000,
+			expectedAveraged:   3000 + 6000,
+			expectedBeneficial: true,
+		},
+		{
+			name: "Steady income gains nothing",
+			req: AveragingRequest{Years: []AveragingYear{
+				{Year: 2021, Income: 30000},
+				{Year: 2022, Income: 30000},
+			}},
+			years:            []TaxYear{taxYear(2021, brackets), taxYear(2022, brackets)},
+			expectedAverage:  30000,
+			expectedStandard: 8000,
+			expectedAveraged: 8000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeAveraging(tt.req, tt.years)
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedAverage, result.AverageIncome, 0.0001)
+			assert.InDelta(t, tt.expectedStandard, result.StandardTax, 0.0001)
+			assert.InDelta(t, tt.expectedAveraged, result.AveragedTax, 0.0001)
+			assert.InDelta(t, tt.expectedStandard-tt.expectedAveraged, result.Saving, 0.0001)
+			assert.Equal(t, tt.expectedBeneficial, result.Beneficial)
+			assert.Len(t, result.Years, len(tt.req.Years))
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ErrNoPrescribedRate is returned when interest is requested for a day no
+// configured prescribed rate covers
+var ErrNoPrescribedRate = errors.New("no prescribed interest rate in effect")
+
+type (
+	// PrescribedRate is the annual interest rate charged on late balances
+	// from EffectiveFrom, formatted with DateFormat, until the next rate
+	PrescribedRate struct {
+		EffectiveFrom string  `mapstructure:"effectiveFrom" json:"effective_from"`
+		Rate          float64 `mapstructure:"rate" json:"rate"`
+	}
+
+	// BalanceResult is the final position of the return: the total tax less
+	// what was already paid. Exactly one of BalanceOwing and Refund is set.
+	BalanceResult struct {
+		Withheld          float64         `json:"withheld"`
+		Instalments       float64         `json:"instalments"`
+		RefundableCredits float64         `json:"refundable_credits"`
+		Payments          float64         `json:"payments"`
+		BalanceOwing      float64         `json:"balance_owing"`
+		Refund            float64         `json:"refund"`
+		Interest          *InterestResult `json:"interest,omitempty"`
+	}
+
+	// InterestResult estimates the interest on a balance paid after its due
+	// date. Interest compounds daily at the prescribed rate of each day.
+	// Note explains why no estimate was made when no prescribed rate covers
+	// the late days.
+	InterestResult struct {
+		DueDate     string           `json:"due_date"`
+		PaymentDate string           `json:"payment_date"`
+		Days        int              `json:"days"`
+		Amount      float64          `json:"amount"`
+		Periods     []InterestPeriod `json:"periods,omitempty"`
+		Note        string           `json:"note,omitempty"`
+	}
+
+	// InterestPeriod is a run of days charged at the same prescribed rate
+	InterestPeriod struct {
+		From   string  `json:"from"`
+		To     string  `json:"to"`
+		Days   int     `json:"days"`
+		Rate   float64 `json:"rate"`
+		Amount float64 `json:"amount"`
+	}
+)
+
+// BalanceDueDate is the day the balance owing for a tax year is due, April
+// 30 of the following year
+func BalanceDueDate(year int) time.Time {
+	return time.Date(year+1, time.April, 30, 0, 0, 0, 0, time.UTC)
+}
+
+// ValidatePrescribedRates checks that every rate has a valid date and a rate
+// between 0 and 1
+func ValidatePrescribedRates(rates []PrescribedRate) error {
+	for _, rate := range rates {
+		if _, err := time.Parse(DateFormat, rate.EffectiveFrom); err != nil {
+			return fmt.Errorf("invalid prescribed rate date %q, expected format %s", rate.EffectiveFrom, DateFormat)
+		}
+		if rate.Rate < 0 || rate.Rate > 1 {
+			return fmt.Errorf("prescribed rate from %s must be between 0 and 1, got %v", rate.EffectiveFrom, rate.Rate)
+		}
+	}
+	return nil
+}
+
+// ComputeBalance returns the balance owing or refund once withholdings,
+// instalments and refundable credits are applied to the total tax, or nil
+// when the request carries none of them. When the request has a payment
+// date after the due date, interest on the balance owing is estimated with
+// the prescribed rates. Without a rate for the late days the estimate is
+// skipped with a note rather than failing the calculation.
+func ComputeBalance(req TaxRequest, totalTax float64, rates []PrescribedRate, trace *Trace) (*BalanceResult, error) {
+	if req.Withheld == 0 && req.Instalments == 0 && req.RefundableCredits == 0 && req.PaymentDate == "" {
+		return nil, nil
+	}
+
+	payments := req.Withheld + req.Instalments + req.RefundableCredits
+	balance := totalTax - payments
+	trace.Addf(StageBalance, fmt.Sprintf("%.2f + %.2f + %.2f = %.2f", req.Withheld, req.Instalments, req.RefundableCredits, payments), payments,
+		"Payments and refundable credits")
+	trace.Add(StageBalance, "Balance", fmt.Sprintf("%.2f - %.2f = %.2f", totalTax, payments, balance), balance)
+
+	result := &BalanceResult{
+		Withheld:          req.Withheld,
+		Instalments:       req.Instalments,
+		RefundableCredits: req.RefundableCredits,
+		Payments:          payments,
+		BalanceOwing:      math.Max(0, balance),
+		Refund:            math.Max(0, -balance),
+	}
+
+	if req.PaymentDate != "" {
+		paid, err := time.Parse(DateFormat, req.PaymentDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payment date %q, expected format %s", req.PaymentDate, DateFormat)
+		}
+		due := BalanceDueDate(req.Year)
+		interest, err := ComputeInterest(result.BalanceOwing, due, paid, rates)
+		if errors.Is(err, ErrNoPrescribedRate) {
+			trace.Add(StageBalance, "Interest not estimated", err.Error(), 0)
+			result.Interest = &InterestResult{
+				DueDate:     due.Format(DateFormat),
+				PaymentDate: paid.Format(DateFormat),
+				Note:        "interest not estimated: " + err.Error(),
+			}
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		trace.Addf(StageBalance, fmt.Sprintf("%.2f over %d days = %.2f", result.BalanceOwing, interest.Days, interest.Amount), interest.Amount,
+			"Interest from %s to %s", interest.DueDate, interest.PaymentDate)
+		result.Interest = interest
+	}
+
+	return result, nil
+}
+
+// ComputeInterest compounds daily interest on the balance for every day
+// after the due date up to and including the payment date. Each day is
+// charged at the latest prescribed rate in effect on that day.
+func ComputeInterest(balance float64, due, paid time.Time, rates []PrescribedRate) (*InterestResult, error) {
+	result := &InterestResult{
+		DueDate:     due.Format(DateFormat),
+		PaymentDate: paid.Format(DateFormat),
+	}
+	if balance <= 0 || !paid.After(due) {
+		return result, nil
+	}
+
+	sorted := append([]PrescribedRate(nil), rates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].EffectiveFrom < sorted[j].EffectiveFrom })
+
+	owing := balance
+	from := due.AddDate(0, 0, 1)
+	for !from.After(paid) {
+		rate, until, err := prescribedRateOn(sorted, from)
+		if err != nil {
+			return nil, err
+		}
+		to := paid
+		if !until.IsZero() && until.Before(paid) {
+			to = until
+		}
+
+		days := daysBetween(from, to)
+		interest := owing * (math.Pow(1+rate/365, float64(days)) - 1)
+		owing += interest
+
+		result.Periods = append(result.Periods, InterestPeriod{
+			From:   from.Format(DateFormat),
+			To:     to.Format(DateFormat),
+			Days:   days,
+			Rate:   rate,
+			Amount: interest,
+		})
+		result.Days += days
+		result.Amount += interest
+		from = to.AddDate(0, 0, 1)
+	}
+
+	return result, nil
+}
+
+// prescribedRateOn returns the rate in effect on the day and the last day it
+// applies, zero when no later rate is configured. The rates must be sorted.
+func prescribedRateOn(rates []PrescribedRate, day time.Time) (float64, time.Time, error) {
+	index := -1
+	for i, rate := range rates {
+		from, err := time.Parse(DateFormat, rate.EffectiveFrom)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid prescribed rate date %q: %w", rate.EffectiveFrom, err)
+		}
+		if from.After(day) {
+			break
+		}
+		index = i
+	}
+	if index < 0 {
+		return 0, time.Time{}, fmt.Errorf("%w on %s", ErrNoPrescribedRate, day.Format(DateFormat))
+	}
+
+	var until time.Time
+	if index+1 < len(rates) {
+		next, err := time.Parse(DateFormat, rates[index+1].EffectiveFrom)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid prescribed rate date %q: %w", rates[index+1].EffectiveFrom, err)
+		}
+		until = next.AddDate(0, 0, -1)
+	}
+	return rates[index].Rate, until, nil
+}
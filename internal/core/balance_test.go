@@ -0,0 +1,136 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeBalance(t *testing.T) {
+	rates := []PrescribedRate{{EffectiveFrom: "2023-01-01", Rate: 0.1}}
+
+	tests := []struct {
+		name             string
+		req              TaxRequest
+		totalTax         float64
+		expected         *BalanceResult
+		expectedInterest float64
+		expectedError    string
+	}{
+		{
+			name:     "No payments",
+			req:      TaxRequest{Year: 2022},
+			totalTax: 5000,
+			expected: nil,
+		},
+		{
+			name:     "Balance owing",
+			req:      TaxRequest{Year: 2022, Withheld: 3000, Instalments: 1000},
+			totalTax: 5000,
+			expected: &BalanceResult{Withheld: 3000, Instalments: 1000, Payments: 4000, BalanceOwing: 1000},
+		},
+		{
+			name:     "Refundable credits can produce a refund",
+			req:      TaxRequest{Year: 2022, Withheld: 5000, RefundableCredits: 500},
+			totalTax: 5000,
+			expected: &BalanceResult{Withheld: 5000, RefundableCredits: 500, Payments: 5500, Refund: 500},
+		},
+		{
+			name:             "Interest on a late balance",
+			req:              TaxRequest{Year: 2022, Withheld: 4000, PaymentDate: "2023-05-10"},
+			totalTax:         5000,
+			expectedInterest: 1000 * (math.Pow(1+0.1/365, 10) - 1),
+			expected: &BalanceResult{Withheld: 4000, Payments: 4000, BalanceOwing: 1000, Interest: &InterestResult{
+				DueDate: "2023-04-30", PaymentDate: "2023-05-10", Days: 10,
+			}},
+		},
+		{
+			name:     "No interest when paid on time",
+			req:      TaxRequest{Year: 2022, Withheld: 4000, PaymentDate: "2023-04-30"},
+			totalTax: 5000,
+			expected: &BalanceResult{Withheld: 4000, Payments: 4000, BalanceOwing: 1000, Interest: &InterestResult{
+				DueDate: "2023-04-30", PaymentDate: "2023-04-30",
+			}},
+		},
+		{
+			name:          "Malformed payment date",
+			req:           TaxRequest{Year: 2022, PaymentDate: "10/05/2023"},
+			totalTax:      5000,
+			expectedError: "invalid payment date",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeBalance(tt.req, tt.totalTax, rates, nil)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			if tt.expected.Interest != nil {
+				assert.NotNil(t, result.Interest)
+				assert.InDelta(t, tt.expectedInterest, result.Interest.Amount, 0.0001)
+				assert.Equal(t, tt.expected.Interest.Days, result.Interest.Days)
+				assert.Equal(t, tt.expected.Interest.DueDate, result.Interest.DueDate)
+				assert.Equal(t, tt.expected.Interest.PaymentDate, result.Interest.PaymentDate)
+				result.Interest, tt.expected.Interest = nil, nil
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestComputeBalance_NoPrescribedRate(t *testing.T) {
+	req := TaxRequest{Year: 2022, Withheld: 4000, PaymentDate: "2023-05-10"}
+
+	result, err := ComputeBalance(req, 5000, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1000.0, result.BalanceOwing)
+	assert.NotNil(t, result.Interest)
+	assert.Zero(t, result.Interest.Amount)
+	assert.Equal(t, "2023-04-30", result.Interest.DueDate)
+	assert.Contains(t, result.Interest.Note, "no prescribed interest rate in effect on 2023-05-01")
+}
+
+func TestComputeInterest(t *testing.T) {
+	due := BalanceDueDate(2022)
+	paid := time.Date(2023, time.May, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Rate changes split the periods", func(t *testing.T) {
+		rates := []PrescribedRate{
+			{EffectiveFrom: "2023-05-06", Rate: 0.10},
+			{EffectiveFrom: "2023-01-01", Rate: 0.08},
+		}
+		result, err := ComputeInterest(1000, due, paid, rates)
+		assert.NoError(t, err)
+
+		first := 1000 * (math.Pow(1+0.08/365, 5) - 1)
+		second := (1000 + first) * (math.Pow(1+0.10/365, 5) - 1)
+		assert.Len(t, result.Periods, 2)
+		assert.Equal(t, InterestPeriod{From: "2023-05-01", To: "2023-05-05", Days: 5, Rate: 0.08, Amount: result.Periods[0].Amount}, result.Periods[0])
+		assert.Equal(t, "2023-05-06", result.Periods[1].From)
+		assert.InDelta(t, first, result.Periods[0].Amount, 0.0001)
+		assert.InDelta(t, second, result.Periods[1].Amount, 0.0001)
+		assert.InDelta(t, first+second, result.Amount, 0.0001)
+		assert.Equal(t, 10, result.Days)
+	})
+
+	t.Run("No rate configured", func(t *testing.T) {
+		_, err := ComputeInterest(1000, due, paid, nil)
+		assert.ErrorIs(t, err, ErrNoPrescribedRate)
+	})
+}
+
+func TestValidatePrescribedRates(t *testing.T) {
+	assert.NoError(t, ValidatePrescribedRates([]PrescribedRate{{EffectiveFrom: "2023-01-01", Rate: 0.09}}))
+	assert.ErrorContains(t, ValidatePrescribedRates([]PrescribedRate{{EffectiveFrom: "2023/01/01", Rate: 0.09}}), "invalid prescribed rate date")
+	assert.ErrorContains(t, ValidatePrescribedRates([]PrescribedRate{{EffectiveFrom: "2023-01-01", Rate: 9}}), "between 0 and 1")
+}
@@ -0,0 +1,66 @@
+package core
+
+import "fmt"
+
+// ComputeTax applies the progressive brackets to the given income and
+// returns the total tax, the tax per band and the effective rate.
+// Brackets are expected in ascending order; a Max of 0 means no upper limit.
+func ComputeTax(income float64, brackets []TaxBracket) TaxResult {
+	return ComputeTaxTraced(income, brackets, nil)
+}
+
+// ComputeTaxTraced is ComputeTax recording each bracket's contribution in trace
+func ComputeTaxTraced(income float64, brackets []TaxBracket, trace *Trace) TaxResult {
+	return ComputeTaxRounded(income, brackets, RoundingPolicy{}, trace)
+}
+
+// ComputeTaxRounded is ComputeTaxTraced rounding the tax of each bracket
+// when the policy rounds at the bracket step
+func ComputeTaxRounded(income float64, brackets []TaxBracket, policy RoundingPolicy, trace *Trace) TaxResult {
+	perBand := make(map[string]float64)
+	var totalTax float64
+
+	for _, b := range brackets {
+		if income <= b.Min {
+			break
+		}
+
+		upper := b.Max
+		if upper == 0 || income < upper {
+			upper = income
+		}
+
+		taxable := upper - b.Min
+		if taxable < 0 {
+			taxable = 0
+		}
+
+		tax := taxable * b.Rate
+		key := fmt.Sprintf("%.2f-%.2f", b.Min, upper)
+
+		trace.Addf(StageBracket, fmt.Sprintf("(%.2f - %.2f) × %.4f = %.2f", upper, b.Min, b.Rate, tax), tax,
+			"Tax on band %s at rate %.4f", key, b.Rate)
+
+		if policy.Step == RoundBracket {
+			rounded := policy.Round(tax)
+			trace.Addf(StageRounding, fmt.Sprintf("%s(%.4f) = %.2f", policy.describe(), tax, rounded), rounded,
+				"Tax on band %s rounded", key)
+			tax = rounded
+		}
+
+		totalTax += tax
+		perBand[key] = tax
+	}
+
+	effectiveRate := 0.0
+	if income > 0 {
+		effectiveRate = totalTax / income
+	}
+
+	return TaxResult{
+		TotalTax:      totalTax,
+		PerBracket:    perBand,
+		EffectiveRate: effectiveRate,
+		MarginalRate:  MarginalRate(income, brackets),
+	}
+}
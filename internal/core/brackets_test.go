@@ -0,0 +1,63 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeTax(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0.1},
+		{Min: 10000, Max: 50000, Rate: 0.2},
+		{Min: 50000, Max: 0, Rate: 0.3},
+	}
+
+	tests := []struct {
+		name          string
+		income        float64
+		expectedTotal float64
+		expectedBands map[string]float64
+	}{
+		{
+			name:          "Zero income",
+			income:        0,
+			expectedTotal: 0,
+			expectedBands: map[string]float64{},
+		},
+		{
+			name:          "Within first bracket",
+			income:        5000,
+			expectedTotal: 500,
+			expectedBands: map[string]float64{"0.00-5000.00": 500},
+		},
+		{
+			name:          "Open-ended top bracket",
+			income:        60000,
+			expectedTotal: 12000,
+			expectedBands: map[string]float64{
+				"0.00-10000.00":     1000,
+				"10000.00-50000.00": 8000,
+				"50000.00-60000.00": 3000,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ComputeTax(tt.income, brackets)
+
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+			assert.Equal(t, len(tt.expectedBands), len(result.PerBracket))
+			for k, v := range tt.expectedBands {
+				assert.InDelta(t, v, result.PerBracket[k], 0.0001)
+			}
+			assert.Equal(t, MarginalRate(tt.income, brackets), result.MarginalRate)
+			if tt.income > 0 {
+				assert.InDelta(t, tt.expectedTotal/tt.income, result.EffectiveRate, 0.0001)
+			} else {
+				assert.Equal(t, 0.0, result.EffectiveRate)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// Calculate runs the pipeline for the request against the schedules of its
+// year. When the request asks for an explanation the result carries the
+// ordered trace of every step. The prescribed rates estimate interest on a
+// balance owing paid late. The net gain realized by the transactions of the
+// year is taxed as capital gains. The pipeline's rounding policy rounds the
+// total tax before the balance is computed; a rounding line carries the
+// difference so the lines still add up to the total.
+func Calculate(req TaxRequest, schedules []TaxSchedule, pipeline Pipeline, rates []PrescribedRate) (TaxResult, error) {
+	trace := NewTrace(req.Explain)
+
+	var ledger *LedgerYear
+	if len(req.Transactions) > 0 {
+		result, err := ComputeLedger(req.Transactions)
+		if err != nil {
+			return TaxResult{}, err
+		}
+		year := result.Year(req.Year)
+		ledger = &year
+		trace.Add(StageInput, "Net gain realized by the transactions", fmt.Sprintf("%.2f - %.2f = %.2f", year.Gains, year.Losses, year.NetGain), year.NetGain)
+		// A net capital loss does not reduce other income
+		if year.NetGain > 0 {
+			req.CapitalGains += year.NetGain
+		}
+	}
+
+	trace.Add(StageInput, "Income", fmt.Sprintf("income = %.2f", req.Income), req.Income)
+	if req.CapitalGains > 0 {
+		trace.Add(StageInput, "Capital gains", fmt.Sprintf("capital gains = %.2f", req.CapitalGains), req.CapitalGains)
+	}
+	if req.Dividends > 0 {
+		trace.Add(StageInput, "Dividends", fmt.Sprintf("dividends = %.2f", req.Dividends), req.Dividends)
+	}
+	if req.BusinessIncome > 0 {
+		trace.Add(StageInput, "Business income", fmt.Sprintf("business income = %.2f", req.BusinessIncome), req.BusinessIncome)
+	}
+	trace.Add(StageInput, "Tax year", fmt.Sprintf("year = %d", req.Year), float64(req.Year))
+
+	residency, err := ComputeResidency(req)
+	if err != nil {
+		return TaxResult{}, err
+	}
+	if residency != nil {
+		trace.Addf(StageInput, fmt.Sprintf("%d / %d = %.4f", residency.Days, residency.YearDays, residency.Factor), residency.Factor,
+			"Resident from %s to %s", residency.Start, residency.End)
+	}
+	factor := 1.0
+	if residency != nil {
+		factor = residency.Factor
+	}
+
+	var result TaxResult
+	if req.AsOf != "" {
+		date, err := time.Parse(DateFormat, req.AsOf)
+		if err != nil {
+			return TaxResult{}, fmt.Errorf("invalid as-of date %q: %w", req.AsOf, err)
+		}
+		trace.Add(StageInput, "Calculation as of "+req.AsOf, "", 0)
+
+		schedule, err := ScheduleAsOf(schedules, date)
+		if err != nil {
+			return TaxResult{}, err
+		}
+		traceSchedule(trace, schedule)
+		result = pipeline.Compute(req, schedule.Brackets, factor, trace)
+	} else {
+		blended, err := ComputeBlended(req, schedules, pipeline, factor, trace)
+		if err != nil {
+			return TaxResult{}, err
+		}
+		result = blended
+	}
+
+	if policy := pipeline.Rounding(); policy.Enabled() {
+		rounded := policy.Round(result.TotalTax)
+		trace.Add(StageRounding, "Total tax rounded", fmt.Sprintf("%s(%.4f) = %.2f", policy.describe(), result.TotalTax, rounded), rounded)
+		if rounded != result.TotalTax {
+			result.Lines = append(result.Lines, TaxLine{Name: LineRounding, Type: LineRounding, Amount: rounded - result.TotalTax})
+		}
+		result.TotalTax = rounded
+		if req.TotalIncome() > 0 {
+			result.EffectiveRate = rounded / req.TotalIncome()
+		}
+	}
+
+	trace.Add(StageTotal, "Total tax", fmt.Sprintf("%.2f", result.TotalTax), result.TotalTax)
+	if req.TotalIncome() > 0 {
+		trace.Add(StageTotal, "Effective rate", fmt.Sprintf("%.2f / %.2f = %.4f", result.TotalTax, req.TotalIncome(), result.EffectiveRate), result.EffectiveRate)
+	}
+
+	balance, err := ComputeBalance(req, result.TotalTax, rates, trace)
+	if err != nil {
+		return TaxResult{}, err
+	}
+
+	result.Residency = residency
+	result.Balance = balance
+	result.Ledger = ledger
+	result.Trace = trace.Steps()
+	return result, nil
+}
+
+// TaxYear holds what is needed to calculate the tax of one year, so
+// calculations spanning several years can recompute each year's tax.
+// Projection is set when the schedules were projected from an earlier year.
+type TaxYear struct {
+	Year       int
+	Schedules  []TaxSchedule
+	Pipeline   Pipeline
+	Projection *Projection
+}
+
+// Calculate runs the request under the year's schedules and pipeline
+func (y TaxYear) Calculate(req TaxRequest) (TaxResult, error) {
+	req.Year = y.Year
+	result, err := Calculate(req, y.Schedules, y.Pipeline, nil)
+	if err != nil {
+		return TaxResult{}, err
+	}
+	if y.Projection != nil {
+		result.Projected = true
+		result.Projection = y.Projection
+	}
+	return result, nil
+}
+
+// Tax returns the total tax of the request under the year's schedules and
+// pipeline
+func (y TaxYear) Tax(req TaxRequest) (float64, error) {
+	req.Explain = false
+	result, err := y.Calculate(req)
+	if err != nil {
+		return 0, err
+	}
+	return result.TotalTax, nil
+}
+
+// Kinks returns the sorted incomes, starting at 0, at which the tax of the
+// year changes slope: the bracket boundaries of every schedule and the
+// thresholds of the rules under each of them
+func (y TaxYear) Kinks() []float64 {
+	var values []float64
+	for _, schedule := range y.Schedules {
+		for _, b := range schedule.Brackets {
+			values = append(values, b.Min, b.Max)
+		}
+		values = append(values, y.Pipeline.kinks(schedule.Brackets)...)
+	}
+	return sortedKinks(values)
+}
+
+// ClosingSchedule returns the schedule in effect on the last day of the year
+func (y TaxYear) ClosingSchedule() (TaxSchedule, error) {
+	return ScheduleAsOf(y.Schedules, time.Date(y.Year, time.December, 31, 0, 0, 0, 0, time.UTC))
+}
+
+// compiled returns the compiled brackets of the year when they alone give
+// its tax: a single schedule covering the whole year, under a pipeline
+// applying only the brackets
+func (y TaxYear) compiled() (CompiledSchedule, bool) {
+	if len(y.Schedules) != 1 || !y.Pipeline.bracketsOnly() {
+		return CompiledSchedule{}, false
+	}
+	from, to, err := y.Schedules[0].Dates()
+	if err != nil || from.After(time.Date(y.Year, time.January, 1, 0, 0, 0, 0, time.UTC)) ||
+		(!to.IsZero() && to.Before(time.Date(y.Year, time.December, 31, 0, 0, 0, 0, time.UTC))) {
+		return CompiledSchedule{}, false
+	}
+	schedule, err := CompileBrackets(y.Schedules[0].Brackets)
+	return schedule, err == nil
+}
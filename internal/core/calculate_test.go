@@ -0,0 +1,104 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculate(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0.1},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	schedules := []TaxSchedule{
+		{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-12-31", Brackets: brackets, Source: "test", Version: "v1"},
+	}
+
+	tests := []struct {
+		name           string
+		req            TaxRequest
+		schedules      []TaxSchedule
+		expectedTotal  float64
+		expectedStages []string
+		expectError    bool
+	}{
+		{
+			name:          "Without explain there is no trace",
+			req:           TaxRequest{Income: 30000, Year: 2022},
+			schedules:     schedules,
+			expectedTotal: 5000,
+		},
+		{
+			name:          "Explain records every step in order",
+			req:           TaxRequest{Income: 30000, Year: 2022, Explain: true},
+			schedules:     schedules,
+			expectedTotal: 5000,
+			expectedStages: []string{
+				StageInput, StageInput,
+				StageSchedule,
+				StageBracket, StageBracket,
+				StageTotal, StageTotal,
+			},
+		},
+		{
+			name:          "Explain with an as-of date",
+			req:           TaxRequest{Income: 5000, Year: 2022, AsOf: "2022-05-01", Explain: true},
+			schedules:     schedules,
+			expectedTotal: 500,
+			expectedStages: []string{
+				StageInput, StageInput, StageInput,
+				StageSchedule,
+				StageBracket,
+				StageTotal, StageTotal,
+			},
+		},
+		{
+			name: "Explain a blended year",
+			req:  TaxRequest{Income: 5000, Year: 2022, Explain: true},
+			schedules: []TaxSchedule{
+				{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-07-01", Brackets: brackets},
+				{EffectiveFrom: "2022-07-02", Brackets: []TaxBracket{{Min: 0, Max: 0, Rate: 0.2}}},
+			},
+			expectedTotal: 500*182/365.0 + 1000*183/365.0,
+			expectedStages: []string{
+				StageInput, StageInput,
+				StageSchedule, StageBracket, StageSchedule,
+				StageSchedule, StageBracket, StageSchedule,
+				StageTotal, StageTotal,
+			},
+		},
+		{
+			name:        "Invalid as-of date",
+			req:         TaxRequest{Income: 5000, Year: 2022, AsOf: "2022-13-01"},
+			schedules:   schedules,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Calculate(tt.req, tt.schedules, DefaultPipeline(), nil)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+
+			stages := make([]string, 0, len(result.Trace))
+			for i, step := range result.Trace {
+				assert.Equal(t, i+1, step.Step)
+				stages = append(stages, step.Stage)
+			}
+			if tt.expectedStages == nil {
+				assert.Nil(t, result.Trace)
+			} else {
+				assert.Equal(t, tt.expectedStages, stages)
+				assert.Equal(t, result.TotalTax, result.Trace[len(result.Trace)-2].Value)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// CompareRequest compares the tax on Income across Years under the rules
+	// of Jurisdiction
+	CompareRequest struct {
+		Income       float64 `json:"income"`
+		Years        []int   `json:"years"`
+		Jurisdiction string  `json:"jurisdiction,omitempty"`
+	}
+
+	YearResult struct {
+		Year int `json:"year"`
+		TaxResult
+	}
+
+	// ScheduleChange records a bracket that differs between two schedules.
+	// Previous is nil for an added bracket and Current is nil for a removed one.
+	ScheduleChange struct {
+		Index    int         `json:"index"`
+		Fields   []string    `json:"fields,omitempty"`
+		Previous *TaxBracket `json:"previous,omitempty"`
+		Current  *TaxBracket `json:"current,omitempty"`
+	}
+
+	// BracketDelta is the change in the tax on one bracket, matched by
+	// position. Previous and Current are the bracket in the schedules in
+	// effect at the end of each year, nil where the schedule has no bracket
+	// at that position.
+	BracketDelta struct {
+		Index    int         `json:"index"`
+		Previous *TaxBracket `json:"previous,omitempty"`
+		Current  *TaxBracket `json:"current,omitempty"`
+		Tax      float64     `json:"tax"`
+	}
+
+	// YearDelta holds the change from one year to the next in the comparison
+	YearDelta struct {
+		FromYear        int              `json:"from_year"`
+		ToYear          int              `json:"to_year"`
+		TotalTax        float64          `json:"total_tax"`
+		EffectiveRate   float64          `json:"effective_rate"`
+		PerBracket      []BracketDelta   `json:"per_bracket"`
+		ScheduleChanges []ScheduleChange `json:"schedule_changes,omitempty"`
+	}
+
+	CompareResult struct {
+		Income  float64      `json:"income"`
+		Results []YearResult `json:"results"`
+		Deltas  []YearDelta  `json:"deltas"`
+	}
+)
+
+// CompareSchedules lists the brackets whose boundaries or rate changed,
+// matching brackets by position.
+func CompareSchedules(previous, current []TaxBracket) []ScheduleChange {
+	var changes []ScheduleChange
+
+	for i := 0; i < len(previous) || i < len(current); i++ {
+		switch {
+		case i >= len(current):
+			p := previous[i]
+			changes = append(changes, ScheduleChange{Index: i, Previous: &p})
+		case i >= len(previous):
+			c := current[i]
+			changes = append(changes, ScheduleChange{Index: i, Current: &c})
+		default:
+			p, c := previous[i], current[i]
+			var fields []string
+			if p.Min != c.Min {
+				fields = append(fields, "min")
+			}
+			if p.Max != c.Max {
+				fields = append(fields, "max")
+			}
+			if p.Rate != c.Rate {
+				fields = append(fields, "rate")
+			}
+			if len(fields) > 0 {
+				changes = append(changes, ScheduleChange{Index: i, Fields: fields, Previous: &p, Current: &c})
+			}
+		}
+	}
+
+	return changes
+}
+
+// taxByBracket sums the per-band tax of a result by bracket position. A band
+// is named after its lower bound and the income it reaches, so it belongs
+// to the bracket with the same lower bound; under schedules changing
+// mid-year each position collects the bands of every schedule.
+func taxByBracket(result TaxResult, schedules []TaxSchedule) []float64 {
+	var amounts []float64
+	counted := make(map[string]bool)
+	for _, schedule := range schedules {
+		for i, b := range schedule.Brackets {
+			if i == len(amounts) {
+				amounts = append(amounts, 0)
+			}
+			lower := fmt.Sprintf("%.2f", b.Min)
+			for key, tax := range result.PerBracket {
+				if min, _, ok := strings.Cut(key, "-"); ok && min == lower && !counted[key] {
+					counted[key] = true
+					amounts[i] += tax
+				}
+			}
+		}
+	}
+	return amounts
+}
+
+// bracketDeltas pairs the brackets of two years by position with the change
+// in the tax on each
+func bracketDeltas(previous, current []float64, previousBrackets, currentBrackets []TaxBracket) []BracketDelta {
+	n := max(len(previous), len(current), len(previousBrackets), len(currentBrackets))
+	deltas := make([]BracketDelta, n)
+	for i := range deltas {
+		deltas[i].Index = i
+		if i < len(previousBrackets) {
+			b := previousBrackets[i]
+			deltas[i].Previous = &b
+		}
+		if i < len(currentBrackets) {
+			b := currentBrackets[i]
+			deltas[i].Current = &b
+		}
+		if i < len(current) {
+			deltas[i].Tax += current[i]
+		}
+		if i < len(previous) {
+			deltas[i].Tax -= previous[i]
+		}
+	}
+	return deltas
+}
+
+// ComputeComparison calculates tax for the same income in each year and the
+// deltas between consecutive years. Schedule changes compare the schedules
+// in effect at the end of each year.
+func ComputeComparison(req CompareRequest, years []TaxYear) (CompareResult, error) {
+	result := CompareResult{
+		Income:  req.Income,
+		Results: make([]YearResult, len(years)),
+	}
+
+	schedules := make([][]TaxBracket, len(years))
+	amounts := make([][]float64, len(years))
+	for i, year := range years {
+		tax, err := year.Calculate(TaxRequest{Income: req.Income, Jurisdiction: req.Jurisdiction})
+		if err != nil {
+			return CompareResult{}, fmt.Errorf("tax year %d: %w", year.Year, err)
+		}
+		closing, err := year.ClosingSchedule()
+		if err != nil {
+			return CompareResult{}, fmt.Errorf("tax year %d: %w", year.Year, err)
+		}
+		result.Results[i] = YearResult{Year: year.Year, TaxResult: tax}
+		schedules[i] = closing.Brackets
+		amounts[i] = taxByBracket(tax, year.Schedules)
+	}
+
+	for i := 1; i < len(years); i++ {
+		prev, curr := result.Results[i-1], result.Results[i]
+
+		result.Deltas = append(result.Deltas, YearDelta{
+			FromYear:        prev.Year,
+			ToYear:          curr.Year,
+			TotalTax:        curr.TotalTax - prev.TotalTax,
+			EffectiveRate:   curr.EffectiveRate - prev.EffectiveRate,
+			PerBracket:      bracketDeltas(amounts[i-1], amounts[i], schedules[i-1], schedules[i]),
+			ScheduleChanges: CompareSchedules(schedules[i-1], schedules[i]),
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareSchedules(t *testing.T) {
+	base := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0.1},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+
+	tests := []struct {
+		name     string
+		current  []TaxBracket
+		expected []ScheduleChange
+	}{
+		{
+			name:     "Identical schedules",
+			current:  base,
+			expected: nil,
+		},
+		{
+			name: "Indexed boundary and new rate",
+			current: []TaxBracket{
+				{Min: 0, Max: 11000, Rate: 0.1},
+				{Min: 11000, Max: 0, Rate: 0.25},
+			},
+			expected: []ScheduleChange{
+				{Index: 0, Fields: []string{"max"}, Previous: &base[0], Current: &TaxBracket{Min: 0, Max: 11000, Rate: 0.1}},
+				{Index: 1, Fields: []string{"min", "rate"}, Previous: &base[1], Current: &TaxBracket{Min: 11000, Max: 0, Rate: 0.25}},
+			},
+		},
+		{
+			name: "Added bracket",
+			current: []TaxBracket{
+				{Min: 0, Max: 10000, Rate: 0.1},
+				{Min: 10000, Max: 0, Rate: 0.2},
+				{Min: 200000, Max: 0, Rate: 0.33},
+			},
+			expected: []ScheduleChange{
+				{Index: 2, Current: &TaxBracket{Min: 200000, Max: 0, Rate: 0.33}},
+			},
+		},
+		{
+			name:    "Removed bracket",
+			current: base[:1],
+			expected: []ScheduleChange{
+				{Index: 1, Previous: &base[1]},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CompareSchedules(base, tt.current))
+		})
+	}
+}
+
+func TestComputeComparison(t *testing.T) {
+	schedules := [][]TaxBracket{
+		{{Min: 0, Max: 10000, Rate: 0.1}, {Min: 10000, Max: 0, Rate: 0.2}},
+		{{Min: 0, Max: 10000, Rate: 0.1}, {Min: 10000, Max: 0, Rate: 0.25}},
+		{{Min: 0, Max: 20000, Rate: 0.1}, {Min: 20000, Max: 0, Rate: 0.25}},
+	}
+
+	years := make([]TaxYear, len(schedules))
+	for i, brackets := range schedules {
+		year := 2020 + i
+		years[i] = TaxYear{Year: year, Schedules: []TaxSchedule{WholeYearSchedule(year, brackets)}, Pipeline: DefaultPipeline()}
+	}
+
+	result, err := ComputeComparison(CompareRequest{Income: 30000, Years: []int{2020, 2021, 2022}}, years)
+
+	assert.NoError(t, err)
+
+	assert.Equal(t, 30000.0, result.Income)
+	assert.Len(t, result.Results, 3)
+	assert.InDelta(t, 5000, result.Results[0].TotalTax, 0.0001)
+	assert.InDelta(t, 6000, result.Results[1].TotalTax, 0.0001)
+	assert.InDelta(t, 4500, result.Results[2].TotalTax, 0.0001)
+
+	assert.Len(t, result.Deltas, 2)
+	assert.Equal(t, 2020, result.Deltas[0].FromYear)
+	assert.Equal(t, 2021, result.Deltas[0].ToYear)
+	assert.InDelta(t, 1000, result.Deltas[0].TotalTax, 0.0001)
+	assert.InDelta(t, 1000.0/30000, result.Deltas[0].EffectiveRate, 0.0001)
+	assert.Len(t, result.Deltas[0].PerBracket, 2)
+	assert.Equal(t, 1, result.Deltas[0].PerBracket[1].Index)
+	assert.Equal(t, schedules[0][1], *result.Deltas[0].PerBracket[1].Previous)
+	assert.Equal(t, schedules[1][1], *result.Deltas[0].PerBracket[1].Current)
+	assert.InDelta(t, 0, result.Deltas[0].PerBracket[0].Tax, 0.0001)
+	assert.InDelta(t, 1000, result.Deltas[0].PerBracket[1].Tax, 0.0001)
+	assert.Len(t, result.Deltas[0].ScheduleChanges, 1)
+
+	assert.InDelta(t, -1500, result.Deltas[1].TotalTax, 0.0001)
+	assert.InDelta(t, 1000, result.Deltas[1].PerBracket[0].Tax, 0.0001)
+	assert.InDelta(t, -2500, result.Deltas[1].PerBracket[1].Tax, 0.0001)
+	assert.Len(t, result.Deltas[1].ScheduleChanges, 2)
+}
+
+func TestComputeComparison_MidYearSchedules(t *testing.T) {
+	brackets := []TaxBracket{{Min: 0, Max: 10000, Rate: 0.1}, {Min: 10000, Max: 0, Rate: 0.2}}
+	years := []TaxYear{
+		{Year: 2021, Schedules: []TaxSchedule{WholeYearSchedule(2021, brackets)}, Pipeline: DefaultPipeline()},
+		{Year: 2022, Schedules: []TaxSchedule{
+			{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-07-01", Brackets: brackets},
+			{EffectiveFrom: "2022-07-02", EffectiveTo: "2022-12-31", Brackets: []TaxBracket{{Min: 0, Max: 20000, Rate: 0.1}, {Min: 20000, Max: 0, Rate: 0.2}}},
+		}, Pipeline: DefaultPipeline()},
+	}
+
+	result, err := ComputeComparison(CompareRequest{Income: 30000, Years: []int{2021, 2022}}, years)
+
+	assert.NoError(t, err)
+	delta := result.Deltas[0]
+	assert.Len(t, delta.PerBracket, 2)
+	var sum float64
+	for _, b := range delta.PerBracket {
+		sum += b.Tax
+	}
+	assert.InDelta(t, delta.TotalTax, sum, 0.0001)
+	// The closing schedule widens the first bracket for 183 days
+	assert.InDelta(t, 1000*183.0/365, delta.PerBracket[0].Tax, 0.0001)
+	assert.Equal(t, 20000.0, delta.PerBracket[0].Current.Max)
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"fmt"
+	"math"
+)
+
+// CompiledSchedule is a set of brackets prepared for evaluating many
+// incomes: the tax below every bracket is precomputed, so evaluation is a
+// binary search and a single multiplication, without allocating.
+type CompiledSchedule struct {
+	mins  []float64
+	ends  []float64
+	rates []float64
+	// cum[i] is the tax on every bracket below bracket i
+	cum []float64
+}
+
+// CompileBrackets prepares brackets for repeated evaluation. The brackets
+// must be in ascending order without overlapping, with only the last one
+// open-ended; gaps between brackets are untaxed, as in ComputeTax.
+func CompileBrackets(brackets []TaxBracket) (CompiledSchedule, error) {
+	n := len(brackets)
+	s := CompiledSchedule{
+		mins:  make([]float64, n),
+		ends:  make([]float64, n),
+		rates: make([]float64, n),
+		cum:   make([]float64, n),
+	}
+
+	var total float64
+	for i, b := range brackets {
+		end := b.Max
+		if end == 0 {
+			if i != n-1 {
+				return CompiledSchedule{}, fmt.Errorf("bracket %d is open-ended but not the last one", i)
+			}
+			end = math.Inf(1)
+		}
+		if end < b.Min {
+			return CompiledSchedule{}, fmt.Errorf("bracket %d ends at %.2f before it starts at %.2f", i, b.Max, b.Min)
+		}
+		if i > 0 && b.Min < s.ends[i-1] {
+			return CompiledSchedule{}, fmt.Errorf("bracket %d starts at %.2f before bracket %d ends", i, b.Min, i-1)
+		}
+
+		s.mins[i], s.ends[i], s.rates[i], s.cum[i] = b.Min, end, b.Rate, total
+		if i < n-1 {
+			total += (end - b.Min) * b.Rate
+		}
+	}
+
+	return s, nil
+}
+
+// Tax returns the total tax on the income, the same as ComputeTax
+func (s CompiledSchedule) Tax(income float64) float64 {
+	k := s.bracket(income)
+	if k < 0 {
+		return 0
+	}
+	return s.cum[k] + (math.Min(income, s.ends[k])-s.mins[k])*s.rates[k]
+}
+
+// MarginalRate returns the rate applied to the next unit of income, the
+// same as MarginalRate of the brackets
+func (s CompiledSchedule) MarginalRate(income float64) float64 {
+	// The last bracket starting at or below the income
+	lo, hi := 0, len(s.mins)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if s.mins[mid] <= income {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if k := lo - 1; k >= 0 && income < s.ends[k] {
+		return s.rates[k]
+	}
+	return 0
+}
+
+// bracket returns the index of the last bracket starting below the income,
+// -1 when there is none
+func (s CompiledSchedule) bracket(income float64) int {
+	lo, hi := 0, len(s.mins)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if s.mins[mid] < income {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo - 1
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileBrackets(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0.1},
+		{Min: 10000, Max: 50000, Rate: 0.2},
+		{Min: 60000, Max: 0, Rate: 0.3},
+	}
+
+	tests := []struct {
+		name          string
+		brackets      []TaxBracket
+		income        float64
+		expectedTax   float64
+		expectedRate  float64
+		expectedError string
+	}{
+		{name: "No income", brackets: brackets, income: 0, expectedTax: 0, expectedRate: 0.1},
+		{name: "Negative income", brackets: brackets, income: -100, expectedTax: 0, expectedRate: 0},
+		{name: "First bracket", brackets: brackets, income: 5000, expectedTax: 500, expectedRate: 0.1},
+		{name: "On a boundary", brackets: brackets, income: 10000, expectedTax: 1000, expectedRate: 0.2},
+		{name: "In a gap", brackets: brackets, income: 55000, expectedTax: 9000, expectedRate: 0},
+		{name: "Open-ended bracket", brackets: brackets, income: 70000, expectedTax: 12000, expectedRate: 0.3},
+		{
+			name:         "Closed last bracket",
+			brackets:     []TaxBracket{{Min: 0, Max: 10000, Rate: 0.1}},
+			income:       20000,
+			expectedTax:  1000,
+			expectedRate: 0,
+		},
+		{name: "No brackets", income: 20000},
+		{
+			name:          "Open-ended bracket before the last",
+			brackets:      []TaxBracket{{Min: 0, Rate: 0.1}, {Min: 10000, Rate: 0.2}},
+			expectedError: "bracket 0 is open-ended but not the last one",
+		},
+		{
+			name:          "Overlapping brackets",
+			brackets:      []TaxBracket{{Min: 0, Max: 10000, Rate: 0.1}, {Min: 5000, Rate: 0.2}},
+			expectedError: "bracket 1 starts at 5000.00 before bracket 0 ends",
+		},
+		{
+			name:          "Bracket ending before it starts",
+			brackets:      []TaxBracket{{Min: 10000, Max: 5000, Rate: 0.1}},
+			expectedError: "bracket 0 ends at 5000.00 before it starts at 10000.00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := CompileBrackets(tt.brackets)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTax, schedule.Tax(tt.income), 0.0001)
+			assert.InDelta(t, tt.expectedRate, schedule.MarginalRate(tt.income), 0.0001)
+			assert.Equal(t, ComputeTax(tt.income, tt.brackets).TotalTax, schedule.Tax(tt.income))
+			assert.Equal(t, MarginalRate(tt.income, tt.brackets), schedule.MarginalRate(tt.income))
+		})
+	}
+}
+
+func TestCompiledSchedule_ZeroAllocations(t *testing.T) {
+	schedule, err := CompileBrackets(benchmarkBrackets(5))
+	assert.NoError(t, err)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		schedule.Tax(123456)
+		schedule.MarginalRate(123456)
+	})
+	assert.Zero(t, allocs)
+}
+
+func FuzzCompiledSchedule(f *testing.F) {
+	f.Add(50000.0, 10000.0, 40000.0, 0.0, 0.1, 0.2, 0.3, true)
+	f.Add(10000.0, 10000.0, 40000.0, 5000.0, 0.1, 0.2, 0.3, false)
+	f.Add(0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, true)
+	f.Add(-1.0, 1.0, 1.0, 1.0, 1.0, 1.0, 1.0, true)
+
+	f.Fuzz(func(t *testing.T, income, width1, width2, gap, rate1, rate2, rate3 float64, open bool) {
+		for _, v := range []float64{income, width1, width2, gap, rate1, rate2, rate3} {
+			if math.IsNaN(v) || math.IsInf(v, 0) || math.Abs(v) > 1e12 {
+				t.Skip()
+			}
+		}
+
+		// Build a valid schedule of three brackets, possibly with a gap
+		// before the last one
+		width1, width2, gap = math.Abs(width1), math.Abs(width2), math.Abs(gap)
+		brackets := []TaxBracket{
+			{Min: 0, Max: width1, Rate: rate1},
+			{Min: width1, Max: width1 + width2, Rate: rate2},
+			{Min: width1 + width2 + gap, Rate: rate3},
+		}
+		if !open {
+			brackets[2].Max = brackets[2].Min + width1
+		}
+		if brackets[0].Max == 0 || brackets[1].Max == 0 || (!open && brackets[2].Max == 0) {
+			t.Skip()
+		}
+
+		schedule, err := CompileBrackets(brackets)
+		if err != nil {
+			t.Fatalf("valid brackets %v did not compile: %v", brackets, err)
+		}
+
+		want := ComputeTax(income, brackets).TotalTax
+		if got := schedule.Tax(income); math.Abs(got-want) > 1e-9*math.Max(1, math.Abs(want)) {
+			t.Fatalf("Tax(%v) = %v, ComputeTax = %v for %v", income, got, want, brackets)
+		}
+		if got, want := schedule.MarginalRate(income), MarginalRate(income, brackets); got != want {
+			t.Fatalf("MarginalRate(%v) = %v, want %v for %v", income, got, want, brackets)
+		}
+	})
+}
+
+// benchmarkBrackets returns n contiguous brackets of 10000 each
+func benchmarkBrackets(n int) []TaxBracket {
+	brackets := make([]TaxBracket, n)
+	for i := range brackets {
+		brackets[i] = TaxBracket{Min: float64(i) * 10000, Max: float64(i+1) * 10000, Rate: 0.05 + float64(i)/float64(2*n)}
+	}
+	brackets[n-1].Max = 0
+	return brackets
+}
+
+func BenchmarkComputeTax(b *testing.B) {
+	for _, n := range []int{5, 50} {
+		brackets := benchmarkBrackets(n)
+		income := float64(n) * 9000
+		b.Run(fmt.Sprintf("%d brackets", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ComputeTax(income, brackets)
+			}
+		})
+	}
+}
+
+func BenchmarkCompiledSchedule_Tax(b *testing.B) {
+	for _, n := range []int{5, 50} {
+		schedule, err := CompileBrackets(benchmarkBrackets(n))
+		if err != nil {
+			b.Fatal(err)
+		}
+		income := float64(n) * 9000
+		b.Run(fmt.Sprintf("%d brackets", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				schedule.Tax(income)
+			}
+		})
+	}
+}
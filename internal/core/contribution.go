@@ -0,0 +1,116 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+type (
+	// ContributionRequest asks how a deductible retirement contribution
+	// (RRSP, 401k) of up to Room lowers the tax on Income
+	ContributionRequest struct {
+		Income       float64 `json:"income"`
+		Year         int     `json:"year"`
+		Jurisdiction string  `json:"jurisdiction,omitempty"`
+		Room         float64 `json:"room"`
+	}
+
+	// ContributionBand is a range of contributions saving the same tax on
+	// each additional dollar
+	ContributionBand struct {
+		From   float64 `json:"from"`
+		To     float64 `json:"to"`
+		Rate   float64 `json:"rate"`
+		Saving float64 `json:"saving"`
+	}
+
+	// ContributionRecommendation is a contribution bringing taxable income
+	// down to a bracket boundary, or using all the room. Rate is the average
+	// saving per dollar contributed.
+	ContributionRecommendation struct {
+		Contribution  float64 `json:"contribution"`
+		TaxableIncome float64 `json:"taxable_income"`
+		TaxAfter      float64 `json:"tax_after"`
+		TaxSaved      float64 `json:"tax_saved"`
+		Rate          float64 `json:"rate"`
+	}
+
+	ContributionResult struct {
+		Income          float64                      `json:"income"`
+		Room            float64                      `json:"room"`
+		TaxBefore       float64                      `json:"tax_before"`
+		Bands           []ContributionBand           `json:"bands"`
+		Recommendations []ContributionRecommendation `json:"recommendations"`
+		Projection      *Projection                  `json:"projection,omitempty"`
+	}
+)
+
+// ComputeContribution splits the contribution room into the marginal bands
+// it deducts income from, highest income first, and recommends the
+// contributions that bring income down to each bracket boundary within the
+// room
+func ComputeContribution(req ContributionRequest, year TaxYear) (ContributionResult, error) {
+	taxAt := func(income float64) (float64, error) {
+		return year.Tax(TaxRequest{Income: income, Jurisdiction: req.Jurisdiction})
+	}
+
+	before, err := taxAt(req.Income)
+	if err != nil {
+		return ContributionResult{}, err
+	}
+	result := ContributionResult{Income: req.Income, Room: req.Room, TaxBefore: before, Projection: year.Projection}
+
+	floor := math.Max(0, req.Income-req.Room)
+	boundaries := contributionBoundaries(year, floor, req.Income)
+
+	upper, taxUpper := req.Income, before
+	for _, lower := range boundaries {
+		taxLower, err := taxAt(lower)
+		if err != nil {
+			return ContributionResult{}, fmt.Errorf("tax at %.2f: %w", lower, err)
+		}
+
+		saving := taxUpper - taxLower
+		result.Bands = append(result.Bands, ContributionBand{
+			From:   req.Income - upper,
+			To:     req.Income - lower,
+			Rate:   saving / (upper - lower),
+			Saving: saving,
+		})
+
+		contribution := req.Income - lower
+		saved := before - taxLower
+		result.Recommendations = append(result.Recommendations, ContributionRecommendation{
+			Contribution:  contribution,
+			TaxableIncome: lower,
+			TaxAfter:      taxLower,
+			TaxSaved:      saved,
+			Rate:          saved / contribution,
+		})
+
+		upper, taxUpper = lower, taxLower
+	}
+
+	return result, nil
+}
+
+// contributionBoundaries returns the kinks of the tax of the year strictly
+// between floor and income, highest first, followed by floor itself. Kinks
+// include the points where rules such as credits run out, so the tax is
+// linear within every band.
+func contributionBoundaries(year TaxYear, floor, income float64) []float64 {
+	seen := map[float64]bool{floor: true}
+	boundaries := []float64{floor}
+	for _, kink := range year.Kinks() {
+		if kink > floor && kink < income && !seen[kink] {
+			seen[kink] = true
+			boundaries = append(boundaries, kink)
+		}
+	}
+	if floor >= income {
+		return nil
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(boundaries)))
+	return boundaries
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeContribution(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 50000, Rate: 0.2},
+		{Min: 50000, Max: 0, Rate: 0.4},
+	}
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, brackets)}, Pipeline: DefaultPipeline()}
+
+	tests := []struct {
+		name                    string
+		req                     ContributionRequest
+		expectedBands           []ContributionBand
+		expectedRecommendations []float64
+	}{
+		{
+			name: "Room spans two brackets",
+			req:  ContributionRequest{Income: 60000, Room: 20000},
+			expectedBands: []ContributionBand{
+				{From: 0, To: 10000, Rate: 0.4, Saving: 4000},
+				{From: 10000, To: 20000, Rate: 0.2, Saving: 2000},
+			},
+			expectedRecommendations: []float64{10000, 20000},
+		},
+		{
+			name: "Room larger than taxable income",
+			req:  ContributionRequest{Income: 30000, Room: 50000},
+			expectedBands: []ContributionBand{
+				{From: 0, To: 20000, Rate: 0.2, Saving: 4000},
+				{From: 20000, To: 30000, Rate: 0, Saving: 0},
+			},
+			expectedRecommendations: []float64{20000, 30000},
+		},
+		{
+			name: "Room within one bracket",
+			req:  ContributionRequest{Income: 30000, Room: 5000},
+			expectedBands: []ContributionBand{
+				{From: 0, To: 5000, Rate: 0.2, Saving: 1000},
+			},
+			expectedRecommendations: []float64{5000},
+		},
+		{
+			name: "No room",
+			req:  ContributionRequest{Income: 30000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeContribution(tt.req, year)
+			assert.NoError(t, err)
+			assert.Len(t, result.Bands, len(tt.expectedBands))
+			for i, band := range tt.expectedBands {
+				assert.InDelta(t, band.From, result.Bands[i].From, 0.0001)
+				assert.InDelta(t, band.To, result.Bands[i].To, 0.0001)
+				assert.InDelta(t, band.Rate, result.Bands[i].Rate, 0.0001)
+				assert.InDelta(t, band.Saving, result.Bands[i].Saving, 0.0001)
+			}
+			assert.Len(t, result.Recommendations, len(tt.expectedRecommendations))
+			for i, contribution := range tt.expectedRecommendations {
+				assert.InDelta(t, contribution, result.Recommendations[i].Contribution, 0.0001)
+				assert.InDelta(t, tt.req.Income-contribution, result.Recommendations[i].TaxableIncome, 0.0001)
+			}
+		})
+	}
+}
+
+func TestComputeContribution_Credit(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	// The credit of 2000 is used up at 20000, so contributions below it
+	// save nothing
+	pipeline, err := NewPipeline([]RuleSpec{{Type: RuleBrackets}, {Type: RuleCredit, Rate: 0.2, Amount: 10000}})
+	assert.NoError(t, err)
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, brackets)}, Pipeline: pipeline}
+
+	result, err := ComputeContribution(ContributionRequest{Income: 30000, Room: 25000}, year)
+
+	assert.NoError(t, err)
+	expected := []ContributionBand{
+		{From: 0, To: 10000, Rate: 0.2, Saving: 2000},
+		{From: 10000, To: 20000, Rate: 0, Saving: 0},
+		{From: 20000, To: 25000, Rate: 0, Saving: 0},
+	}
+	assert.Len(t, result.Bands, len(expected))
+	for i, band := range expected {
+		assert.InDelta(t, band.From, result.Bands[i].From, 0.0001)
+		assert.InDelta(t, band.To, result.Bands[i].To, 0.0001)
+		assert.InDelta(t, band.Rate, result.Bands[i].Rate, 0.0001)
+		assert.InDelta(t, band.Saving, result.Bands[i].Saving, 0.0001)
+	}
+}
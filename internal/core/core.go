@@ -9,9 +9,65 @@ type (
 		Rate float64 `json:"rate"`
 	}
 
+	// TaxRequest holds the inputs of a tax calculation. Jurisdiction selects
+	// the configured rule set, the default one when empty. AsOf is an optional
+	// date, formatted with DateFormat, selecting the schedule in effect on
+	// that day instead of blending every schedule of the year. Explain asks
+	// for the step-by-step trace of the calculation. CapitalGains are realized
+	// gains, included in taxable income at the configured inclusion rate, and
+	// AMTCarryForward is the minimum tax credit brought in from prior years.
+	// ResidencyStart and ResidencyEnd bound the days resident in the year for
+	// part-year residents; prorated rules scale by the days resident.
+	// Withheld, Instalments and RefundableCredits are applied to the total
+	// tax to find the balance owing or refund, and PaymentDate estimates the
+	// interest on a balance paid late. InflationRate overrides the indexation
+	// of every projected year when the year is not published yet.
+	// BusinessIncome is net self-employment income, taxed with the income
+	// and subject to the self-employment contribution rule when configured.
+	// Transactions are purchases and sales of securities; the net gain they
+	// realize in the year is added to CapitalGains. Dividends are the
+	// dividends received, grossed up and credited by the dividend rules when
+	// configured.
+	TaxRequest struct {
+		Income            float64       `json:"income"`
+		Year              int           `json:"year"`
+		Jurisdiction      string        `json:"jurisdiction,omitempty"`
+		AsOf              string        `json:"as_of,omitempty"`
+		Explain           bool          `json:"explain,omitempty"`
+		CapitalGains      float64       `json:"capital_gains,omitempty"`
+		AMTCarryForward   float64       `json:"amt_carryforward,omitempty"`
+		ResidencyStart    string        `json:"residency_start,omitempty"`
+		ResidencyEnd      string        `json:"residency_end,omitempty"`
+		Withheld          float64       `json:"withheld,omitempty"`
+		Instalments       float64       `json:"instalments,omitempty"`
+		RefundableCredits float64       `json:"refundable_credits,omitempty"`
+		PaymentDate       string        `json:"payment_date,omitempty"`
+		InflationRate     *float64      `json:"inflation_rate,omitempty"`
+		BusinessIncome    float64       `json:"business_income,omitempty"`
+		Transactions      []Transaction `json:"transactions,omitempty"`
+		Dividends         float64       `json:"dividends,omitempty"`
+	}
+
 	TaxResult struct {
-		TotalTax      float64            `json:"total_tax"`
-		PerBracket    map[string]float64 `json:"per_bracket"`
-		EffectiveRate float64            `json:"effective_rate"`
+		TotalTax       float64               `json:"total_tax"`
+		PerBracket     map[string]float64    `json:"per_bracket"`
+		EffectiveRate  float64               `json:"effective_rate"`
+		MarginalRate   float64               `json:"marginal_rate"`
+		BasicTax       float64               `json:"basic_tax,omitempty"`
+		Lines          []TaxLine             `json:"lines,omitempty"`
+		AMT            *AMTResult            `json:"amt,omitempty"`
+		SelfEmployment *SelfEmploymentResult `json:"self_employment,omitempty"`
+		Residency      *ResidencyResult      `json:"residency,omitempty"`
+		Balance        *BalanceResult        `json:"balance,omitempty"`
+		Ledger         *LedgerYear           `json:"ledger,omitempty"`
+		Projected      bool                  `json:"projected,omitempty"`
+		Projection     *Projection           `json:"projection,omitempty"`
+		Periods        []PeriodResult        `json:"periods,omitempty"`
+		Trace          []TraceStep           `json:"trace,omitempty"`
 	}
 )
+
+// TotalIncome is the income before any inclusion rate or deduction
+func (r TaxRequest) TotalIncome() float64 {
+	return r.Income + r.BusinessIncome + r.CapitalGains + r.Dividends
+}
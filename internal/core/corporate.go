@@ -0,0 +1,97 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrNoCorporateParameters = errors.New("no corporate parameters")
+
+type (
+	// CorporateParameters are the corporate tax parameters of a year. Active
+	// business income up to the business limit is taxed at the small
+	// business rate and the rest at the general rate. Passive income above
+	// the passive threshold grinds the business limit down by GrindRate
+	// dollars per dollar.
+	CorporateParameters struct {
+		SmallBusinessRate float64 `json:"small_business_rate"`
+		GeneralRate       float64 `json:"general_rate"`
+		BusinessLimit     float64 `json:"business_limit"`
+		PassiveThreshold  float64 `json:"passive_threshold"`
+		GrindRate         float64 `json:"grind_rate"`
+		Source            string  `json:"source,omitempty"`
+		Version           string  `json:"version,omitempty"`
+	}
+
+	// CorporateRequest holds the inputs of a corporate calculation.
+	// PassiveIncome is the adjusted aggregate investment income of the
+	// previous year, which grinds the business limit.
+	CorporateRequest struct {
+		Year          int     `json:"year"`
+		ActiveIncome  float64 `json:"active_income"`
+		PassiveIncome float64 `json:"passive_income,omitempty"`
+		Explain       bool    `json:"explain,omitempty"`
+	}
+
+	// CorporateResult is the corporate tax in the shape of TaxResult, with the
+	// business limit left after the passive income grind
+	CorporateResult struct {
+		BusinessLimit float64 `json:"business_limit"`
+		Grind         float64 `json:"grind"`
+		TaxResult
+	}
+)
+
+// Validate checks that the rates are between 0 and 1 and the amounts are
+// non-negative
+func (p CorporateParameters) Validate() error {
+	for _, rate := range []float64{p.SmallBusinessRate, p.GeneralRate} {
+		if err := validateRate(rate); err != nil {
+			return err
+		}
+	}
+	if p.BusinessLimit < 0 || p.PassiveThreshold < 0 || p.GrindRate < 0 {
+		return errors.New("business limit, passive threshold and grind rate must be non-negative")
+	}
+	return nil
+}
+
+// Brackets returns the corporate brackets for a business limit
+func (p CorporateParameters) Brackets(limit float64) []TaxBracket {
+	if limit <= 0 {
+		return []TaxBracket{{Min: 0, Rate: p.GeneralRate}}
+	}
+	return []TaxBracket{
+		{Min: 0, Max: limit, Rate: p.SmallBusinessRate},
+		{Min: limit, Rate: p.GeneralRate},
+	}
+}
+
+// ComputeCorporateTax grinds the business limit by the passive income, then
+// taxes active business income through the corporate brackets
+func ComputeCorporateTax(req CorporateRequest, params CorporateParameters) (CorporateResult, error) {
+	if err := params.Validate(); err != nil {
+		return CorporateResult{}, fmt.Errorf("invalid corporate parameters for %d: %w", req.Year, err)
+	}
+
+	trace := NewTrace(req.Explain)
+	trace.Add(StageInput, "Active business income", fmt.Sprintf("active income = %.2f", req.ActiveIncome), req.ActiveIncome)
+	trace.Add(StageInput, "Passive income", fmt.Sprintf("passive income = %.2f", req.PassiveIncome), req.PassiveIncome)
+	trace.Add(StageInput, "Tax year", fmt.Sprintf("year = %d", req.Year), float64(req.Year))
+
+	grind := math.Min(params.BusinessLimit, math.Max(0, req.PassiveIncome-params.PassiveThreshold)*params.GrindRate)
+	limit := params.BusinessLimit - grind
+	trace.Add(StageDeduction, "Business limit after the passive income grind",
+		fmt.Sprintf("%.2f - min(%.2f, max(0, %.2f - %.2f) × %.2f) = %.2f", params.BusinessLimit, params.BusinessLimit, req.PassiveIncome, params.PassiveThreshold, params.GrindRate, limit), limit)
+
+	result := ComputeTaxTraced(req.ActiveIncome, params.Brackets(limit), trace)
+	trace.Add(StageTotal, "Total tax", fmt.Sprintf("%.2f", result.TotalTax), result.TotalTax)
+	result.Trace = trace.Steps()
+
+	return CorporateResult{
+		BusinessLimit: limit,
+		Grind:         grind,
+		TaxResult:     result,
+	}, nil
+}
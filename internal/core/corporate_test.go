@@ -0,0 +1,98 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeCorporateTax(t *testing.T) {
+	params := CorporateParameters{
+		SmallBusinessRate: 0.09,
+		GeneralRate:       0.15,
+		BusinessLimit:     500000,
+		PassiveThreshold:  50000,
+		GrindRate:         5,
+	}
+
+	tests := []struct {
+		name          string
+		req           CorporateRequest
+		params        CorporateParameters
+		expectedLimit float64
+		expectedGrind float64
+		expectedTotal float64
+		expectedRate  float64
+		expectError   bool
+	}{
+		{
+			name:          "Income below the business limit",
+			req:           CorporateRequest{Year: 2022, ActiveIncome: 300000},
+			params:        params,
+			expectedLimit: 500000,
+			expectedTotal: 27000,
+			expectedRate:  0.09,
+		},
+		{
+			name:          "Income above the business limit",
+			req:           CorporateRequest{Year: 2022, ActiveIncome: 600000},
+			params:        params,
+			expectedLimit: 500000,
+			expectedTotal: 45000 + 15000,
+			expectedRate:  0.15,
+		},
+		{
+			name:          "Passive income grinds the limit",
+			req:           CorporateRequest{Year: 2022, ActiveIncome: 600000, PassiveIncome: 90000},
+			params:        params,
+			expectedLimit: 300000,
+			expectedGrind: 200000,
+			expectedTotal: 27000 + 45000,
+			expectedRate:  0.15,
+		},
+		{
+			name:          "Limit ground to zero",
+			req:           CorporateRequest{Year: 2022, ActiveIncome: 100000, PassiveIncome: 150000},
+			params:        params,
+			expectedLimit: 0,
+			expectedGrind: 500000,
+			expectedTotal: 15000,
+			expectedRate:  0.15,
+		},
+		{
+			name:        "Invalid parameters",
+			req:         CorporateRequest{Year: 2022, ActiveIncome: 100000},
+			params:      CorporateParameters{SmallBusinessRate: 2},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeCorporateTax(tt.req, tt.params)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedLimit, result.BusinessLimit, 0.0001)
+			assert.InDelta(t, tt.expectedGrind, result.Grind, 0.0001)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+			assert.InDelta(t, tt.expectedRate, result.MarginalRate, 0.0001)
+			assert.Nil(t, result.Trace)
+		})
+	}
+}
+
+func TestComputeCorporateTax_Explain(t *testing.T) {
+	params := CorporateParameters{SmallBusinessRate: 0.09, GeneralRate: 0.15, BusinessLimit: 500000}
+
+	result, err := ComputeCorporateTax(CorporateRequest{Year: 2022, ActiveIncome: 600000, Explain: true}, params)
+	assert.NoError(t, err)
+
+	stages := make([]string, len(result.Trace))
+	for i, step := range result.Trace {
+		stages[i] = step.Stage
+	}
+	assert.Equal(t, []string{StageInput, StageInput, StageInput, StageDeduction, StageBracket, StageBracket, StageTotal}, stages)
+}
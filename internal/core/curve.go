@@ -0,0 +1,109 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// MaxCurvePoints caps the number of points a single curve may contain
+const MaxCurvePoints = 10000
+
+var ErrTooManyPoints = errors.New("curve has too many points")
+
+type (
+	// CurveRequest describes the income range to sample. When Breakpoints is
+	// set only the range ends and the kinks of the tax are returned and Step
+	// is ignored. The rules of Jurisdiction apply.
+	CurveRequest struct {
+		Year         int
+		Jurisdiction string
+		From         float64
+		To           float64
+		Step         float64
+		Breakpoints  bool
+	}
+
+	CurvePoint struct {
+		Income        float64 `json:"income"`
+		TotalTax      float64 `json:"total_tax"`
+		EffectiveRate float64 `json:"effective_rate"`
+		MarginalRate  float64 `json:"marginal_rate"`
+	}
+
+	CurveResult struct {
+		Year   int          `json:"year"`
+		Points []CurvePoint `json:"points"`
+	}
+)
+
+// MarginalRate returns the rate applied to the next unit of income
+func MarginalRate(income float64, brackets []TaxBracket) float64 {
+	for _, b := range brackets {
+		if income >= b.Min && (b.Max == 0 || income < b.Max) {
+			return b.Rate
+		}
+	}
+	return 0
+}
+
+// ComputeCurve samples the tax of the year over [From, To]. Kinks inside
+// the range are always included so that straight lines between points are
+// exact. Years whose tax is their brackets alone are evaluated on the
+// compiled brackets, every other year through its pipeline.
+func ComputeCurve(req CurveRequest, year TaxYear) (CurveResult, error) {
+	incomes := []float64{req.From, req.To}
+	for _, k := range year.Kinks() {
+		if k > req.From && k < req.To {
+			incomes = append(incomes, k)
+		}
+	}
+
+	if !req.Breakpoints {
+		steps := math.Floor((req.To - req.From) / req.Step)
+		if steps+float64(len(incomes)) > MaxCurvePoints {
+			return CurveResult{}, ErrTooManyPoints
+		}
+		for i := 1; i <= int(steps); i++ {
+			incomes = append(incomes, req.From+float64(i)*req.Step)
+		}
+	}
+
+	sort.Float64s(incomes)
+
+	point := func(income float64) (CurvePoint, error) {
+		result, err := year.Calculate(TaxRequest{Income: income, Jurisdiction: req.Jurisdiction})
+		if err != nil {
+			return CurvePoint{}, err
+		}
+		return CurvePoint{
+			Income:        income,
+			TotalTax:      result.TotalTax,
+			EffectiveRate: result.EffectiveRate,
+			MarginalRate:  result.MarginalRate,
+		}, nil
+	}
+	if schedule, ok := year.compiled(); ok {
+		point = func(income float64) (CurvePoint, error) {
+			p := CurvePoint{Income: income, TotalTax: schedule.Tax(income), MarginalRate: schedule.MarginalRate(income)}
+			if income > 0 {
+				p.EffectiveRate = p.TotalTax / income
+			}
+			return p, nil
+		}
+	}
+
+	points := make([]CurvePoint, 0, len(incomes))
+	for i, income := range incomes {
+		if i > 0 && income == incomes[i-1] {
+			continue
+		}
+		p, err := point(income)
+		if err != nil {
+			return CurveResult{}, err
+		}
+		points = append(points, p)
+	}
+
+	return CurveResult{Year: req.Year, Points: points}, nil
+}
@@ -0,0 +1,202 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarginalRate(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0.1},
+		{Min: 10000, Max: 50000, Rate: 0.2},
+		{Min: 50000, Max: 0, Rate: 0.3},
+	}
+
+	tests := []struct {
+		name     string
+		income   float64
+		expected float64
+	}{
+		{"Zero income", 0, 0.1},
+		{"Inside first bracket", 9999, 0.1},
+		{"On a boundary", 10000, 0.2},
+		{"Open-ended bracket", 1e6, 0.3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MarginalRate(tt.income, brackets))
+		})
+	}
+}
+
+func TestComputeCurve(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0.1},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, brackets)}, Pipeline: DefaultPipeline()}
+
+	tests := []struct {
+		name            string
+		req             CurveRequest
+		expectedIncomes []float64
+		expectedError   error
+	}{
+		{
+			name:            "Step grid includes kinks",
+			req:             CurveRequest{From: 0, To: 20000, Step: 7500},
+			expectedIncomes: []float64{0, 7500, 10000, 15000, 20000},
+		},
+		{
+			name:            "Breakpoints only",
+			req:             CurveRequest{From: 5000, To: 20000, Breakpoints: true},
+			expectedIncomes: []float64{5000, 10000, 20000},
+		},
+		{
+			name:          "Too many points",
+			req:           CurveRequest{From: 0, To: 1e9, Step: 1},
+			expectedError: ErrTooManyPoints,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeCurve(tt.req, year)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			incomes := make([]float64, len(result.Points))
+			for i, p := range result.Points {
+				incomes[i] = p.Income
+				assert.InDelta(t, ComputeTax(p.Income, brackets).TotalTax, p.TotalTax, 0.0001)
+				assert.Equal(t, MarginalRate(p.Income, brackets), p.MarginalRate)
+			}
+			assert.Equal(t, tt.expectedIncomes, incomes)
+		})
+	}
+}
+
+func TestComputeCurve_Pipeline(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0.1},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	pipeline, err := NewPipeline([]RuleSpec{
+		{Type: RuleBrackets},
+		{Type: RuleClawback, Rate: 0.15, Threshold: 15000},
+	})
+	assert.NoError(t, err)
+	schedules := []TaxSchedule{
+		{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-07-01", Brackets: brackets},
+		{EffectiveFrom: "2022-07-02", EffectiveTo: "2022-12-31", Brackets: []TaxBracket{{Min: 0, Max: 0, Rate: 0.1}}},
+	}
+	year := TaxYear{Year: 2022, Schedules: schedules, Pipeline: pipeline}
+
+	result, err := ComputeCurve(CurveRequest{From: 0, To: 20000, Step: 5000}, year)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Points, 5)
+	for _, p := range result.Points {
+		tax, err := year.Tax(TaxRequest{Income: p.Income})
+		assert.NoError(t, err)
+		assert.InDelta(t, tax, p.TotalTax, 0.0001)
+	}
+	// Above the clawback threshold the clawback adds to the blended bracket rate
+	assert.InDelta(t, (0.2*182+0.1*183)/365+0.15, result.Points[4].MarginalRate, 0.0001)
+}
+
+func TestComputeCurve_RuleKinks(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0.1},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	tests := []struct {
+		name     string
+		specs    []RuleSpec
+		expected []float64
+	}{
+		{
+			name: "Clawback threshold and full recovery",
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleClawback, Rate: 0.15, Threshold: 15000, Amount: 1500},
+			},
+			expected: []float64{0, 10000, 15000, 25000, 40000},
+		},
+		{
+			name: "Surtax tier through the brackets",
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleSurtax, Tiers: []RuleTier{{Threshold: 3000, Rate: 0.2}}},
+			},
+			expected: []float64{0, 10000, 20000, 40000},
+		},
+		{
+			name: "Credit runs out",
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleCredit, Rate: 1, Amount: 2000},
+			},
+			expected: []float64{0, 10000, 15000, 40000},
+		},
+		{
+			name: "Stacked credits run out together",
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleCredit, Name: "basic", Rate: 1, Amount: 500},
+				{Type: RuleCredit, Name: "age", Rate: 0.5, Amount: 3000},
+			},
+			expected: []float64{0, 5000, 10000, 15000, 40000},
+		},
+		{
+			name: "Minimum tax gives way to the regular tax",
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleCredit, Rate: 1, Amount: 1000},
+				{Type: RuleMinimumTax, Rate: 0.1, Threshold: 5000},
+			},
+			expected: []float64{0, 5000, 10000, 15000, 40000},
+		},
+		{
+			name: "AMT gives way to the regular tax",
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleCredit, Rate: 1, Amount: 2000},
+				{Type: RuleAMT, Rate: 0.15, Threshold: 10000},
+			},
+			expected: []float64{0, 10000, 15000, 30000, 40000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline(tt.specs)
+			assert.NoError(t, err)
+			year := TaxYear{Year: 2022, Schedules: []TaxSchedule{{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-12-31", Brackets: brackets}}, Pipeline: pipeline}
+
+			result, err := ComputeCurve(CurveRequest{From: 0, To: 40000, Breakpoints: true}, year)
+
+			assert.NoError(t, err)
+			incomes := make([]float64, len(result.Points))
+			for i, p := range result.Points {
+				incomes[i] = p.Income
+			}
+			assert.InDeltaSlice(t, tt.expected, incomes, 0.01)
+
+			// Straight lines between the points give the tax in between
+			for i := 1; i < len(result.Points); i++ {
+				prev, next := result.Points[i-1], result.Points[i]
+				mid := (prev.Income + next.Income) / 2
+				tax, err := year.Tax(TaxRequest{Income: mid})
+				assert.NoError(t, err)
+				assert.InDelta(t, (prev.TotalTax+next.TotalTax)/2, tax, 0.01)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"fmt"
+	"math"
+)
+
+// Dividend rule types. Without them dividends are taxed in full like other
+// income.
+const (
+	RuleDividendGrossUp = "dividend_gross_up"
+	RuleDividendCredit  = "dividend_credit"
+)
+
+func init() {
+	RegisterRuleType(RuleDividendGrossUp, func(spec RuleSpec) (TaxRule, error) {
+		if spec.Rate < 0 || math.IsNaN(spec.Rate) {
+			return nil, fmt.Errorf("gross-up rate %v must be non-negative", spec.Rate)
+		}
+		return dividendGrossUpRule{name: spec.Name, rate: spec.Rate}, nil
+	})
+	RegisterRuleType(RuleDividendCredit, func(spec RuleSpec) (TaxRule, error) {
+		if err := validateRate(spec.Rate); err != nil {
+			return nil, err
+		}
+		return dividendCreditRule{name: spec.Name, rate: spec.Rate}, nil
+	})
+}
+
+// dividendGrossUpRule adds rate × dividends to taxable income, bringing the
+// dividend back to the corporate income it was paid from
+type dividendGrossUpRule struct {
+	name string
+	rate float64
+}
+
+func (r dividendGrossUpRule) Name() string { return r.name }
+func (r dividendGrossUpRule) Phase() Phase { return PhaseIncome }
+
+func (r dividendGrossUpRule) Apply(c *Computation) {
+	grossUp := c.Request.Dividends * r.rate
+	c.Trace.Addf(StageInput, fmt.Sprintf("%.2f × %.4f = %.2f", c.Request.Dividends, r.rate, grossUp), grossUp,
+		"Dividend gross-up %s", r.name)
+	c.Income += grossUp
+	c.GrossedUpDividends += grossUp
+}
+
+// dividendCreditRule is a non-refundable credit worth rate × the grossed-up
+// dividends, for the tax the corporation already paid, limited to the tax
+// still owing
+type dividendCreditRule struct {
+	name string
+	rate float64
+}
+
+func (r dividendCreditRule) Name() string { return r.name }
+func (r dividendCreditRule) Phase() Phase { return PhaseCredit }
+
+func (r dividendCreditRule) Apply(c *Computation) {
+	credit := math.Min(c.GrossedUpDividends*r.rate, math.Max(0, c.TotalTax))
+	c.Trace.Addf(StageCredit, fmt.Sprintf("min(%.2f × %.4f, %.2f) = %.2f", c.GrossedUpDividends, r.rate, c.TotalTax, credit), -credit,
+		"Dividend tax credit %s", r.name)
+	c.AddLine(r, RuleDividendCredit, -credit)
+}
+
+// Kinks returns the taxable income at which the tax still owing before the
+// credit equals it, where the credit runs out. The credit follows the
+// dividends, so a taxpayer with income alone has none.
+func (r dividendCreditRule) Kinks(k KinkContext) []float64 {
+	return crossings(k.Kinks, func(income float64) float64 {
+		c := k.Before(income)
+		return c.TotalTax - c.GrossedUpDividends*r.rate
+	})
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDividendRules(t *testing.T) {
+	brackets := []TaxBracket{{Min: 0, Max: 0, Rate: 0.2}}
+
+	tests := []struct {
+		name          string
+		specs         []RuleSpec
+		req           TaxRequest
+		expectedTotal float64
+		expectedError string
+	}{
+		{
+			name:          "Dividends taxed in full without rules",
+			specs:         []RuleSpec{{Type: RuleBrackets}},
+			req:           TaxRequest{Dividends: 10000},
+			expectedTotal: 2000,
+		},
+		{
+			name: "Grossed up and credited",
+			specs: []RuleSpec{
+				{Type: RuleDividendGrossUp, Rate: 0.38},
+				{Type: RuleBrackets},
+				{Type: RuleDividendCredit, Rate: 0.15},
+			},
+			req: TaxRequest{Dividends: 10000},
+			// 13800 × 0.2 - 13800 × 0.15
+			expectedTotal: 2760 - 2070,
+		},
+		{
+			name: "Credit limited to the tax owing",
+			specs: []RuleSpec{
+				{Type: RuleDividendGrossUp, Rate: 0.38},
+				{Type: RuleBrackets},
+				{Type: RuleDividendCredit, Rate: 0.3},
+			},
+			req:           TaxRequest{Dividends: 10000},
+			expectedTotal: 0,
+		},
+		{
+			name:          "Negative gross-up",
+			specs:         []RuleSpec{{Type: RuleDividendGrossUp, Rate: -0.1}},
+			expectedError: "gross-up rate -0.1 must be non-negative",
+		},
+		{
+			name:          "Invalid credit rate",
+			specs:         []RuleSpec{{Type: RuleDividendCredit, Rate: 1.5}},
+			expectedError: "rate 1.5 must be between 0 and 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline(tt.specs)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			result := pipeline.Compute(tt.req, brackets, 1, nil)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+		})
+	}
+}
+
+func TestDividendCreditRule_Kinks(t *testing.T) {
+	brackets := []TaxBracket{{Min: 0, Max: 10000, Rate: 0.1}, {Min: 10000, Max: 0, Rate: 0.2}}
+	rule := dividendCreditRule{name: "dividend", rate: 0.15}
+
+	tests := []struct {
+		name      string
+		grossedUp float64
+		expected  []float64
+	}{
+		// 13800 × 0.15 = 2070, owed at 10000 + 1070 / 0.2
+		{name: "Credit runs out above a bracket boundary", grossedUp: 13800, expected: []float64{15350}},
+		{name: "No dividends, no credit", grossedUp: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := CompileBrackets(brackets)
+			assert.NoError(t, err)
+
+			kinks := rule.Kinks(KinkContext{
+				Brackets: brackets,
+				Kinks:    Kinks(brackets),
+				Before: func(income float64) *Computation {
+					return &Computation{Income: income, GrossedUpDividends: tt.grossedUp, TotalTax: schedule.Tax(income)}
+				},
+			})
+
+			assert.InDeltaSlice(t, tt.expected, kinks, 0.001)
+		})
+	}
+}
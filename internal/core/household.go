@@ -0,0 +1,132 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+type (
+	HouseholdMember struct {
+		Name   string  `json:"name,omitempty"`
+		Income float64 `json:"income"`
+	}
+
+	// HouseholdRequest describes a couple. Splittable is the eligible
+	// pension income of the first member that can be allocated to the
+	// second.
+	HouseholdRequest struct {
+		Year         int               `json:"year"`
+		Jurisdiction string            `json:"jurisdiction,omitempty"`
+		Members      []HouseholdMember `json:"members"`
+		Splittable   float64           `json:"splittable"`
+	}
+
+	HouseholdMemberResult struct {
+		Name        string  `json:"name,omitempty"`
+		Income      float64 `json:"income"`
+		SplitIncome float64 `json:"split_income"`
+		TaxResult
+	}
+
+	// HouseholdResult is the allocation of the splittable income that
+	// minimizes the combined tax, with each member's tax under it
+	HouseholdResult struct {
+		Allocated       float64                 `json:"allocated"`
+		Members         []HouseholdMemberResult `json:"members"`
+		CombinedTax     float64                 `json:"combined_tax"`
+		TaxWithoutSplit float64                 `json:"tax_without_split"`
+		Saving          float64                 `json:"saving"`
+		Projection      *Projection             `json:"projection,omitempty"`
+	}
+)
+
+// ComputeHousehold finds the amount of splittable income to allocate from
+// the first member to the second that minimizes the combined tax. Between
+// two kinks of the year, bracket boundaries, rule thresholds and the points
+// where credits run out or minimum taxes take over alike, the combined tax
+// is linear in the amount, so only the amounts moving either member onto a
+// kink, and both ends of the range, need to be evaluated. Ties keep the
+// smaller allocation.
+func ComputeHousehold(req HouseholdRequest, year TaxYear) (HouseholdResult, error) {
+	if len(req.Members) != 2 {
+		return HouseholdResult{}, fmt.Errorf("a household has two members, got %d", len(req.Members))
+	}
+	first, second := req.Members[0], req.Members[1]
+	splittable := math.Min(req.Splittable, first.Income)
+
+	combined := func(amount float64) (float64, error) {
+		a, err := year.Tax(TaxRequest{Income: first.Income - amount, Jurisdiction: req.Jurisdiction})
+		if err != nil {
+			return 0, err
+		}
+		b, err := year.Tax(TaxRequest{Income: second.Income + amount, Jurisdiction: req.Jurisdiction})
+		if err != nil {
+			return 0, err
+		}
+		return a + b, nil
+	}
+
+	without, err := combined(0)
+	if err != nil {
+		return HouseholdResult{}, err
+	}
+
+	best, bestTax := 0.0, without
+	for _, amount := range splitCandidates(year, first.Income, second.Income, splittable) {
+		tax, err := combined(amount)
+		if err != nil {
+			return HouseholdResult{}, err
+		}
+		if tax < bestTax-1e-9 {
+			best, bestTax = amount, tax
+		}
+	}
+
+	result := HouseholdResult{
+		Allocated:       best,
+		CombinedTax:     bestTax,
+		TaxWithoutSplit: without,
+		Saving:          without - bestTax,
+		Projection:      year.Projection,
+	}
+	for _, member := range []struct {
+		HouseholdMember
+		split float64
+	}{{first, first.Income - best}, {second, second.Income + best}} {
+		tax, err := year.Calculate(TaxRequest{Income: member.split, Jurisdiction: req.Jurisdiction})
+		if err != nil {
+			return HouseholdResult{}, err
+		}
+		result.Members = append(result.Members, HouseholdMemberResult{
+			Name:        member.Name,
+			Income:      member.Income,
+			SplitIncome: member.split,
+			TaxResult:   tax,
+		})
+	}
+
+	return result, nil
+}
+
+// splitCandidates returns the sorted allocations between 0 and splittable
+// that put either member on a kink, plus both ends
+func splitCandidates(year TaxYear, first, second, splittable float64) []float64 {
+	seen := map[float64]bool{}
+	var candidates []float64
+	add := func(amount float64) {
+		if amount >= 0 && amount <= splittable && !seen[amount] {
+			seen[amount] = true
+			candidates = append(candidates, amount)
+		}
+	}
+
+	add(0)
+	add(splittable)
+	for _, kink := range year.Kinks() {
+		add(first - kink)
+		add(kink - second)
+	}
+	sort.Float64s(candidates)
+	return candidates
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeHousehold(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 50000, Rate: 0.2},
+		{Min: 50000, Max: 0, Rate: 0.4},
+	}
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, brackets)}, Pipeline: DefaultPipeline()}
+
+	tests := []struct {
+		name              string
+		req               HouseholdRequest
+		expectedAllocated float64
+		expectedCombined  float64
+		expectedWithout   float64
+		expectError       bool
+	}{
+		{
+			name: "Allocate down to the top bracket boundary",
+			req: HouseholdRequest{
+				Members:    []HouseholdMember{{Name: "a", Income: 80000}, {Name: "b", Income: 0}},
+				Splittable: 40000,
+			},
+			// Every dollar moved out of a's 40% band saves tax; below it both
+			// members are taxed at 20% and moving more is a wash
+			expectedAllocated: 30000,
+			expectedCombined:  8000 + 4000,
+			expectedWithout:   20000,
+		},
+		{
+			name: "Splitting is limited to the splittable amount",
+			req: HouseholdRequest{
+				Members:    []HouseholdMember{{Name: "a", Income: 80000}, {Name: "b", Income: 0}},
+				Splittable: 5000,
+			},
+			expectedAllocated: 5000,
+			expectedCombined:  18000,
+			expectedWithout:   20000,
+		},
+		{
+			name: "No allocation when the second member pays more",
+			req: HouseholdRequest{
+				Members:    []HouseholdMember{{Income: 20000}, {Income: 90000}},
+				Splittable: 10000,
+			},
+			expectedAllocated: 0,
+			expectedCombined:  2000 + 24000,
+			expectedWithout:   2000 + 24000,
+		},
+		{
+			name: "No allocation when both members are in the same band",
+			req: HouseholdRequest{
+				Members:    []HouseholdMember{{Income: 20000}, {Income: 30000}},
+				Splittable: 10000,
+			},
+			expectedAllocated: 0,
+			expectedCombined:  2000 + 4000,
+			expectedWithout:   2000 + 4000,
+		},
+		{
+			name:        "Household needs two members",
+			req:         HouseholdRequest{Members: []HouseholdMember{{Income: 20000}}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeHousehold(tt.req, year)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedAllocated, result.Allocated, 0.0001)
+			assert.InDelta(t, tt.expectedCombined, result.CombinedTax, 0.0001)
+			assert.InDelta(t, tt.expectedWithout, result.TaxWithoutSplit, 0.0001)
+			assert.InDelta(t, tt.expectedWithout-tt.expectedCombined, result.Saving, 0.0001)
+			assert.Len(t, result.Members, 2)
+			assert.InDelta(t, result.CombinedTax, result.Members[0].TotalTax+result.Members[1].TotalTax, 0.0001)
+		})
+	}
+}
+
+func TestComputeHousehold_Rules(t *testing.T) {
+	tests := []struct {
+		name              string
+		brackets          []TaxBracket
+		specs             []RuleSpec
+		req               HouseholdRequest
+		expectedAllocated float64
+		expectedCombined  float64
+		expectedWithout   float64
+	}{
+		{
+			// Above the clawback threshold a dollar costs 25%, below it 10%,
+			// so the best split keeps both members at the threshold
+			name:     "Clawback threshold",
+			brackets: []TaxBracket{{Min: 0, Max: 60000, Rate: 0.1}, {Min: 60000, Max: 0, Rate: 0.3}},
+			specs:    []RuleSpec{{Type: RuleBrackets}, {Type: RuleClawback, Rate: 0.15, Threshold: 50000}},
+			req: HouseholdRequest{
+				Members:    []HouseholdMember{{Income: 100000}, {Income: 0}},
+				Splittable: 100000,
+			},
+			expectedAllocated: 50000,
+			expectedCombined:  5000 + 5000,
+			expectedWithout:   6000 + 12000 + 7500,
+		},
+		{
+			name:     "Splittable income only moves from the first member",
+			brackets: []TaxBracket{{Min: 0, Max: 60000, Rate: 0.1}, {Min: 60000, Max: 0, Rate: 0.3}},
+			specs:    []RuleSpec{{Type: RuleBrackets}, {Type: RuleClawback, Rate: 0.15, Threshold: 50000}},
+			req: HouseholdRequest{
+				Members:    []HouseholdMember{{Income: 0}, {Income: 100000}},
+				Splittable: 100000,
+			},
+			expectedAllocated: 0,
+			expectedCombined:  6000 + 12000 + 7500,
+			expectedWithout:   6000 + 12000 + 7500,
+		},
+		{
+			// The second member's credit is wasted until their tax reaches
+			// it, which takes 10000 of income
+			name:     "Use the second member's credit",
+			brackets: []TaxBracket{{Min: 0, Max: 50000, Rate: 0.2}, {Min: 50000, Max: 0, Rate: 0.4}},
+			specs:    []RuleSpec{{Type: RuleBrackets}, {Type: RuleCredit, Rate: 1, Amount: 2000}},
+			req: HouseholdRequest{
+				Members:    []HouseholdMember{{Income: 40000}, {Income: 0}},
+				Splittable: 40000,
+			},
+			expectedAllocated: 10000,
+			expectedCombined:  4000 + 0,
+			expectedWithout:   6000 + 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline(tt.specs)
+			assert.NoError(t, err)
+			year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, tt.brackets)}, Pipeline: pipeline}
+
+			result, err := ComputeHousehold(tt.req, year)
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedAllocated, result.Allocated, 0.0001)
+			assert.InDelta(t, tt.expectedCombined, result.CombinedTax, 0.0001)
+			assert.InDelta(t, tt.expectedWithout, result.TaxWithoutSplit, 0.0001)
+			assert.InDelta(t, tt.expectedWithout-tt.expectedCombined, result.Saving, 0.0001)
+		})
+	}
+}
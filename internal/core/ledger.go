@@ -0,0 +1,253 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Transaction types
+const (
+	TransactionBuy  = "buy"
+	TransactionSell = "sell"
+)
+
+// SuperficialLossDays is how many days before and after a sale at a loss a
+// purchase of the same security makes the loss superficial
+const SuperficialLossDays = 30
+
+// ErrInsufficientUnits is returned when a sale exceeds the units held
+var ErrInsufficientUnits = errors.New("insufficient units")
+
+type (
+	// Transaction is a purchase or sale of a security on a date formatted
+	// with DateFormat. Commission adds to the cost of a purchase and reduces
+	// the proceeds of a sale.
+	Transaction struct {
+		Date       string  `json:"date"`
+		Security   string  `json:"security"`
+		Type       string  `json:"type"`
+		Quantity   float64 `json:"quantity"`
+		Price      float64 `json:"price"`
+		Commission float64 `json:"commission,omitempty"`
+	}
+
+	// Disposition is the gain or loss realized by a sale. DeniedLoss is the
+	// part of a loss denied as superficial and added to the cost of the
+	// units still held.
+	Disposition struct {
+		Date       string  `json:"date"`
+		Security   string  `json:"security"`
+		Quantity   float64 `json:"quantity"`
+		Proceeds   float64 `json:"proceeds"`
+		Cost       float64 `json:"cost"`
+		Gain       float64 `json:"gain"`
+		DeniedLoss float64 `json:"denied_loss,omitempty"`
+	}
+
+	// LedgerYear sums the dispositions of a tax year. Gains and Losses are
+	// both positive; NetGain is their difference and is negative for a net
+	// capital loss.
+	LedgerYear struct {
+		Year         int     `json:"year"`
+		Gains        float64 `json:"gains"`
+		Losses       float64 `json:"losses"`
+		DeniedLosses float64 `json:"denied_losses,omitempty"`
+		NetGain      float64 `json:"net_gain"`
+	}
+
+	// Position is what is left of a security after every transaction, with
+	// its adjusted cost base
+	Position struct {
+		Security   string  `json:"security"`
+		Quantity   float64 `json:"quantity"`
+		ACB        float64 `json:"acb"`
+		ACBPerUnit float64 `json:"acb_per_unit"`
+	}
+
+	// LedgerRequest holds the transactions of a ledger, in any order
+	LedgerRequest struct {
+		Transactions []Transaction `json:"transactions"`
+	}
+
+	// LedgerResult is the ledger of a list of transactions
+	LedgerResult struct {
+		Dispositions []Disposition `json:"dispositions"`
+		Years        []LedgerYear  `json:"years"`
+		Positions    []Position    `json:"positions"`
+	}
+
+	// ledgerEntry is a transaction with its parsed date
+	ledgerEntry struct {
+		Transaction
+		date time.Time
+	}
+)
+
+// Year returns the totals of a tax year, zero when nothing was sold in it
+func (l LedgerResult) Year(year int) LedgerYear {
+	for _, y := range l.Years {
+		if y.Year == year {
+			return y
+		}
+	}
+	return LedgerYear{Year: year}
+}
+
+// ComputeLedger tracks the adjusted cost base of every security with the
+// average-cost method and realizes a gain or loss on every sale. A loss is
+// superficial, and denied, in proportion to the units bought within
+// SuperficialLossDays of the sale and still held at the end of that window;
+// the denied loss is added to the cost of the units held.
+func ComputeLedger(transactions []Transaction) (LedgerResult, error) {
+	entries, err := ledgerEntries(transactions)
+	if err != nil {
+		return LedgerResult{}, err
+	}
+
+	type pool struct {
+		quantity, acb float64
+		// deferred is a denied loss waiting for the units it belongs to
+		deferred float64
+	}
+	pools := map[string]*pool{}
+	years := map[int]*LedgerYear{}
+
+	result := LedgerResult{Dispositions: []Disposition{}, Years: []LedgerYear{}, Positions: []Position{}}
+	for _, e := range entries {
+		p, ok := pools[e.Security]
+		if !ok {
+			p = &pool{}
+			pools[e.Security] = p
+		}
+
+		if e.Type == TransactionBuy {
+			p.quantity += e.Quantity
+			p.acb += e.Quantity*e.Price + e.Commission + p.deferred
+			p.deferred = 0
+			continue
+		}
+
+		if e.Quantity > p.quantity+1e-9 {
+			return LedgerResult{}, fmt.Errorf("%w: sale of %g %s on %s exceeds the %g units held", ErrInsufficientUnits, e.Quantity, e.Security, e.Date, p.quantity)
+		}
+
+		cost := p.acb * e.Quantity / p.quantity
+		d := Disposition{
+			Date:     e.Date,
+			Security: e.Security,
+			Quantity: e.Quantity,
+			Proceeds: e.Quantity*e.Price - e.Commission,
+			Cost:     cost,
+		}
+		d.Gain = d.Proceeds - d.Cost
+
+		p.quantity -= e.Quantity
+		p.acb -= cost
+		if p.quantity <= 1e-9 {
+			p.quantity, p.acb = 0, 0
+		}
+
+		if d.Gain < 0 {
+			d.DeniedLoss = -d.Gain * superficialShare(e, entries)
+			d.Gain += d.DeniedLoss
+			if p.quantity > 0 {
+				p.acb += d.DeniedLoss
+			} else {
+				p.deferred += d.DeniedLoss
+			}
+		}
+		result.Dispositions = append(result.Dispositions, d)
+
+		y, ok := years[e.date.Year()]
+		if !ok {
+			y = &LedgerYear{Year: e.date.Year()}
+			years[e.date.Year()] = y
+		}
+		if d.Gain > 0 {
+			y.Gains += d.Gain
+		} else {
+			y.Losses -= d.Gain
+		}
+		y.DeniedLosses += d.DeniedLoss
+		y.NetGain = y.Gains - y.Losses
+	}
+
+	for _, y := range years {
+		result.Years = append(result.Years, *y)
+	}
+	sort.Slice(result.Years, func(i, j int) bool { return result.Years[i].Year < result.Years[j].Year })
+
+	for security, p := range pools {
+		if p.quantity == 0 {
+			continue
+		}
+		result.Positions = append(result.Positions, Position{
+			Security:   security,
+			Quantity:   p.quantity,
+			ACB:        p.acb,
+			ACBPerUnit: p.acb / p.quantity,
+		})
+	}
+	sort.Slice(result.Positions, func(i, j int) bool { return result.Positions[i].Security < result.Positions[j].Security })
+
+	return result, nil
+}
+
+// superficialShare is the share of a sale's loss that is superficial: the
+// least of the units sold, the units bought within the window around the
+// sale and the units held at its end, over the units sold
+func superficialShare(sale ledgerEntry, entries []ledgerEntry) float64 {
+	start := sale.date.AddDate(0, 0, -SuperficialLossDays)
+	end := sale.date.AddDate(0, 0, SuperficialLossDays)
+
+	var bought, held float64
+	for _, e := range entries {
+		if e.Security != sale.Security || e.date.After(end) {
+			continue
+		}
+		if e.Type == TransactionBuy {
+			held += e.Quantity
+			if !e.date.Before(start) {
+				bought += e.Quantity
+			}
+		} else {
+			held -= e.Quantity
+		}
+	}
+
+	return math.Max(0, math.Min(sale.Quantity, math.Min(bought, held))) / sale.Quantity
+}
+
+// ValidateTransactions checks the dates, types and amounts of the
+// transactions, but not that the units sold are held
+func ValidateTransactions(transactions []Transaction) error {
+	_, err := ledgerEntries(transactions)
+	return err
+}
+
+// ledgerEntries validates the transactions and sorts them by date. Purchases
+// and sales on the same day keep their order.
+func ledgerEntries(transactions []Transaction) ([]ledgerEntry, error) {
+	entries := make([]ledgerEntry, 0, len(transactions))
+	for i, t := range transactions {
+		date, err := time.Parse(DateFormat, t.Date)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: invalid date %q: %w", i+1, t.Date, err)
+		}
+		if t.Security == "" {
+			return nil, fmt.Errorf("transaction %d: missing security", i+1)
+		}
+		if t.Type != TransactionBuy && t.Type != TransactionSell {
+			return nil, fmt.Errorf("transaction %d: unknown type %q", i+1, t.Type)
+		}
+		if t.Quantity <= 0 || t.Price < 0 || t.Commission < 0 {
+			return nil, fmt.Errorf("transaction %d: quantity must be positive, price and commission non-negative", i+1)
+		}
+		entries = append(entries, ledgerEntry{Transaction: t, date: date})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].date.Before(entries[j].date) })
+	return entries, nil
+}
@@ -0,0 +1,192 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeLedger(t *testing.T) {
+	tests := []struct {
+		name                 string
+		transactions         []Transaction
+		expectedDispositions []Disposition
+		expectedYears        []LedgerYear
+		expectedPositions    []Position
+		expectedError        string
+	}{
+		{
+			name: "Average cost across purchases",
+			transactions: []Transaction{
+				{Date: "2022-01-10", Security: "XYZ", Type: TransactionBuy, Quantity: 100, Price: 10, Commission: 10},
+				{Date: "2022-02-10", Security: "XYZ", Type: TransactionBuy, Quantity: 100, Price: 12, Commission: 10},
+				{Date: "2022-06-10", Security: "XYZ", Type: TransactionSell, Quantity: 150, Price: 15, Commission: 10},
+			},
+			expectedDispositions: []Disposition{
+				{Date: "2022-06-10", Security: "XYZ", Quantity: 150, Proceeds: 2240, Cost: 1665, Gain: 575},
+			},
+			expectedYears:     []LedgerYear{{Year: 2022, Gains: 575, NetGain: 575}},
+			expectedPositions: []Position{{Security: "XYZ", Quantity: 50, ACB: 555, ACBPerUnit: 11.1}},
+		},
+		{
+			name: "Superficial loss on a repurchase after the sale",
+			transactions: []Transaction{
+				{Date: "2022-01-10", Security: "XYZ", Type: TransactionBuy, Quantity: 100, Price: 50},
+				{Date: "2022-03-01", Security: "XYZ", Type: TransactionSell, Quantity: 100, Price: 40},
+				{Date: "2022-03-15", Security: "XYZ", Type: TransactionBuy, Quantity: 100, Price: 41},
+			},
+			expectedDispositions: []Disposition{
+				{Date: "2022-03-01", Security: "XYZ", Quantity: 100, Proceeds: 4000, Cost: 5000, Gain: 0, DeniedLoss: 1000},
+			},
+			expectedYears:     []LedgerYear{{Year: 2022, DeniedLosses: 1000}},
+			expectedPositions: []Position{{Security: "XYZ", Quantity: 100, ACB: 5100, ACBPerUnit: 51}},
+		},
+		{
+			name: "Partly superficial loss",
+			transactions: []Transaction{
+				{Date: "2022-01-10", Security: "XYZ", Type: TransactionBuy, Quantity: 100, Price: 50},
+				{Date: "2022-03-01", Security: "XYZ", Type: TransactionSell, Quantity: 100, Price: 40},
+				{Date: "2022-03-10", Security: "XYZ", Type: TransactionBuy, Quantity: 40, Price: 41},
+			},
+			expectedDispositions: []Disposition{
+				{Date: "2022-03-01", Security: "XYZ", Quantity: 100, Proceeds: 4000, Cost: 5000, Gain: -600, DeniedLoss: 400},
+			},
+			expectedYears:     []LedgerYear{{Year: 2022, Losses: 600, DeniedLosses: 400, NetGain: -600}},
+			expectedPositions: []Position{{Security: "XYZ", Quantity: 40, ACB: 2040, ACBPerUnit: 51}},
+		},
+		{
+			name: "Purchase before the sale with the units still held",
+			transactions: []Transaction{
+				{Date: "2022-01-10", Security: "XYZ", Type: TransactionBuy, Quantity: 100, Price: 50},
+				{Date: "2022-02-20", Security: "XYZ", Type: TransactionBuy, Quantity: 100, Price: 40},
+				{Date: "2022-03-01", Security: "XYZ", Type: TransactionSell, Quantity: 100, Price: 40},
+			},
+			expectedDispositions: []Disposition{
+				{Date: "2022-03-01", Security: "XYZ", Quantity: 100, Proceeds: 4000, Cost: 4500, Gain: 0, DeniedLoss: 500},
+			},
+			expectedYears:     []LedgerYear{{Year: 2022, DeniedLosses: 500}},
+			expectedPositions: []Position{{Security: "XYZ", Quantity: 100, ACB: 5000, ACBPerUnit: 50}},
+		},
+		{
+			name: "Repurchase after the window keeps the loss",
+			transactions: []Transaction{
+				{Date: "2022-01-10", Security: "XYZ", Type: TransactionBuy, Quantity: 100, Price: 50},
+				{Date: "2022-03-01", Security: "XYZ", Type: TransactionSell, Quantity: 100, Price: 40},
+				{Date: "2022-04-01", Security: "XYZ", Type: TransactionBuy, Quantity: 100, Price: 41},
+			},
+			expectedDispositions: []Disposition{
+				{Date: "2022-03-01", Security: "XYZ", Quantity: 100, Proceeds: 4000, Cost: 5000, Gain: -1000},
+			},
+			expectedYears:     []LedgerYear{{Year: 2022, Losses: 1000, NetGain: -1000}},
+			expectedPositions: []Position{{Security: "XYZ", Quantity: 100, ACB: 4100, ACBPerUnit: 41}},
+		},
+		{
+			name: "Gains split by tax year and security",
+			transactions: []Transaction{
+				{Date: "2021-12-30", Security: "ABC", Type: TransactionSell, Quantity: 10, Price: 30},
+				{Date: "2021-01-04", Security: "ABC", Type: TransactionBuy, Quantity: 20, Price: 20},
+				{Date: "2021-02-01", Security: "XYZ", Type: TransactionBuy, Quantity: 10, Price: 100},
+				{Date: "2022-01-03", Security: "ABC", Type: TransactionSell, Quantity: 10, Price: 25},
+				{Date: "2022-05-01", Security: "XYZ", Type: TransactionSell, Quantity: 10, Price: 90},
+			},
+			expectedDispositions: []Disposition{
+				{Date: "2021-12-30", Security: "ABC", Quantity: 10, Proceeds: 300, Cost: 200, Gain: 100},
+				{Date: "2022-01-03", Security: "ABC", Quantity: 10, Proceeds: 250, Cost: 200, Gain: 50},
+				{Date: "2022-05-01", Security: "XYZ", Quantity: 10, Proceeds: 900, Cost: 1000, Gain: -100},
+			},
+			expectedYears: []LedgerYear{
+				{Year: 2021, Gains: 100, NetGain: 100},
+				{Year: 2022, Gains: 50, Losses: 100, NetGain: -50},
+			},
+			expectedPositions: []Position{},
+		},
+		{
+			name: "Sale of more units than held",
+			transactions: []Transaction{
+				{Date: "2022-01-10", Security: "XYZ", Type: TransactionBuy, Quantity: 10, Price: 50},
+				{Date: "2022-03-01", Security: "XYZ", Type: TransactionSell, Quantity: 20, Price: 40},
+			},
+			expectedError: "exceeds the 10 units held",
+		},
+		{
+			name:          "Unknown transaction type",
+			transactions:  []Transaction{{Date: "2022-01-10", Security: "XYZ", Type: "gift", Quantity: 10, Price: 50}},
+			expectedError: "unknown type",
+		},
+		{
+			name:          "Invalid date",
+			transactions:  []Transaction{{Date: "2022-02-30", Security: "XYZ", Type: TransactionBuy, Quantity: 10, Price: 50}},
+			expectedError: "invalid date",
+		},
+		{
+			name:          "Non-positive quantity",
+			transactions:  []Transaction{{Date: "2022-01-10", Security: "XYZ", Type: TransactionBuy, Price: 50}},
+			expectedError: "quantity must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeLedger(tt.transactions)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, result.Dispositions, len(tt.expectedDispositions))
+			for i, d := range tt.expectedDispositions {
+				assert.Equal(t, d.Date, result.Dispositions[i].Date)
+				assert.Equal(t, d.Security, result.Dispositions[i].Security)
+				assert.InDelta(t, d.Proceeds, result.Dispositions[i].Proceeds, 0.0001)
+				assert.InDelta(t, d.Cost, result.Dispositions[i].Cost, 0.0001)
+				assert.InDelta(t, d.Gain, result.Dispositions[i].Gain, 0.0001)
+				assert.InDelta(t, d.DeniedLoss, result.Dispositions[i].DeniedLoss, 0.0001)
+			}
+			assert.Len(t, result.Years, len(tt.expectedYears))
+			for i, y := range tt.expectedYears {
+				assert.Equal(t, y.Year, result.Years[i].Year)
+				assert.InDelta(t, y.Gains, result.Years[i].Gains, 0.0001)
+				assert.InDelta(t, y.Losses, result.Years[i].Losses, 0.0001)
+				assert.InDelta(t, y.DeniedLosses, result.Years[i].DeniedLosses, 0.0001)
+				assert.InDelta(t, y.NetGain, result.Years[i].NetGain, 0.0001)
+			}
+			assert.Len(t, result.Positions, len(tt.expectedPositions))
+			for i, p := range tt.expectedPositions {
+				assert.Equal(t, p.Security, result.Positions[i].Security)
+				assert.InDelta(t, p.Quantity, result.Positions[i].Quantity, 0.0001)
+				assert.InDelta(t, p.ACB, result.Positions[i].ACB, 0.0001)
+				assert.InDelta(t, p.ACBPerUnit, result.Positions[i].ACBPerUnit, 0.0001)
+			}
+		})
+	}
+}
+
+func TestCalculate_Ledger(t *testing.T) {
+	schedules := []TaxSchedule{WholeYearSchedule(2022, []TaxBracket{{Min: 0, Rate: 0.1}})}
+	transactions := []Transaction{
+		{Date: "2021-01-04", Security: "XYZ", Type: TransactionBuy, Quantity: 100, Price: 10},
+		{Date: "2021-06-01", Security: "XYZ", Type: TransactionSell, Quantity: 50, Price: 20},
+		{Date: "2022-06-01", Security: "XYZ", Type: TransactionSell, Quantity: 50, Price: 30},
+	}
+
+	result, err := Calculate(TaxRequest{Income: 10000, Year: 2022, Transactions: transactions}, schedules, DefaultPipeline(), nil)
+	assert.NoError(t, err)
+	// Only the 2022 gain of 1000 is added to the income
+	assert.InDelta(t, 1100, result.TotalTax, 0.0001)
+	assert.Equal(t, &LedgerYear{Year: 2022, Gains: 1000, NetGain: 1000}, result.Ledger)
+
+	// A net capital loss does not reduce other income
+	losing := []Transaction{
+		{Date: "2022-01-04", Security: "XYZ", Type: TransactionBuy, Quantity: 100, Price: 10},
+		{Date: "2022-06-01", Security: "XYZ", Type: TransactionSell, Quantity: 100, Price: 5},
+	}
+	result, err = Calculate(TaxRequest{Income: 10000, Year: 2022, Transactions: losing}, schedules, DefaultPipeline(), nil)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1000, result.TotalTax, 0.0001)
+	assert.InDelta(t, -500, result.Ledger.NetGain, 0.0001)
+
+	_, err = Calculate(TaxRequest{Income: 10000, Year: 2022, Transactions: losing[1:]}, schedules, DefaultPipeline(), nil)
+	assert.Error(t, err)
+}
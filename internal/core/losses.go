@@ -0,0 +1,299 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Kinds of losses
+const (
+	LossNonCapital = "non_capital"
+	LossNetCapital = "net_capital"
+)
+
+type (
+	// LossYear is the income of one year and the losses that arose in it.
+	// Non-capital losses are deducted from income and net capital losses
+	// from capital gains, before the inclusion rate.
+	LossYear struct {
+		Year           int     `json:"year"`
+		Income         float64 `json:"income"`
+		CapitalGains   float64 `json:"capital_gains,omitempty"`
+		NonCapitalLoss float64 `json:"non_capital_loss,omitempty"`
+		NetCapitalLoss float64 `json:"net_capital_loss,omitempty"`
+	}
+
+	LossRequest struct {
+		Jurisdiction string     `json:"jurisdiction,omitempty"`
+		Years        []LossYear `json:"years"`
+	}
+
+	// CarryRule is how many years losses arising in Year can be carried back
+	// and forward. A negative number of years carries indefinitely.
+	CarryRule struct {
+		Year              int `mapstructure:"year" json:"year"`
+		NonCapitalBack    int `mapstructure:"nonCapitalBack" json:"non_capital_back"`
+		NonCapitalForward int `mapstructure:"nonCapitalForward" json:"non_capital_forward"`
+		CapitalBack       int `mapstructure:"capitalBack" json:"capital_back"`
+		CapitalForward    int `mapstructure:"capitalForward" json:"capital_forward"`
+	}
+
+	// LossApplication is an amount of a loss deducted in another year
+	LossApplication struct {
+		LossYear  int     `json:"loss_year"`
+		Kind      string  `json:"kind"`
+		AppliedTo int     `json:"applied_to"`
+		Amount    float64 `json:"amount"`
+		Saving    float64 `json:"saving"`
+	}
+
+	// LossBalance is what remains of a loss after every application.
+	// ExpiresAfter is the last year it can be used, 0 when it never expires.
+	LossBalance struct {
+		LossYear     int     `json:"loss_year"`
+		Kind         string  `json:"kind"`
+		Amount       float64 `json:"amount"`
+		Applied      float64 `json:"applied"`
+		Remaining    float64 `json:"remaining"`
+		ExpiresAfter int     `json:"expires_after,omitempty"`
+	}
+
+	LossYearResult struct {
+		Year                int         `json:"year"`
+		NonCapitalDeduction float64     `json:"non_capital_deduction"`
+		NetCapitalDeduction float64     `json:"net_capital_deduction"`
+		TaxBeforeLosses     float64     `json:"tax_before_losses"`
+		TaxAfterLosses      float64     `json:"tax_after_losses"`
+		Projection          *Projection `json:"projection,omitempty"`
+	}
+
+	LossResult struct {
+		Years           []LossYearResult  `json:"years"`
+		Applications    []LossApplication `json:"applications"`
+		Balances        []LossBalance     `json:"balances"`
+		TaxBeforeLosses float64           `json:"tax_before_losses"`
+		TaxAfterLosses  float64           `json:"tax_after_losses"`
+		Saving          float64           `json:"saving"`
+	}
+)
+
+// DefaultCarryRule applies to years without a configured rule: non-capital
+// losses carry back 3 years and forward 20, net capital losses back 3 years
+// and forward indefinitely
+var DefaultCarryRule = CarryRule{NonCapitalBack: 3, NonCapitalForward: 20, CapitalBack: 3, CapitalForward: -1}
+
+// FindCarryRule returns the carry rule configured for the year, or the
+// default rule
+func FindCarryRule(rules []CarryRule, year int) CarryRule {
+	for _, rule := range rules {
+		if rule.Year == year {
+			return rule
+		}
+	}
+	rule := DefaultCarryRule
+	rule.Year = year
+	return rule
+}
+
+// lossPool is a loss still available for deduction
+type lossPool struct {
+	balance *LossBalance
+	back    int
+	forward int
+}
+
+func (p lossPool) eligible(year int) bool {
+	if year == p.balance.LossYear {
+		return false
+	}
+	if year < p.balance.LossYear {
+		return p.balance.LossYear-year <= p.back
+	}
+	return p.forward < 0 || year-p.balance.LossYear <= p.forward
+}
+
+// ComputeLosses applies the losses of every year to the other years their
+// carry rules allow. Losses are applied greedily: each step deducts up to
+// the next bracket threshold in the year where it saves the most tax, so
+// losses go to the years with the highest marginal rate first. Ties go to
+// the loss expiring first and then to the earliest year. A loss is never
+// applied where it saves no tax, so it stays available for later years.
+func ComputeLosses(req LossRequest, years []TaxYear, rules []CarryRule) (LossResult, error) {
+	if len(req.Years) != len(years) {
+		return LossResult{}, fmt.Errorf("expected %d tax years, got %d", len(req.Years), len(years))
+	}
+
+	requests := make([]TaxRequest, len(req.Years))
+	results := make([]LossYearResult, len(req.Years))
+	var pools []lossPool
+	for i, year := range req.Years {
+		requests[i] = TaxRequest{
+			Income:       year.Income,
+			Year:         year.Year,
+			Jurisdiction: req.Jurisdiction,
+			CapitalGains: year.CapitalGains,
+		}
+		tax, err := years[i].Tax(requests[i])
+		if err != nil {
+			return LossResult{}, fmt.Errorf("tax year %d: %w", year.Year, err)
+		}
+		results[i] = LossYearResult{Year: year.Year, TaxBeforeLosses: tax, TaxAfterLosses: tax, Projection: years[i].Projection}
+
+		rule := FindCarryRule(rules, year.Year)
+		if year.NonCapitalLoss > 0 {
+			pools = append(pools, lossPool{
+				balance: newLossBalance(year.Year, LossNonCapital, year.NonCapitalLoss, rule.NonCapitalForward),
+				back:    rule.NonCapitalBack,
+				forward: rule.NonCapitalForward,
+			})
+		}
+		if year.NetCapitalLoss > 0 {
+			pools = append(pools, lossPool{
+				balance: newLossBalance(year.Year, LossNetCapital, year.NetCapitalLoss, rule.CapitalForward),
+				back:    rule.CapitalBack,
+				forward: rule.CapitalForward,
+			})
+		}
+	}
+
+	// Losses expiring first win ties
+	sort.SliceStable(pools, func(i, j int) bool {
+		return expiry(pools[i].balance) < expiry(pools[j].balance)
+	})
+
+	var applications []LossApplication
+	for {
+		best := struct {
+			pool, year     int
+			amount, saving float64
+			rate           float64
+		}{pool: -1}
+
+		for p, pool := range pools {
+			if pool.balance.Remaining <= 0 {
+				continue
+			}
+			for y, year := range req.Years {
+				if !pool.eligible(year.Year) {
+					continue
+				}
+				amount := lossStep(pool, requests[y], years[y], pool.balance.Remaining)
+				if amount <= 0 {
+					continue
+				}
+				deducted := deduct(requests[y], pool.balance.Kind, amount)
+				tax, err := years[y].Tax(deducted)
+				if err != nil {
+					return LossResult{}, fmt.Errorf("tax year %d: %w", year.Year, err)
+				}
+				saving := results[y].TaxAfterLosses - tax
+				if rate := saving / amount; rate > best.rate+1e-12 {
+					best.pool, best.year, best.amount, best.saving, best.rate = p, y, amount, saving, rate
+				}
+			}
+		}
+		if best.pool < 0 {
+			break
+		}
+
+		pool := pools[best.pool]
+		requests[best.year] = deduct(requests[best.year], pool.balance.Kind, best.amount)
+		results[best.year].TaxAfterLosses -= best.saving
+		if pool.balance.Kind == LossNonCapital {
+			results[best.year].NonCapitalDeduction += best.amount
+		} else {
+			results[best.year].NetCapitalDeduction += best.amount
+		}
+		pool.balance.Applied += best.amount
+		pool.balance.Remaining -= best.amount
+		applications = mergeApplication(applications, LossApplication{
+			LossYear:  pool.balance.LossYear,
+			Kind:      pool.balance.Kind,
+			AppliedTo: req.Years[best.year].Year,
+			Amount:    best.amount,
+			Saving:    best.saving,
+		})
+	}
+
+	result := LossResult{Years: results, Applications: applications}
+	for _, year := range results {
+		result.TaxBeforeLosses += year.TaxBeforeLosses
+		result.TaxAfterLosses += year.TaxAfterLosses
+	}
+	result.Saving = result.TaxBeforeLosses - result.TaxAfterLosses
+
+	balances := make([]LossBalance, 0, len(pools))
+	for _, pool := range pools {
+		balances = append(balances, *pool.balance)
+	}
+	sort.SliceStable(balances, func(i, j int) bool {
+		if balances[i].LossYear != balances[j].LossYear {
+			return balances[i].LossYear < balances[j].LossYear
+		}
+		return balances[i].Kind > balances[j].Kind
+	})
+	result.Balances = balances
+
+	return result, nil
+}
+
+func newLossBalance(year int, kind string, amount float64, forward int) *LossBalance {
+	balance := &LossBalance{LossYear: year, Kind: kind, Amount: amount, Remaining: amount}
+	if forward >= 0 {
+		balance.ExpiresAfter = year + forward
+	}
+	return balance
+}
+
+func expiry(b *LossBalance) int {
+	if b.ExpiresAfter == 0 {
+		return math.MaxInt
+	}
+	return b.ExpiresAfter
+}
+
+// lossStep returns how much of the loss to deduct in the year in one step:
+// as much as the income allows, but no further than the next bracket
+// threshold below the current income, so each step has a single rate
+func lossStep(pool lossPool, req TaxRequest, year TaxYear, remaining float64) float64 {
+	available := req.Income
+	if pool.balance.Kind == LossNetCapital {
+		available = req.CapitalGains
+	}
+	step := math.Min(remaining, available)
+	if step <= 0 {
+		return 0
+	}
+
+	income := req.TotalIncome()
+	for _, kink := range year.Kinks() {
+		if kink < income && income-kink < step {
+			step = income - kink
+		}
+	}
+	return step
+}
+
+func deduct(req TaxRequest, kind string, amount float64) TaxRequest {
+	if kind == LossNonCapital {
+		req.Income -= amount
+	} else {
+		req.CapitalGains -= amount
+	}
+	return req
+}
+
+// mergeApplication folds consecutive steps of the same loss into the same
+// year into a single application
+func mergeApplication(applications []LossApplication, a LossApplication) []LossApplication {
+	for i := range applications {
+		existing := &applications[i]
+		if existing.LossYear == a.LossYear && existing.Kind == a.Kind && existing.AppliedTo == a.AppliedTo {
+			existing.Amount += a.Amount
+			existing.Saving += a.Saving
+			return applications
+		}
+	}
+	return append(applications, a)
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeLosses(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 50000, Rate: 0.2},
+		{Min: 50000, Max: 0, Rate: 0.4},
+	}
+	taxYears := func(years ...int) []TaxYear {
+		result := make([]TaxYear, len(years))
+		for i, year := range years {
+			result[i] = TaxYear{Year: year, Schedules: []TaxSchedule{WholeYearSchedule(year, brackets)}, Pipeline: DefaultPipeline()}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name                 string
+		req                  LossRequest
+		rules                []CarryRule
+		expectedSaving       float64
+		expectedApplications []LossApplication
+		expectedBalances     []LossBalance
+	}{
+		{
+			name: "Loss carried back to the year with the highest rate",
+			req: LossRequest{Years: []LossYear{
+				{Year: 2019, Income: 80000},
+				{Year: 2020, NonCapitalLoss: 50000},
+				{Year: 2021, Income: 30000},
+			}},
+			expectedSaving: 16000,
+			expectedApplications: []LossApplication{
+				{LossYear: 2020, Kind: LossNonCapital, AppliedTo: 2019, Amount: 50000, Saving: 16000},
+			},
+			expectedBalances: []LossBalance{
+				{LossYear: 2020, Kind: LossNonCapital, Amount: 50000, Applied: 50000, ExpiresAfter: 2040},
+			},
+		},
+		{
+			name: "Carry rules limit the years a loss reaches",
+			req: LossRequest{Years: []LossYear{
+				{Year: 2019, Income: 80000},
+				{Year: 2020, NonCapitalLoss: 50000},
+				{Year: 2021, Income: 30000},
+			}},
+			rules:          []CarryRule{{Year: 2020, NonCapitalBack: 0, NonCapitalForward: 20}},
+			expectedSaving: 4000,
+			expectedApplications: []LossApplication{
+				{LossYear: 2020, Kind: LossNonCapital, AppliedTo: 2021, Amount: 20000, Saving: 4000},
+			},
+			expectedBalances: []LossBalance{
+				{LossYear: 2020, Kind: LossNonCapital, Amount: 50000, Applied: 20000, Remaining: 30000, ExpiresAfter: 2040},
+			},
+		},
+		{
+			name: "Net capital losses only reduce capital gains",
+			req: LossRequest{Years: []LossYear{
+				{Year: 2020, Income: 30000, NetCapitalLoss: 10000},
+				{Year: 2021, Income: 30000, CapitalGains: 4000},
+			}},
+			expectedSaving: 800,
+			expectedApplications: []LossApplication{
+				{LossYear: 2020, Kind: LossNetCapital, AppliedTo: 2021, Amount: 4000, Saving: 800},
+			},
+			expectedBalances: []LossBalance{
+				{LossYear: 2020, Kind: LossNetCapital, Amount: 10000, Applied: 4000, Remaining: 6000},
+			},
+		},
+		{
+			name: "Losses are kept where they save nothing",
+			req: LossRequest{Years: []LossYear{
+				{Year: 2020, NonCapitalLoss: 5000},
+				{Year: 2021, Income: 8000},
+			}},
+			expectedBalances: []LossBalance{
+				{LossYear: 2020, Kind: LossNonCapital, Amount: 5000, Remaining: 5000, ExpiresAfter: 2040},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			years := make([]int, len(tt.req.Years))
+			for i, year := range tt.req.Years {
+				years[i] = year.Year
+			}
+
+			result, err := ComputeLosses(tt.req, taxYears(years...), tt.rules)
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedSaving, result.Saving, 0.0001)
+			assert.Equal(t, tt.expectedBalances, result.Balances)
+			assert.Len(t, result.Applications, len(tt.expectedApplications))
+			for i, a := range tt.expectedApplications {
+				assert.Equal(t, a.LossYear, result.Applications[i].LossYear)
+				assert.Equal(t, a.Kind, result.Applications[i].Kind)
+				assert.Equal(t, a.AppliedTo, result.Applications[i].AppliedTo)
+				assert.InDelta(t, a.Amount, result.Applications[i].Amount, 0.0001)
+				assert.InDelta(t, a.Saving, result.Applications[i].Saving, 0.0001)
+			}
+		})
+	}
+}
+
+func TestFindCarryRule(t *testing.T) {
+	rules := []CarryRule{{Year: 2020, NonCapitalBack: 1, NonCapitalForward: 5}}
+
+	assert.Equal(t, rules[0], FindCarryRule(rules, 2020))
+
+	expected := DefaultCarryRule
+	expected.Year = 2021
+	assert.Equal(t, expected, FindCarryRule(rules, 2021))
+}
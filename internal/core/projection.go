@@ -0,0 +1,132 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Sources of the indexation rate of a projected year
+const (
+	IndexationInflation = "inflation_rate"
+	IndexationCPI       = "cpi"
+	IndexationRequest   = "request"
+)
+
+type (
+	// CPISeries is the consumer price index level of each year
+	CPISeries map[int]float64
+
+	// Indexation derives the rate thresholds are indexed by in a
+	// projected year: the change in the CPI series from the previous year
+	// when the series covers both years, the flat inflation rate otherwise
+	Indexation struct {
+		InflationRate float64
+		CPI           CPISeries
+	}
+
+	// ProjectedYear is the indexation applied to reach one projected year
+	ProjectedYear struct {
+		Year   int     `json:"year"`
+		Rate   float64 `json:"rate"`
+		Source string  `json:"source"`
+	}
+
+	// Projection describes how the schedules of a year the upstream does
+	// not publish were derived from the latest known year. Factor is the
+	// cumulative indexation of the thresholds.
+	Projection struct {
+		BaseYear int             `json:"base_year"`
+		Year     int             `json:"year"`
+		Factor   float64         `json:"factor"`
+		Rates    []ProjectedYear `json:"rates"`
+	}
+)
+
+// Rate returns the indexation rate of the year and where it comes from
+func (ix Indexation) Rate(year int) (float64, string) {
+	current, ok := ix.CPI[year]
+	previous, okPrevious := ix.CPI[year-1]
+	if ok && okPrevious && previous > 0 {
+		return current/previous - 1, IndexationCPI
+	}
+	return ix.InflationRate, IndexationInflation
+}
+
+// ProjectSchedules derives the schedules of year from those of baseYear by
+// indexing every bracket threshold, rounded to the nearest dollar, for each
+// year in between. A non-nil override replaces the rate of every year.
+func ProjectSchedules(schedules []TaxSchedule, baseYear, year int, ix Indexation, override *float64) ([]TaxSchedule, Projection, error) {
+	if year <= baseYear {
+		return nil, Projection{}, fmt.Errorf("cannot project %d from later year %d", year, baseYear)
+	}
+
+	projection := Projection{BaseYear: baseYear, Year: year, Factor: 1}
+	for y := baseYear + 1; y <= year; y++ {
+		rate, source := ix.Rate(y)
+		if override != nil {
+			rate, source = *override, IndexationRequest
+		}
+		projection.Factor *= 1 + rate
+		projection.Rates = append(projection.Rates, ProjectedYear{Year: y, Rate: rate, Source: source})
+	}
+
+	projected := make([]TaxSchedule, len(schedules))
+	for i, schedule := range schedules {
+		from, to, err := schedule.Dates()
+		if err != nil {
+			return nil, Projection{}, err
+		}
+
+		brackets := make([]TaxBracket, len(schedule.Brackets))
+		for j, b := range schedule.Brackets {
+			brackets[j] = TaxBracket{
+				Min:  math.Round(b.Min * projection.Factor),
+				Max:  math.Round(b.Max * projection.Factor),
+				Rate: b.Rate,
+			}
+		}
+
+		projected[i] = TaxSchedule{
+			EffectiveFrom: shiftYears(from, year-baseYear).Format(DateFormat),
+			Brackets:      brackets,
+			Source:        fmt.Sprintf("projected from %d", baseYear),
+			Version:       schedule.Version,
+		}
+		if !to.IsZero() {
+			projected[i].EffectiveTo = shiftYears(to, year-baseYear).Format(DateFormat)
+		}
+	}
+
+	return projected, projection, nil
+}
+
+// ProjectRules indexes the dollar amounts of the rules of the base year by
+// the cumulative factor of a projection, rounded to the nearest dollar:
+// thresholds, amounts and surtax tier thresholds. Rates are kept.
+func ProjectRules(specs []RuleSpec, projection Projection) []RuleSpec {
+	projected := make([]RuleSpec, len(specs))
+	for i, spec := range specs {
+		spec.Threshold = math.Round(spec.Threshold * projection.Factor)
+		spec.Amount = math.Round(spec.Amount * projection.Factor)
+		if spec.Tiers != nil {
+			tiers := make([]RuleTier, len(spec.Tiers))
+			for j, tier := range spec.Tiers {
+				tiers[j] = RuleTier{Threshold: math.Round(tier.Threshold * projection.Factor), Rate: tier.Rate}
+			}
+			spec.Tiers = tiers
+		}
+		projected[i] = spec
+	}
+	return projected
+}
+
+// shiftYears moves a date by whole years, keeping February 28 rather than
+// rolling a February 29 into March
+func shiftYears(date time.Time, years int) time.Time {
+	shifted := date.AddDate(years, 0, 0)
+	if shifted.Month() != date.Month() {
+		shifted = shifted.AddDate(0, 0, -shifted.Day())
+	}
+	return shifted
+}
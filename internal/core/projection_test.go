@@ -0,0 +1,133 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexation_Rate(t *testing.T) {
+	ix := Indexation{InflationRate: 0.02, CPI: CPISeries{2022: 100, 2023: 105}}
+
+	rate, source := ix.Rate(2023)
+	assert.InDelta(t, 0.05, rate, 0.0001)
+	assert.Equal(t, IndexationCPI, source)
+
+	rate, source = ix.Rate(2024)
+	assert.Equal(t, 0.02, rate)
+	assert.Equal(t, IndexationInflation, source)
+}
+
+func TestProjectSchedules(t *testing.T) {
+	schedules := []TaxSchedule{
+		{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-06-30", Brackets: []TaxBracket{{Min: 0, Max: 10000, Rate: 0.1}, {Min: 10000, Rate: 0.2}}, Version: "v1"},
+		{EffectiveFrom: "2022-07-01", EffectiveTo: "2022-12-31", Brackets: []TaxBracket{{Min: 0, Max: 12000, Rate: 0.1}, {Min: 12000, Rate: 0.2}}, Version: "v1"},
+	}
+	ix := Indexation{InflationRate: 0.1, CPI: CPISeries{2022: 100, 2023: 105}}
+	override := 0.5
+
+	tests := []struct {
+		name             string
+		year             int
+		override         *float64
+		expectedFactor   float64
+		expectedRates    []ProjectedYear
+		expectedBrackets [][]TaxBracket
+		expectError      bool
+	}{
+		{
+			name:           "CPI then inflation rate",
+			year:           2024,
+			expectedFactor: 1.05 * 1.1,
+			expectedRates: []ProjectedYear{
+				{Year: 2023, Rate: 0.05, Source: IndexationCPI},
+				{Year: 2024, Rate: 0.1, Source: IndexationInflation},
+			},
+			expectedBrackets: [][]TaxBracket{
+				{{Min: 0, Max: 11550, Rate: 0.1}, {Min: 11550, Rate: 0.2}},
+				{{Min: 0, Max: 13860, Rate: 0.1}, {Min: 13860, Rate: 0.2}},
+			},
+		},
+		{
+			name:           "Rate overridden by the request",
+			year:           2023,
+			override:       &override,
+			expectedFactor: 1.5,
+			expectedRates:  []ProjectedYear{{Year: 2023, Rate: 0.5, Source: IndexationRequest}},
+			expectedBrackets: [][]TaxBracket{
+				{{Min: 0, Max: 15000, Rate: 0.1}, {Min: 15000, Rate: 0.2}},
+				{{Min: 0, Max: 18000, Rate: 0.1}, {Min: 18000, Rate: 0.2}},
+			},
+		},
+		{
+			name:        "Cannot project backwards",
+			year:        2021,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projected, projection, err := ProjectSchedules(schedules, 2022, tt.year, ix, tt.override)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedFactor, projection.Factor, 0.0001)
+			assert.Len(t, projection.Rates, len(tt.expectedRates))
+			for i, rate := range tt.expectedRates {
+				assert.Equal(t, rate.Year, projection.Rates[i].Year)
+				assert.Equal(t, rate.Source, projection.Rates[i].Source)
+				assert.InDelta(t, rate.Rate, projection.Rates[i].Rate, 0.0001)
+			}
+			for i, brackets := range tt.expectedBrackets {
+				assert.Equal(t, brackets, projected[i].Brackets)
+				assert.Equal(t, "projected from 2022", projected[i].Source)
+			}
+		})
+	}
+}
+
+func TestProjectRules(t *testing.T) {
+	specs := []RuleSpec{
+		{Type: RuleBrackets},
+		{Type: RuleSurtax, Name: "surtax", Tiers: []RuleTier{{Threshold: 5000, Rate: 0.2}, {Threshold: 6500, Rate: 0.36}}},
+		{Type: RuleCredit, Name: "basic", Rate: 0.15, Amount: 12345},
+		{Type: RuleClawback, Name: "oas", Rate: 0.15, Threshold: 81761, Amount: 7500},
+		{Type: RuleMinimumTax, Name: "minimum", Rate: 0.15, Threshold: 40000},
+	}
+
+	projected := ProjectRules(specs, Projection{BaseYear: 2022, Year: 2024, Factor: 1.05 * 1.1})
+
+	assert.Equal(t, []RuleSpec{
+		{Type: RuleBrackets},
+		{Type: RuleSurtax, Name: "surtax", Tiers: []RuleTier{{Threshold: 5775, Rate: 0.2}, {Threshold: 7508, Rate: 0.36}}},
+		{Type: RuleCredit, Name: "basic", Rate: 0.15, Amount: 14258},
+		{Type: RuleClawback, Name: "oas", Rate: 0.15, Threshold: 94434, Amount: 8663},
+		{Type: RuleMinimumTax, Name: "minimum", Rate: 0.15, Threshold: 46200},
+	}, projected)
+	// The rules of the base year are left untouched
+	assert.Equal(t, 5000.0, specs[1].Tiers[0].Threshold)
+}
+
+func TestShiftYears(t *testing.T) {
+	tests := []struct {
+		date     string
+		years    int
+		expected string
+	}{
+		{date: "2022-07-01", years: 1, expected: "2023-07-01"},
+		{date: "2022-12-31", years: 2, expected: "2024-12-31"},
+		{date: "2024-02-29", years: 1, expected: "2025-02-28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			date, err := time.Parse(DateFormat, tt.date)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, shiftYears(date, tt.years).Format(DateFormat))
+		})
+	}
+}
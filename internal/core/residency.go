@@ -0,0 +1,70 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ResidencyResult describes the part of the tax year the taxpayer was
+// resident. Factor is the share of the year used to prorate credits and
+// thresholds.
+type ResidencyResult struct {
+	Start    string  `json:"start"`
+	End      string  `json:"end"`
+	Days     int     `json:"days"`
+	YearDays int     `json:"year_days"`
+	Factor   float64 `json:"factor"`
+}
+
+// ComputeResidency returns the residency period of the request, or nil when
+// the taxpayer was resident all year. A missing start means resident since
+// January 1 and a missing end means resident through December 31.
+func ComputeResidency(req TaxRequest) (*ResidencyResult, error) {
+	if req.ResidencyStart == "" && req.ResidencyEnd == "" {
+		return nil, nil
+	}
+
+	yearStart := time.Date(req.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(req.Year, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	start, end := yearStart, yearEnd
+	if req.ResidencyStart != "" {
+		date, err := time.Parse(DateFormat, req.ResidencyStart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid residency start date %q, expected format %s", req.ResidencyStart, DateFormat)
+		}
+		start = date
+	}
+	if req.ResidencyEnd != "" {
+		date, err := time.Parse(DateFormat, req.ResidencyEnd)
+		if err != nil {
+			return nil, fmt.Errorf("invalid residency end date %q, expected format %s", req.ResidencyEnd, DateFormat)
+		}
+		end = date
+	}
+
+	if req.ResidencyStart != "" && req.ResidencyEnd != "" && end.Before(start) {
+		return nil, errors.New("residency ends before it starts")
+	}
+	if start.Before(yearStart) {
+		start = yearStart
+	}
+	if end.After(yearEnd) {
+		end = yearEnd
+	}
+	if start.After(end) {
+		return nil, fmt.Errorf("residency does not overlap tax year %d", req.Year)
+	}
+
+	days := daysBetween(start, end)
+	yearDays := daysBetween(yearStart, yearEnd)
+
+	return &ResidencyResult{
+		Start:    start.Format(DateFormat),
+		End:      end.Format(DateFormat),
+		Days:     days,
+		YearDays: yearDays,
+		Factor:   float64(days) / float64(yearDays),
+	}, nil
+}
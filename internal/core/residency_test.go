@@ -0,0 +1,126 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeResidency(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           TaxRequest
+		expected      *ResidencyResult
+		expectedError string
+	}{
+		{
+			name:     "Resident all year",
+			req:      TaxRequest{Year: 2022},
+			expected: nil,
+		},
+		{
+			name: "Moved in mid-year",
+			req:  TaxRequest{Year: 2022, ResidencyStart: "2022-07-02"},
+			expected: &ResidencyResult{
+				Start: "2022-07-02", End: "2022-12-31", Days: 183, YearDays: 365, Factor: 183.0 / 365,
+			},
+		},
+		{
+			name: "Moved out mid-year",
+			req:  TaxRequest{Year: 2022, ResidencyEnd: "2022-01-31"},
+			expected: &ResidencyResult{
+				Start: "2022-01-01", End: "2022-01-31", Days: 31, YearDays: 365, Factor: 31.0 / 365,
+			},
+		},
+		{
+			name: "Dates outside the year are clipped",
+			req:  TaxRequest{Year: 2024, ResidencyStart: "2023-06-01", ResidencyEnd: "2025-01-01"},
+			expected: &ResidencyResult{
+				Start: "2024-01-01", End: "2024-12-31", Days: 366, YearDays: 366, Factor: 1,
+			},
+		},
+		{
+			name:          "Malformed date",
+			req:           TaxRequest{Year: 2022, ResidencyStart: "02/07/2022"},
+			expectedError: "invalid residency start date",
+		},
+		{
+			name:          "End before start",
+			req:           TaxRequest{Year: 2022, ResidencyStart: "2022-07-01", ResidencyEnd: "2022-06-30"},
+			expectedError: "residency ends before it starts",
+		},
+		{
+			name:          "Residency in another year",
+			req:           TaxRequest{Year: 2022, ResidencyStart: "2023-01-01"},
+			expectedError: "does not overlap tax year 2022",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeResidency(tt.req)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestProratedRules(t *testing.T) {
+	brackets := []TaxBracket{{Min: 0, Max: 0, Rate: 0.2}}
+	pipeline, err := NewPipeline([]RuleSpec{
+		{Type: RuleBrackets},
+		{Name: "basic", Type: RuleCredit, Rate: 0.1, Amount: 36500, Prorate: true},
+		{Name: "spouse", Type: RuleCredit, Rate: 0.1, Amount: 10000},
+		{Name: "benefit", Type: RuleClawback, Rate: 0.1, Threshold: 73000, Amount: 5000, Prorate: true},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		req           TaxRequest
+		expectedTotal float64
+		expectedLines []TaxLine
+	}{
+		{
+			name:          "Full-year resident",
+			req:           TaxRequest{Income: 100000, Year: 2022},
+			expectedTotal: 20000 - 3650 - 1000 + 2700,
+			expectedLines: []TaxLine{
+				{Name: RuleBrackets, Type: RuleBrackets, Amount: 20000},
+				{Name: "basic", Type: RuleCredit, Amount: -3650},
+				{Name: "spouse", Type: RuleCredit, Amount: -1000},
+				{Name: "benefit", Type: RuleClawback, Amount: 2700},
+			},
+		},
+		{
+			name:          "Half-year resident",
+			req:           TaxRequest{Income: 100000, Year: 2022, ResidencyStart: "2022-07-02"},
+			expectedTotal: 20000 - 1830 - 1000 + 5000.0*183/365,
+			expectedLines: []TaxLine{
+				{Name: RuleBrackets, Type: RuleBrackets, Amount: 20000},
+				{Name: "basic", Type: RuleCredit, Amount: -1830, Prorated: true, FullYearAmount: -3650},
+				{Name: "spouse", Type: RuleCredit, Amount: -1000},
+				{Name: "benefit", Type: RuleClawback, Amount: 5000.0 * 183 / 365, Prorated: true, FullYearAmount: 2700},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Calculate(tt.req, []TaxSchedule{WholeYearSchedule(tt.req.Year, brackets)}, pipeline, nil)
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.01)
+			assert.Len(t, result.Lines, len(tt.expectedLines))
+			for i, line := range tt.expectedLines {
+				assert.Equal(t, line.Name, result.Lines[i].Name)
+				assert.Equal(t, line.Prorated, result.Lines[i].Prorated)
+				assert.InDelta(t, line.Amount, result.Lines[i].Amount, 0.01)
+				assert.InDelta(t, line.FullYearAmount, result.Lines[i].FullYearAmount, 0.01)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var ErrTargetUnreachable = errors.New("target is not reachable with the given brackets")
+
+// solveTolerance is how far the value at a linear guess may be from the
+// target before the solver falls back to bisection
+const solveTolerance = 1e-6
+
+// maxSolveDoublings bounds the search for an income past the last kink
+const maxSolveDoublings = 64
+
+type (
+	// ReverseRequest asks for the gross income that produces either a target
+	// net income or a target tax amount under the rules of Jurisdiction.
+	// Exactly one target must be set.
+	ReverseRequest struct {
+		Year         int      `json:"year"`
+		Jurisdiction string   `json:"jurisdiction,omitempty"`
+		TargetNet    *float64 `json:"target_net,omitempty"`
+		TargetTax    *float64 `json:"target_tax,omitempty"`
+	}
+
+	ReverseResult struct {
+		GrossIncome float64 `json:"gross_income"`
+		NetIncome   float64 `json:"net_income"`
+		TaxResult
+	}
+)
+
+// Kinks returns the sorted, de-duplicated incomes at which the tax function
+// changes slope, starting at 0. Between two consecutive kinks tax is linear.
+func Kinks(brackets []TaxBracket) []float64 {
+	values := make([]float64, 0, 2*len(brackets))
+	for _, b := range brackets {
+		values = append(values, b.Min, b.Max)
+	}
+	return sortedKinks(values)
+}
+
+// sortedKinks sorts and de-duplicates the positive values, starting at 0
+func sortedKinks(values []float64) []float64 {
+	seen := map[float64]bool{0: true}
+	kinks := []float64{0}
+	for _, k := range values {
+		if k > 0 && !seen[k] {
+			seen[k] = true
+			kinks = append(kinks, k)
+		}
+	}
+	sort.Float64s(kinks)
+	return kinks
+}
+
+// incomeAtTax returns the smallest income whose bracket tax reaches tax, and
+// false when no income does
+func incomeAtTax(tax float64, brackets []TaxBracket) (float64, bool) {
+	schedule, err := CompileBrackets(brackets)
+	if err != nil {
+		return 0, false
+	}
+	income, err := solve(tax, Kinks(brackets), func(income float64) (float64, error) {
+		return schedule.Tax(income), nil
+	})
+	return income, err == nil
+}
+
+// GrossForNet returns the smallest gross income whose income after tax equals
+// the target. The segment containing the target is found by evaluating the
+// kinks of the year, brackets and rule thresholds alike, then solved
+// linearly; where a rule bends the tax between kinks or rounding makes it a
+// step, the segment is bisected instead.
+func GrossForNet(target float64, year TaxYear) (float64, error) {
+	return solve(target, year.Kinks(), func(income float64) (float64, error) {
+		tax, err := year.Tax(TaxRequest{Income: income})
+		return income - tax, err
+	})
+}
+
+// GrossForTax returns the smallest gross income whose tax equals the target.
+func GrossForTax(target float64, year TaxYear) (float64, error) {
+	return solve(target, year.Kinks(), func(income float64) (float64, error) {
+		return year.Tax(TaxRequest{Income: income})
+	})
+}
+
+// ComputeReverse solves for the gross income and returns it together with
+// the full breakdown for that income.
+func ComputeReverse(req ReverseRequest, year TaxYear) (ReverseResult, error) {
+	var (
+		gross float64
+		err   error
+	)
+	switch {
+	case req.TargetNet != nil:
+		gross, err = GrossForNet(*req.TargetNet, year)
+	case req.TargetTax != nil:
+		gross, err = GrossForTax(*req.TargetTax, year)
+	default:
+		return ReverseResult{}, errors.New("either target net or target tax is required")
+	}
+	if err != nil {
+		return ReverseResult{}, err
+	}
+
+	result, err := year.Calculate(TaxRequest{Income: gross, Jurisdiction: req.Jurisdiction})
+	if err != nil {
+		return ReverseResult{}, err
+	}
+	return ReverseResult{
+		GrossIncome: gross,
+		NetIncome:   gross - result.TotalTax,
+		TaxResult:   result,
+	}, nil
+}
+
+// solve inverts a non-decreasing function of income that is mostly linear
+// between the kinks. Each linear guess is checked against the function, so
+// kinks the caller does not know about, such as a credit running out or a
+// minimum tax taking over, only cost a bisection.
+func solve(target float64, kinks []float64, f func(float64) (float64, error)) (float64, error) {
+	if target < 0 {
+		return 0, ErrTargetUnreachable
+	}
+
+	lo := kinks[0]
+	flo, err := f(lo)
+	if err != nil {
+		return 0, err
+	}
+	if flo >= target {
+		return lo, nil
+	}
+
+	for _, hi := range kinks[1:] {
+		fhi, err := f(hi)
+		if err != nil {
+			return 0, err
+		}
+		if fhi >= target && fhi > flo {
+			return refine(target, lo, hi, lo+(target-flo)*(hi-lo)/(fhi-flo), f)
+		}
+		lo, flo = hi, fhi
+	}
+
+	// Past the last kink the function should be linear, so one more unit
+	// gives the slope. The guess is widened until it reaches the target in
+	// case a hidden kink lies further out.
+	next, err := f(lo + 1)
+	if err != nil {
+		return 0, err
+	}
+	guess := lo + 1
+	if slope := next - flo; slope > 0 {
+		guess = lo + (target-flo)/slope
+	}
+	hi := guess
+	for i := 0; ; i++ {
+		fhi, err := f(hi)
+		if err != nil {
+			return 0, err
+		}
+		if fhi >= target {
+			break
+		}
+		if i == maxSolveDoublings {
+			return 0, ErrTargetUnreachable
+		}
+		hi = lo + 2*(hi-lo)
+	}
+	return refine(target, lo, hi, guess, f)
+}
+
+// crossings returns the incomes at which gap changes sign. gap must be linear
+// between consecutive kinks and past the last one, so each sign change is
+// found by interpolation; a kink the caller does not know about only costs a
+// bisection.
+func crossings(kinks []float64, gap func(float64) float64) []float64 {
+	var found []float64
+	lo, glo := kinks[0], gap(kinks[0])
+	for _, hi := range kinks[1:] {
+		ghi := gap(hi)
+		if glo*ghi < 0 {
+			found = append(found, crossing(lo, hi, glo, ghi, gap))
+		}
+		lo, glo = hi, ghi
+	}
+
+	// Past the last kink one more unit gives the slope. The guess is widened
+	// until the sign changes in case a hidden kink lies further out.
+	slope := gap(lo+1) - glo
+	if glo*slope >= 0 {
+		return found
+	}
+	hi := lo - glo/slope
+	for i := 0; ; i++ {
+		ghi := gap(hi)
+		if ghi == 0 {
+			return append(found, hi)
+		}
+		if glo*ghi < 0 {
+			return append(found, crossing(lo, hi, glo, ghi, gap))
+		}
+		if i == maxSolveDoublings {
+			return found
+		}
+		hi = lo + 2*(hi-lo)
+	}
+}
+
+// crossing returns the income in [lo, hi] at which gap changes sign, given
+// that it does, given its values at both ends. The linear guess is kept when
+// gap vanishes there, otherwise the interval is bisected.
+func crossing(lo, hi, glo, ghi float64, gap func(float64) float64) float64 {
+	guess := lo + glo*(hi-lo)/(glo-ghi)
+	gg := gap(guess)
+	if math.Abs(gg) <= solveTolerance {
+		return guess
+	}
+	if guess > lo && guess < hi {
+		if glo*gg < 0 {
+			hi = guess
+		} else {
+			lo, glo = guess, gg
+		}
+	}
+	for {
+		mid := lo + (hi-lo)/2
+		if mid <= lo || mid >= hi {
+			return hi
+		}
+		gmid := gap(mid)
+		if gmid == 0 {
+			return mid
+		}
+		if glo*gmid < 0 {
+			hi = mid
+		} else {
+			lo, glo = mid, gmid
+		}
+	}
+}
+
+// refine returns guess when the function meets the target there, and
+// otherwise bisects [lo, hi], where f(lo) < target <= f(hi), for the smallest
+// income that reaches it.
+func refine(target, lo, hi, guess float64, f func(float64) (float64, error)) (float64, error) {
+	fg, err := f(guess)
+	if err != nil {
+		return 0, err
+	}
+	if math.Abs(fg-target) <= solveTolerance {
+		return guess, nil
+	}
+	if guess > lo && guess < hi {
+		if fg >= target {
+			hi = guess
+		} else {
+			lo = guess
+		}
+	}
+	for {
+		mid := lo + (hi-lo)/2
+		if mid <= lo || mid >= hi {
+			return hi, nil
+		}
+		fmid, err := f(mid)
+		if err != nil {
+			return 0, err
+		}
+		if fmid >= target {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+}
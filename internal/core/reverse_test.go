@@ -0,0 +1,186 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKinks(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 50000, Rate: 0.15},
+		{Min: 50001, Max: 100000, Rate: 0.2},
+		{Min: 100000, Max: 0, Rate: 0.3},
+	}
+
+	assert.Equal(t, []float64{0, 50000, 50001, 100000}, Kinks(brackets))
+}
+
+func TestCrossings(t *testing.T) {
+	kinks := []float64{0, 10000, 50000}
+	tests := []struct {
+		name     string
+		gap      func(float64) float64
+		expected []float64
+	}{
+		{
+			name:     "Between two kinks",
+			gap:      func(x float64) float64 { return 0.2*x - 3000 },
+			expected: []float64{15000},
+		},
+		{
+			name:     "Past the last kink",
+			gap:      func(x float64) float64 { return 0.1*x - 7000 },
+			expected: []float64{70000},
+		},
+		{
+			name:     "Hidden kink inside a segment",
+			gap:      func(x float64) float64 { return math.Max(0, x-20000)*0.5 - 1000 },
+			expected: []float64{22000},
+		},
+		{
+			name:     "Hidden kink off the bisection points",
+			gap:      func(x float64) float64 { return math.Max(0, x-20000)*0.5 - 1234 },
+			expected: []float64{22468},
+		},
+		{
+			name: "No sign change",
+			gap:  func(x float64) float64 { return x },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDeltaSlice(t, tt.expected, crossings(kinks, tt.gap), 0.001)
+		})
+	}
+}
+
+func TestComputeReverse(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 50000, Rate: 0.2},
+		{Min: 50000, Max: 0, Rate: 0.4},
+	}
+	f := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name          string
+		req           ReverseRequest
+		brackets      []TaxBracket
+		schedules     []TaxSchedule
+		rules         []RuleSpec
+		expectedGross float64
+		expectedError error
+	}{
+		{
+			name:          "Net inside tax-free band",
+			req:           ReverseRequest{TargetNet: f(8000)},
+			brackets:      brackets,
+			expectedGross: 8000,
+		},
+		{
+			name:          "Net inside middle band",
+			req:           ReverseRequest{TargetNet: f(34000)},
+			brackets:      brackets,
+			expectedGross: 40000, // 40000 - 6000
+		},
+		{
+			name:          "Net inside open-ended band",
+			req:           ReverseRequest{TargetNet: f(48000)},
+			brackets:      brackets,
+			expectedGross: 60000, // 60000 - 8000 - 4000
+		},
+		{
+			name:          "Tax of zero returns zero income",
+			req:           ReverseRequest{TargetTax: f(0)},
+			brackets:      brackets,
+			expectedGross: 0,
+		},
+		{
+			name:          "Tax inside open-ended band",
+			req:           ReverseRequest{TargetTax: f(12000)},
+			brackets:      brackets,
+			expectedGross: 60000,
+		},
+		{
+			name:          "Tax beyond a capped schedule is unreachable",
+			req:           ReverseRequest{TargetTax: f(5000)},
+			brackets:      []TaxBracket{{Min: 0, Max: 10000, Rate: 0.1}},
+			expectedError: ErrTargetUnreachable,
+		},
+		{
+			name: "Tax under schedules changing mid-year",
+			req:  ReverseRequest{TargetTax: f(1000)},
+			schedules: []TaxSchedule{
+				{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-07-01", Brackets: []TaxBracket{{Min: 0, Max: 0, Rate: 0.1}}},
+				{EffectiveFrom: "2022-07-02", EffectiveTo: "2022-12-31", Brackets: []TaxBracket{{Min: 0, Max: 0, Rate: 0.2}}},
+			},
+			expectedGross: 1000 / ((0.1*182 + 0.2*183) / 365),
+		},
+		{
+			name: "Net above a clawback threshold",
+			req:  ReverseRequest{TargetNet: f(30000)},
+			rules: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleClawback, Rate: 0.1, Threshold: 30000, Amount: 1000},
+			},
+			brackets:      brackets,
+			expectedGross: 25000 / 0.7, // g - 0.2 * (g - 10000) - 0.1 * (g - 30000) = 30000
+		},
+		{
+			name: "Tax past the end of a credit",
+			req:  ReverseRequest{TargetTax: f(2000)},
+			rules: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleCredit, Rate: 1, Amount: 1000},
+			},
+			brackets:      []TaxBracket{{Min: 0, Max: 0, Rate: 0.1}},
+			expectedGross: 30000,
+		},
+		{
+			name:          "Negative target is unreachable",
+			req:           ReverseRequest{TargetNet: f(-1)},
+			brackets:      brackets,
+			expectedError: ErrTargetUnreachable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedules := tt.schedules
+			if schedules == nil {
+				schedules = []TaxSchedule{WholeYearSchedule(2022, tt.brackets)}
+			}
+			pipeline := DefaultPipeline()
+			if tt.rules != nil {
+				var err error
+				pipeline, err = NewPipeline(tt.rules)
+				assert.NoError(t, err)
+			}
+			year := TaxYear{Year: 2022, Schedules: schedules, Pipeline: pipeline}
+
+			result, err := ComputeReverse(tt.req, year)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedGross, result.GrossIncome, 0.0001)
+			assert.InDelta(t, result.GrossIncome-result.TotalTax, result.NetIncome, 0.0001)
+			if tt.req.TargetNet != nil {
+				assert.InDelta(t, *tt.req.TargetNet, result.NetIncome, 0.0001)
+			} else {
+				assert.InDelta(t, *tt.req.TargetTax, result.TotalTax, 0.0001)
+			}
+		})
+	}
+}
+
+func TestComputeReverse_MissingTarget(t *testing.T) {
+	_, err := ComputeReverse(ReverseRequest{Year: 2022}, TaxYear{Year: 2022, Pipeline: DefaultPipeline()})
+	assert.Error(t, err)
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// RoundBracket rounds the tax of each bracket, then the total
+	RoundBracket = "bracket"
+	// RoundTotal rounds only the total tax
+	RoundTotal = "total"
+
+	// RoundNearest rounds half away from zero
+	RoundNearest = "nearest"
+	// RoundTruncate drops the fraction below the increment
+	RoundTruncate = "truncate"
+
+	// DefaultRoundingIncrement rounds to the cent
+	DefaultRoundingIncrement = 0.01
+
+	// LineRounding is the type and name of the line that carries the
+	// rounding of the total, so the lines add up to the total tax
+	LineRounding = "rounding"
+)
+
+// RoundingPolicy is how the official calculation of a jurisdiction and year
+// rounds: at which step, in which direction and to which increment. Method
+// defaults to nearest and Increment to the cent; an increment of 1 rounds
+// to whole dollars.
+type RoundingPolicy struct {
+	Jurisdiction string  `mapstructure:"jurisdiction" json:"jurisdiction"`
+	Year         int     `mapstructure:"year" json:"year"`
+	Step         string  `mapstructure:"step" json:"step"`
+	Method       string  `mapstructure:"method" json:"method,omitempty"`
+	Increment    float64 `mapstructure:"increment" json:"increment,omitempty"`
+}
+
+// Enabled reports whether the policy rounds at all. The zero policy leaves
+// amounts unrounded.
+func (p RoundingPolicy) Enabled() bool {
+	return p.Step != ""
+}
+
+// Validate checks the step, method and increment of the policy
+func (p RoundingPolicy) Validate() error {
+	switch p.Step {
+	case RoundBracket, RoundTotal:
+	default:
+		return fmt.Errorf("unknown rounding step %q", p.Step)
+	}
+	switch p.Method {
+	case "", RoundNearest, RoundTruncate:
+	default:
+		return fmt.Errorf("unknown rounding method %q", p.Method)
+	}
+	if p.Increment < 0 {
+		return errors.New("increment must be non-negative")
+	}
+	return nil
+}
+
+// Round rounds v to the policy's increment. Amounts are scaled to whole
+// increments first, so a truncated 1234.57 stays 1234.57 despite its
+// binary representation.
+func (p RoundingPolicy) Round(v float64) float64 {
+	if !p.Enabled() {
+		return v
+	}
+
+	increment := p.Increment
+	if increment == 0 {
+		increment = DefaultRoundingIncrement
+	}
+	scale := 1 / increment
+
+	scaled := v * scale
+	if p.Method != RoundTruncate {
+		return math.Round(scaled) / scale
+	}
+	if nearest := math.Round(scaled); math.Abs(scaled-nearest) < 1e-6 {
+		scaled = nearest
+	}
+	return math.Trunc(scaled) / scale
+}
+
+// describe names the rounding in trace formulas
+func (p RoundingPolicy) describe() string {
+	method := p.Method
+	if method == "" {
+		method = RoundNearest
+	}
+	increment := p.Increment
+	if increment == 0 {
+		increment = DefaultRoundingIncrement
+	}
+	return fmt.Sprintf("%s to %g", method, increment)
+}
+
+// FindRoundingPolicy returns the policy configured for the jurisdiction and
+// year
+func FindRoundingPolicy(policies []RoundingPolicy, jurisdiction string, year int) (RoundingPolicy, bool) {
+	for _, policy := range policies {
+		if policy.Jurisdiction == jurisdiction && policy.Year == year {
+			return policy, true
+		}
+	}
+	return RoundingPolicy{}, false
+}
+
+// ValidateRoundingPolicies checks every configured policy
+func ValidateRoundingPolicies(policies []RoundingPolicy) error {
+	for _, policy := range policies {
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("rounding policy %q/%d: %w", policy.Jurisdiction, policy.Year, err)
+		}
+	}
+	return nil
+}
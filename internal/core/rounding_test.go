@@ -0,0 +1,212 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundingPolicy_Round(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RoundingPolicy
+		value    float64
+		expected float64
+	}{
+		{name: "No policy leaves the amount unrounded", policy: RoundingPolicy{}, value: 1234.5678, expected: 1234.5678},
+		{name: "Nearest cent by default", policy: RoundingPolicy{Step: RoundTotal}, value: 1234.5678, expected: 1234.57},
+		{name: "Nearest rounds half away from zero", policy: RoundingPolicy{Step: RoundTotal, Increment: 1}, value: -2.5, expected: -3},
+		{name: "Truncate to the cent", policy: RoundingPolicy{Step: RoundTotal, Method: RoundTruncate}, value: 1234.5678, expected: 1234.56},
+		{name: "Truncate keeps whole cents", policy: RoundingPolicy{Step: RoundTotal, Method: RoundTruncate}, value: 1234.57, expected: 1234.57},
+		{name: "Truncate to whole dollars", policy: RoundingPolicy{Step: RoundBracket, Method: RoundTruncate, Increment: 1}, value: 480.99, expected: 480},
+		{name: "Truncate a negative amount towards zero", policy: RoundingPolicy{Step: RoundTotal, Method: RoundTruncate, Increment: 1}, value: -480.99, expected: -480},
+		{name: "Nearest nickel", policy: RoundingPolicy{Step: RoundTotal, Increment: 0.05}, value: 1.23, expected: 1.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.policy.Round(tt.value))
+		})
+	}
+}
+
+func TestValidateRoundingPolicies(t *testing.T) {
+	tests := []struct {
+		name          string
+		policies      []RoundingPolicy
+		expectedError string
+	}{
+		{name: "No policies", policies: nil},
+		{
+			name: "Valid policies",
+			policies: []RoundingPolicy{
+				{Jurisdiction: "ON", Year: 2022, Step: RoundBracket},
+				{Jurisdiction: "QC", Year: 2022, Step: RoundTotal, Method: RoundTruncate, Increment: 1},
+			},
+		},
+		{
+			name:          "Unknown step",
+			policies:      []RoundingPolicy{{Jurisdiction: "ON", Year: 2022, Step: "line"}},
+			expectedError: `rounding policy "ON"/2022: unknown rounding step "line"`,
+		},
+		{
+			name:          "Unknown method",
+			policies:      []RoundingPolicy{{Jurisdiction: "ON", Year: 2022, Step: RoundTotal, Method: "ceiling"}},
+			expectedError: `unknown rounding method "ceiling"`,
+		},
+		{
+			name:          "Negative increment",
+			policies:      []RoundingPolicy{{Jurisdiction: "ON", Year: 2022, Step: RoundTotal, Increment: -1}},
+			expectedError: "increment must be non-negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRoundingPolicies(tt.policies)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCalculate_Rounding(t *testing.T) {
+	// The bands tax 150.30 and 250.40, 400.70 in total
+	brackets := []TaxBracket{
+		{Min: 0, Max: 1002, Rate: 0.15},
+		{Min: 1002, Max: 0, Rate: 0.25},
+	}
+	schedules := []TaxSchedule{WholeYearSchedule(2022, brackets)}
+	req := TaxRequest{Income: 2003.6, Year: 2022, Explain: true}
+
+	tests := []struct {
+		name               string
+		policy             RoundingPolicy
+		expectedTotal      float64
+		expectedPerBracket map[string]float64
+		expectedRounding   int
+	}{
+		{
+			name:               "No policy",
+			expectedTotal:      400.7,
+			expectedPerBracket: map[string]float64{"0.00-1002.00": 150.3, "1002.00-2003.60": 250.4},
+		},
+		{
+			name:               "Total rounded to the nearest dollar",
+			policy:             RoundingPolicy{Step: RoundTotal, Increment: 1},
+			expectedTotal:      401,
+			expectedPerBracket: map[string]float64{"0.00-1002.00": 150.3, "1002.00-2003.60": 250.4},
+			expectedRounding:   1,
+		},
+		{
+			name:               "Each bracket rounded to the nearest dollar",
+			policy:             RoundingPolicy{Step: RoundBracket, Increment: 1},
+			expectedTotal:      400,
+			expectedPerBracket: map[string]float64{"0.00-1002.00": 150, "1002.00-2003.60": 250},
+			expectedRounding:   3,
+		},
+		{
+			name:               "Total truncated to the cent",
+			policy:             RoundingPolicy{Step: RoundTotal, Method: RoundTruncate},
+			expectedTotal:      400.7,
+			expectedPerBracket: map[string]float64{"0.00-1002.00": 150.3, "1002.00-2003.60": 250.4},
+			expectedRounding:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := DefaultPipeline().WithRounding(tt.policy)
+
+			result, err := Calculate(req, schedules, pipeline, nil)
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 1e-9)
+			assert.InDeltaMapValues(t, tt.expectedPerBracket, result.PerBracket, 0.0001)
+			assert.InDelta(t, result.TotalTax, sumLines(result.Lines), 1e-9)
+			assert.InDelta(t, tt.expectedTotal/req.Income, result.EffectiveRate, 0.0001)
+			// The next dollar is measured before rounding
+			assert.InDelta(t, 0.25, result.MarginalRate, 0.0001)
+
+			var rounding int
+			for _, step := range result.Trace {
+				if step.Stage == StageRounding {
+					rounding++
+				}
+			}
+			assert.Equal(t, tt.expectedRounding, rounding)
+		})
+	}
+}
+
+func TestCalculate_RoundingBlended(t *testing.T) {
+	// Both schedules tax 0.15 on the first 1002 and 0.25 above, but the
+	// second splits the upper band, so prorating leaves fractions of dollars
+	schedules := []TaxSchedule{
+		{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-07-01", Brackets: []TaxBracket{
+			{Min: 0, Max: 1002, Rate: 0.15},
+			{Min: 1002, Max: 0, Rate: 0.25},
+		}},
+		{EffectiveFrom: "2022-07-02", EffectiveTo: "2022-12-31", Brackets: []TaxBracket{
+			{Min: 0, Max: 1002, Rate: 0.15},
+			{Min: 1002, Max: 1500, Rate: 0.25},
+			{Min: 1500, Max: 0, Rate: 0.25},
+		}},
+	}
+	req := TaxRequest{Income: 2003.6, Year: 2022}
+
+	tests := []struct {
+		name   string
+		policy RoundingPolicy
+		rules  []RuleSpec
+	}{
+		{name: "Each bracket rounded to the nearest dollar", policy: RoundingPolicy{Step: RoundBracket, Increment: 1}},
+		{name: "Total rounded to the nearest dollar", policy: RoundingPolicy{Step: RoundTotal, Increment: 1}},
+		{
+			name:   "Bracket rounding under a clawback",
+			policy: RoundingPolicy{Step: RoundBracket, Increment: 1},
+			rules:  []RuleSpec{{Type: RuleBrackets}, {Type: RuleClawback, Rate: 0.033, Threshold: 1000}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := DefaultPipeline()
+			if tt.rules != nil {
+				var err error
+				pipeline, err = NewPipeline(tt.rules)
+				assert.NoError(t, err)
+			}
+			pipeline = pipeline.WithRounding(tt.policy)
+
+			result, err := Calculate(req, schedules, pipeline, nil)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.policy.Round(result.TotalTax), result.TotalTax)
+			assert.InDelta(t, result.TotalTax, sumLines(result.Lines), 1e-9)
+			if tt.policy.Step == RoundBracket {
+				var bands float64
+				for _, v := range result.PerBracket {
+					assert.Equal(t, tt.policy.Round(v), v)
+					bands += v
+				}
+				assert.InDelta(t, result.BasicTax, bands, 1e-9)
+				assert.InDelta(t, result.BasicTax, result.Lines[0].Amount, 1e-9)
+			}
+		})
+	}
+}
+
+func sumLines(lines []TaxLine) float64 {
+	var sum float64
+	for _, line := range lines {
+		sum += line.Amount
+	}
+	return sum
+}
@@ -0,0 +1,280 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Built-in rule types
+const (
+	RuleCapitalGains = "capital_gains"
+	RuleBrackets     = "brackets"
+	RuleFlat         = "flat"
+	RuleSurtax       = "surtax"
+	RuleCredit       = "credit"
+	RuleClawback     = "clawback"
+	RuleMinimumTax   = "minimum_tax"
+)
+
+func init() {
+	RegisterRuleType(RuleCapitalGains, func(spec RuleSpec) (TaxRule, error) {
+		if err := validateRate(spec.Rate); err != nil {
+			return nil, err
+		}
+		return capitalGainsRule{name: spec.Name, inclusion: spec.Rate}, nil
+	})
+	RegisterRuleType(RuleBrackets, func(spec RuleSpec) (TaxRule, error) {
+		return bracketsRule{name: spec.Name}, nil
+	})
+	RegisterRuleType(RuleFlat, func(spec RuleSpec) (TaxRule, error) {
+		if err := validateRate(spec.Rate); err != nil {
+			return nil, err
+		}
+		return flatRule{name: spec.Name, rate: spec.Rate, threshold: spec.Threshold, prorate: spec.Prorate}, nil
+	})
+	RegisterRuleType(RuleSurtax, func(spec RuleSpec) (TaxRule, error) {
+		tiers := spec.Tiers
+		if len(tiers) == 0 {
+			tiers = []RuleTier{{Threshold: spec.Threshold, Rate: spec.Rate}}
+		}
+		for _, tier := range tiers {
+			if err := validateRate(tier.Rate); err != nil {
+				return nil, err
+			}
+			if tier.Threshold < 0 {
+				return nil, errors.New("threshold must be non-negative")
+			}
+		}
+		return surtaxRule{name: spec.Name, tiers: tiers, prorate: spec.Prorate}, nil
+	})
+	RegisterRuleType(RuleCredit, func(spec RuleSpec) (TaxRule, error) {
+		if err := validateRate(spec.Rate); err != nil {
+			return nil, err
+		}
+		if spec.Amount < 0 {
+			return nil, errors.New("amount must be non-negative")
+		}
+		return creditRule{name: spec.Name, rate: spec.Rate, amount: spec.Amount, prorate: spec.Prorate}, nil
+	})
+	RegisterRuleType(RuleClawback, func(spec RuleSpec) (TaxRule, error) {
+		if err := validateRate(spec.Rate); err != nil {
+			return nil, err
+		}
+		return clawbackRule{name: spec.Name, rate: spec.Rate, threshold: spec.Threshold, amount: spec.Amount, prorate: spec.Prorate}, nil
+	})
+	RegisterRuleType(RuleMinimumTax, func(spec RuleSpec) (TaxRule, error) {
+		if err := validateRate(spec.Rate); err != nil {
+			return nil, err
+		}
+		return minimumTaxRule{name: spec.Name, rate: spec.Rate, threshold: spec.Threshold, prorate: spec.Prorate}, nil
+	})
+}
+
+func validateRate(rate float64) error {
+	if rate < 0 || rate > 1 || math.IsNaN(rate) {
+		return fmt.Errorf("rate %v must be between 0 and 1", rate)
+	}
+	return nil
+}
+
+// capitalGainsRule deducts the non-taxable part of capital gains, leaving
+// only rate × gains in taxable income. Without it gains are taxed in full.
+type capitalGainsRule struct {
+	name      string
+	inclusion float64
+}
+
+func (r capitalGainsRule) Name() string { return r.name }
+func (r capitalGainsRule) Phase() Phase { return PhaseIncome }
+
+func (r capitalGainsRule) Apply(c *Computation) {
+	deduction := c.Request.CapitalGains * (1 - r.inclusion)
+	c.Trace.Addf(StageDeduction, fmt.Sprintf("%.2f × (1 - %.4f) = %.2f", c.Request.CapitalGains, r.inclusion, deduction), deduction,
+		"Non-taxable part of capital gains")
+	c.Income -= deduction
+	c.ExcludedCapitalGains += deduction
+}
+
+// bracketsRule applies the progressive brackets of the schedule
+type bracketsRule struct{ name string }
+
+func (r bracketsRule) Name() string { return r.name }
+func (r bracketsRule) Phase() Phase { return PhaseBase }
+
+func (r bracketsRule) Apply(c *Computation) {
+	result := ComputeTaxRounded(c.Income, c.Brackets, c.Rounding, c.Trace)
+	for k, v := range result.PerBracket {
+		c.PerBracket[k] += v
+	}
+	c.BasicTax += result.TotalTax
+	c.AddLine(r, RuleBrackets, result.TotalTax)
+}
+
+// flatRule taxes income above a threshold at a single rate
+type flatRule struct {
+	name      string
+	rate      float64
+	threshold float64
+	prorate   bool
+}
+
+func (r flatRule) Name() string { return r.name }
+func (r flatRule) Phase() Phase { return PhaseBase }
+
+func (r flatRule) Apply(c *Computation) {
+	taxFor := func(factor float64) float64 {
+		return math.Max(0, c.Income-r.threshold*factor) * r.rate
+	}
+	threshold := c.Prorated(r.threshold, r.prorate)
+	tax := taxFor(c.Factor(r.prorate))
+	c.Trace.Addf(StageBracket, fmt.Sprintf("max(0, %.2f - %.2f) × %.4f = %.2f", c.Income, threshold, r.rate, tax), tax,
+		"Flat tax %s", r.name)
+	c.BasicTax += tax
+	c.AddProratedLine(r, RuleFlat, tax, taxFor(1), r.prorate)
+}
+
+// Kinks returns the threshold
+func (r flatRule) Kinks(KinkContext) []float64 {
+	return []float64{r.threshold}
+}
+
+// surtaxRule is a tax on tax: each tier charges its rate on the basic tax
+// above the tier's threshold, and the tiers add up. It runs after the
+// brackets and before credits, so the base is the tax the brackets produced.
+type surtaxRule struct {
+	name    string
+	tiers   []RuleTier
+	prorate bool
+}
+
+func (r surtaxRule) Name() string { return r.name }
+func (r surtaxRule) Phase() Phase { return PhaseSurtax }
+
+func (r surtaxRule) Apply(c *Computation) {
+	var surtax, fullYear float64
+	for _, tier := range r.tiers {
+		threshold := c.Prorated(tier.Threshold, r.prorate)
+		tax := math.Max(0, c.BasicTax-threshold) * tier.Rate
+		c.Trace.Addf(StageSurtax, fmt.Sprintf("max(0, %.2f - %.2f) × %.4f = %.2f", c.BasicTax, threshold, tier.Rate, tax), tax,
+			"Surtax %s on basic tax above %.2f", r.name, threshold)
+		surtax += tax
+		fullYear += math.Max(0, c.BasicTax-tier.Threshold) * tier.Rate
+	}
+	c.AddProratedLine(r, RuleSurtax, surtax, fullYear, r.prorate)
+}
+
+// Kinks returns the incomes at which the brackets bring the basic tax to
+// each tier's threshold
+func (r surtaxRule) Kinks(k KinkContext) []float64 {
+	var kinks []float64
+	for _, tier := range r.tiers {
+		if income, ok := incomeAtTax(tier.Threshold, k.Brackets); ok {
+			kinks = append(kinks, income)
+		}
+	}
+	return kinks
+}
+
+// creditRule is a non-refundable credit worth rate × amount, limited to the
+// tax still owing
+type creditRule struct {
+	name    string
+	rate    float64
+	amount  float64
+	prorate bool
+}
+
+func (r creditRule) Name() string { return r.name }
+func (r creditRule) Phase() Phase { return PhaseCredit }
+
+func (r creditRule) Apply(c *Computation) {
+	amount := c.Prorated(r.amount, r.prorate)
+	credit := math.Min(amount*r.rate, math.Max(0, c.TotalTax))
+	fullYear := math.Min(r.amount*r.rate, math.Max(0, c.TotalTax))
+	c.Trace.Addf(StageCredit, fmt.Sprintf("min(%.2f × %.4f, %.2f) = %.2f", amount, r.rate, c.TotalTax, credit), -credit,
+		"Credit %s", r.name)
+	c.AddProratedLine(r, RuleCredit, -credit, -fullYear, r.prorate)
+}
+
+// Kinks returns the income at which the tax still owing before the credit
+// equals it, where the credit runs out
+func (r creditRule) Kinks(k KinkContext) []float64 {
+	credit := r.amount * r.rate
+	return crossings(k.Kinks, func(income float64) float64 {
+		return k.Before(income).TotalTax - credit
+	})
+}
+
+// clawbackRule recovers a benefit at a rate on income above a threshold.
+// A positive amount caps the recovery at the benefit received.
+type clawbackRule struct {
+	name      string
+	rate      float64
+	threshold float64
+	amount    float64
+	prorate   bool
+}
+
+func (r clawbackRule) Name() string { return r.name }
+func (r clawbackRule) Phase() Phase { return PhaseClawback }
+
+func (r clawbackRule) Apply(c *Computation) {
+	clawbackFor := func(factor float64) float64 {
+		clawback := math.Max(0, c.Income-r.threshold*factor) * r.rate
+		if r.amount > 0 {
+			clawback = math.Min(clawback, r.amount*factor)
+		}
+		return clawback
+	}
+	threshold := c.Prorated(r.threshold, r.prorate)
+	clawback := clawbackFor(c.Factor(r.prorate))
+	c.Trace.Addf(StageClawback, fmt.Sprintf("max(0, %.2f - %.2f) × %.4f = %.2f", c.Income, threshold, r.rate, clawback), clawback,
+		"Clawback %s", r.name)
+	c.AddProratedLine(r, RuleClawback, clawback, clawbackFor(1), r.prorate)
+}
+
+// Kinks returns the threshold and, for a capped clawback, the income at
+// which the whole benefit is recovered
+func (r clawbackRule) Kinks(KinkContext) []float64 {
+	kinks := []float64{r.threshold}
+	if r.amount > 0 && r.rate > 0 {
+		kinks = append(kinks, r.threshold+r.amount/r.rate)
+	}
+	return kinks
+}
+
+// minimumTaxRule raises the total to a flat rate on income above an
+// exemption when the regular tax is lower. It is a plain floor on the
+// taxable income and every line so far; see amtRule for a minimum tax with
+// its own income base and a carry-forward.
+type minimumTaxRule struct {
+	name      string
+	rate      float64
+	threshold float64
+	prorate   bool
+}
+
+func (r minimumTaxRule) Name() string { return r.name }
+func (r minimumTaxRule) Phase() Phase { return PhaseMinimum }
+
+func (r minimumTaxRule) Apply(c *Computation) {
+	topUpFor := func(factor float64) float64 {
+		return math.Max(0, math.Max(0, c.Income-r.threshold*factor)*r.rate-c.TotalTax)
+	}
+	minimum := math.Max(0, c.Income-c.Prorated(r.threshold, r.prorate)) * r.rate
+	topUp := topUpFor(c.Factor(r.prorate))
+	c.Trace.Addf(StageMinimum, fmt.Sprintf("max(0, %.2f - %.2f) = %.2f", minimum, c.TotalTax, topUp), topUp,
+		"Minimum tax %s", r.name)
+	c.AddProratedLine(r, RuleMinimumTax, topUp, topUpFor(1), r.prorate)
+}
+
+// Kinks returns the exemption threshold and the incomes at which the minimum
+// tax crosses the tax of the rules before it, where the top-up starts or
+// stops
+func (r minimumTaxRule) Kinks(k KinkContext) []float64 {
+	kinks := sortedKinks(append([]float64{r.threshold}, k.Kinks...))
+	return append([]float64{r.threshold}, crossings(kinks, func(income float64) float64 {
+		return math.Max(0, income-r.threshold)*r.rate - k.Before(income).TotalTax
+	})...)
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinRules(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+
+	tests := []struct {
+		name          string
+		income        float64
+		specs         []RuleSpec
+		expectedTotal float64
+		expectedBasic float64
+		expectedLines []TaxLine
+	}{
+		{
+			name:          "Flat tax above a threshold",
+			income:        50000,
+			specs:         []RuleSpec{{Type: RuleFlat, Rate: 0.05, Threshold: 20000}},
+			expectedTotal: 1500,
+			expectedLines: []TaxLine{{Name: RuleFlat, Type: RuleFlat, Amount: 1500}},
+		},
+		{
+			name:   "Surtax on basic tax",
+			income: 60000,
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleSurtax, Rate: 0.2, Threshold: 5000},
+			},
+			expectedTotal: 10000 + 1000,
+			expectedLines: []TaxLine{
+				{Name: RuleBrackets, Type: RuleBrackets, Amount: 10000},
+				{Name: RuleSurtax, Type: RuleSurtax, Amount: 1000},
+			},
+		},
+		{
+			name:   "Tiered surtax on basic tax",
+			income: 60000,
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleSurtax, Name: "provincial surtax", Tiers: []RuleTier{
+					{Threshold: 5000, Rate: 0.2},
+					{Threshold: 8000, Rate: 0.36},
+				}},
+			},
+			expectedTotal: 10000 + 1000 + 720,
+			expectedBasic: 10000,
+			expectedLines: []TaxLine{
+				{Name: RuleBrackets, Type: RuleBrackets, Amount: 10000},
+				{Name: "provincial surtax", Type: RuleSurtax, Amount: 1720},
+			},
+		},
+		{
+			name:   "Surtax below the first threshold",
+			income: 20000,
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleSurtax, Tiers: []RuleTier{{Threshold: 5000, Rate: 0.2}}},
+			},
+			expectedTotal: 2000,
+			expectedLines: []TaxLine{
+				{Name: RuleBrackets, Type: RuleBrackets, Amount: 2000},
+				{Name: RuleSurtax, Type: RuleSurtax, Amount: 0},
+			},
+		},
+		{
+			name:   "Surtax is charged before credits reduce the tax",
+			income: 60000,
+			specs: []RuleSpec{
+				{Type: RuleCredit, Rate: 1, Amount: 4000},
+				{Type: RuleSurtax, Tiers: []RuleTier{{Threshold: 5000, Rate: 0.2}}},
+				{Type: RuleBrackets},
+			},
+			expectedTotal: 10000 + 1000 - 4000,
+			expectedLines: []TaxLine{
+				{Name: RuleBrackets, Type: RuleBrackets, Amount: 10000},
+				{Name: RuleSurtax, Type: RuleSurtax, Amount: 1000},
+				{Name: RuleCredit, Type: RuleCredit, Amount: -4000},
+			},
+		},
+		{
+			name:   "Credit is limited to tax owing",
+			income: 12000,
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleCredit, Rate: 0.15, Amount: 10000},
+			},
+			expectedTotal: 0,
+			expectedLines: []TaxLine{
+				{Name: RuleBrackets, Type: RuleBrackets, Amount: 400},
+				{Name: RuleCredit, Type: RuleCredit, Amount: -400},
+			},
+		},
+		{
+			name:   "Clawback capped at the benefit",
+			income: 200000,
+			specs: []RuleSpec{
+				{Type: RuleClawback, Rate: 0.15, Threshold: 80000, Amount: 7000},
+			},
+			expectedTotal: 7000,
+			expectedLines: []TaxLine{{Name: RuleClawback, Type: RuleClawback, Amount: 7000}},
+		},
+		{
+			name:   "Minimum tax tops up a low regular tax",
+			income: 50000,
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleCredit, Rate: 1, Amount: 7000},
+				{Type: RuleMinimumTax, Rate: 0.15, Threshold: 40000},
+			},
+			expectedTotal: 1500,
+			expectedLines: []TaxLine{
+				{Name: RuleBrackets, Type: RuleBrackets, Amount: 8000},
+				{Name: RuleCredit, Type: RuleCredit, Amount: -7000},
+				{Name: RuleMinimumTax, Type: RuleMinimumTax, Amount: 500},
+			},
+		},
+		{
+			name:   "Minimum tax below regular tax adds nothing",
+			income: 50000,
+			specs: []RuleSpec{
+				{Type: RuleBrackets},
+				{Type: RuleMinimumTax, Rate: 0.15, Threshold: 40000},
+			},
+			expectedTotal: 8000,
+			expectedLines: []TaxLine{
+				{Name: RuleBrackets, Type: RuleBrackets, Amount: 8000},
+				{Name: RuleMinimumTax, Type: RuleMinimumTax, Amount: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline(tt.specs)
+			assert.NoError(t, err)
+
+			trace := NewTrace(true)
+			result := pipeline.Compute(TaxRequest{Income: tt.income}, brackets, 1, trace)
+
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+			assert.Len(t, result.Lines, len(tt.expectedLines))
+			for i, line := range tt.expectedLines {
+				assert.Equal(t, line.Name, result.Lines[i].Name)
+				assert.Equal(t, line.Type, result.Lines[i].Type)
+				assert.InDelta(t, line.Amount, result.Lines[i].Amount, 0.0001)
+			}
+			if tt.expectedBasic != 0 {
+				assert.InDelta(t, tt.expectedBasic, result.BasicTax, 0.0001)
+			}
+			assert.NotEmpty(t, trace.Steps())
+		})
+	}
+}
+
+func TestClawbackMarginalRate(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	pipeline, err := NewPipeline([]RuleSpec{
+		{Type: RuleBrackets},
+		{Type: RuleClawback, Name: "old age security", Rate: 0.15, Threshold: 80000, Amount: 7000},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		income           float64
+		expectedClawback float64
+		expectedMarginal float64
+	}{
+		{name: "Below the threshold", income: 50000, expectedClawback: 0, expectedMarginal: 0.2},
+		{name: "Inside the clawback zone", income: 100000, expectedClawback: 3000, expectedMarginal: 0.35},
+		{name: "Benefit fully clawed back", income: 200000, expectedClawback: 7000, expectedMarginal: 0.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := pipeline.Compute(TaxRequest{Income: tt.income}, brackets, 1, nil)
+
+			assert.Len(t, result.Lines, 2)
+			assert.Equal(t, "old age security", result.Lines[1].Name)
+			assert.Equal(t, RuleClawback, result.Lines[1].Type)
+			assert.InDelta(t, tt.expectedClawback, result.Lines[1].Amount, 0.0001)
+			assert.InDelta(t, result.BasicTax+tt.expectedClawback, result.TotalTax, 0.0001)
+			assert.InDelta(t, tt.expectedMarginal, result.MarginalRate, 0.0001)
+		})
+	}
+}
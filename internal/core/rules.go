@@ -0,0 +1,353 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Phase orders the rules of a pipeline. Rules run phase by phase, and in
+// configuration order within a phase.
+type Phase int
+
+const (
+	PhaseIncome Phase = iota
+	PhaseBase
+	PhaseSurtax
+	PhaseCredit
+	PhaseClawback
+	PhaseMinimum
+)
+
+type (
+	// TaxRule is one step of the tax pipeline of a jurisdiction and year
+	TaxRule interface {
+		Name() string
+		Phase() Phase
+		Apply(c *Computation)
+	}
+
+	// KinkedRule is a rule whose amount changes slope at taxable incomes of
+	// its own, such as a threshold or the point where a credit runs out, on
+	// top of the bracket boundaries. Solvers and curves evaluate the tax at
+	// these incomes to stay exact.
+	KinkedRule interface {
+		TaxRule
+		Kinks(k KinkContext) []float64
+	}
+
+	// KinkContext is what a rule needs to find its kinks for a taxpayer with
+	// income alone: the brackets, the kinks of the brackets and of the rules
+	// before it, and the computation of those rules at a given income.
+	// Between two consecutive kinks everything Before computes is linear.
+	KinkContext struct {
+		Brackets []TaxBracket
+		Kinks    []float64
+		Before   func(income float64) *Computation
+	}
+
+	// RuleFactory builds a rule from its configuration
+	RuleFactory func(spec RuleSpec) (TaxRule, error)
+
+	// RuleSpec configures a single rule. Which fields are used depends on Type.
+	// Prorate scales the rule's amounts and thresholds by the share of the
+	// year the taxpayer was resident. Deductible is the share of a
+	// contribution deducted from income.
+	RuleSpec struct {
+		Type       string     `mapstructure:"type" json:"type"`
+		Name       string     `mapstructure:"name" json:"name,omitempty"`
+		Rate       float64    `mapstructure:"rate" json:"rate,omitempty"`
+		Threshold  float64    `mapstructure:"threshold" json:"threshold,omitempty"`
+		Amount     float64    `mapstructure:"amount" json:"amount,omitempty"`
+		Inclusion  float64    `mapstructure:"inclusion" json:"inclusion,omitempty"`
+		Prorate    bool       `mapstructure:"prorate" json:"prorate,omitempty"`
+		Deductible float64    `mapstructure:"deductible" json:"deductible,omitempty"`
+		Tiers      []RuleTier `mapstructure:"tiers" json:"tiers,omitempty"`
+	}
+
+	// RuleTier is a rate applied to the part of a base above a threshold
+	RuleTier struct {
+		Threshold float64 `mapstructure:"threshold" json:"threshold"`
+		Rate      float64 `mapstructure:"rate" json:"rate"`
+	}
+
+	// RuleSet is the ordered list of rules for a jurisdiction and year
+	RuleSet struct {
+		Jurisdiction string     `mapstructure:"jurisdiction" json:"jurisdiction"`
+		Year         int        `mapstructure:"year" json:"year"`
+		Rules        []RuleSpec `mapstructure:"rules" json:"rules"`
+	}
+
+	// TaxLine is the amount a single rule added to (or, for credits,
+	// removed from) the total tax. Prorated lines also report the amount a
+	// full-year resident would have had.
+	TaxLine struct {
+		Name           string  `json:"name"`
+		Type           string  `json:"type"`
+		Amount         float64 `json:"amount"`
+		Prorated       bool    `json:"prorated,omitempty"`
+		FullYearAmount float64 `json:"full_year_amount,omitempty"`
+	}
+
+	// Computation is the state rules read and update as the pipeline runs.
+	// Income starts as the total income and income rules adjust it to the
+	// taxable income the later phases apply to. GrossedUpDividends starts as
+	// the dividends received and includes their gross-up once applied.
+	// ExcludedCapitalGains is the part of the capital gains income rules
+	// left out of Income.
+	// Contributions are charged alongside the tax but are not tax, so they
+	// join TotalTax and Lines only once every rule has run.
+	Computation struct {
+		Request              TaxRequest
+		Income               float64
+		GrossedUpDividends   float64
+		ExcludedCapitalGains float64
+		Brackets             []TaxBracket
+		BasicTax             float64
+		TotalTax             float64
+		PerBracket           map[string]float64
+		Lines                []TaxLine
+		Contributions        []TaxLine
+		AMT                  *AMTResult
+		SelfEmployment       *SelfEmploymentResult
+		Residency            float64
+		Rounding             RoundingPolicy
+		Trace                *Trace
+	}
+
+	// Pipeline is a compiled, phase-ordered list of rules and the rounding
+	// policy of its jurisdiction and year
+	Pipeline struct {
+		rules    []TaxRule
+		rounding RoundingPolicy
+	}
+)
+
+var (
+	ruleTypesMu sync.RWMutex
+	ruleTypes   = map[string]RuleFactory{}
+)
+
+// RegisterRuleType makes a rule type available to configuration.
+// Registering an existing type replaces it.
+func RegisterRuleType(name string, factory RuleFactory) {
+	ruleTypesMu.Lock()
+	defer ruleTypesMu.Unlock()
+	ruleTypes[name] = factory
+}
+
+// NewRule builds a rule from its configuration
+func NewRule(spec RuleSpec) (TaxRule, error) {
+	ruleTypesMu.RLock()
+	factory, ok := ruleTypes[spec.Type]
+	ruleTypesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown rule type %q", spec.Type)
+	}
+	if spec.Name == "" {
+		spec.Name = spec.Type
+	}
+	rule, err := factory(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s rule %q: %w", spec.Type, spec.Name, err)
+	}
+	return rule, nil
+}
+
+// NewPipeline builds the rules and sorts them by phase
+func NewPipeline(specs []RuleSpec) (Pipeline, error) {
+	rules := make([]TaxRule, 0, len(specs))
+	for _, spec := range specs {
+		rule, err := NewRule(spec)
+		if err != nil {
+			return Pipeline{}, err
+		}
+		rules = append(rules, rule)
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Phase() < rules[j].Phase() })
+	return Pipeline{rules: rules}, nil
+}
+
+// DefaultPipeline applies the progressive brackets and nothing else
+func DefaultPipeline() Pipeline {
+	return Pipeline{rules: []TaxRule{bracketsRule{name: "brackets"}}}
+}
+
+// WithRounding returns the pipeline rounding under the policy
+func (p Pipeline) WithRounding(policy RoundingPolicy) Pipeline {
+	p.rounding = policy
+	return p
+}
+
+// bracketsOnly reports whether the pipeline applies the brackets and
+// nothing else, without rounding
+func (p Pipeline) bracketsOnly() bool {
+	if len(p.rules) != 1 || p.rounding.Enabled() {
+		return false
+	}
+	_, ok := p.rules[0].(bracketsRule)
+	return ok
+}
+
+// kinks returns the incomes at which the tax of a taxpayer with income alone
+// changes slope under the brackets: the bracket boundaries and the kinks of
+// every rule. Each rule sees the kinks found before it, so it can locate
+// where its amount crosses the tax of the earlier rules.
+func (p Pipeline) kinks(brackets []TaxBracket) []float64 {
+	kinks := Kinks(brackets)
+	for i, rule := range p.rules {
+		kinked, ok := rule.(KinkedRule)
+		if !ok {
+			continue
+		}
+		// The rules before this one, unrounded, so their tax stays linear
+		// between the kinks found so far
+		before := Pipeline{rules: p.rules[:i]}
+		found := kinked.Kinks(KinkContext{
+			Brackets: brackets,
+			Kinks:    kinks,
+			Before: func(income float64) *Computation {
+				return before.run(TaxRequest{Income: income}, brackets, 1, nil)
+			},
+		})
+		kinks = sortedKinks(append(append([]float64{}, kinks...), found...))
+	}
+	return kinks
+}
+
+// Rounding returns the rounding policy of the pipeline
+func (p Pipeline) Rounding() RoundingPolicy {
+	return p.rounding
+}
+
+// FindRuleSet returns the rules configured for the jurisdiction and year
+func FindRuleSet(sets []RuleSet, jurisdiction string, year int) ([]RuleSpec, bool) {
+	for _, set := range sets {
+		if set.Jurisdiction == jurisdiction && set.Year == year {
+			return set.Rules, true
+		}
+	}
+	return nil, false
+}
+
+// ValidateRuleSets checks that every configured rule can be built
+func ValidateRuleSets(sets []RuleSet) error {
+	for _, set := range sets {
+		if _, err := NewPipeline(set.Rules); err != nil {
+			return fmt.Errorf("rule set %q/%d: %w", set.Jurisdiction, set.Year, err)
+		}
+	}
+	return nil
+}
+
+// Compute runs the pipeline for the request against one set of brackets.
+// The marginal rate is the extra tax on the next dollar of income, so it
+// reflects every rule, including surtaxes and clawbacks. It is measured
+// before rounding, which would otherwise turn it into a whole number of
+// increments. residency is the share of the year the taxpayer was resident,
+// 1 for a full year, as returned by ComputeResidency.
+func (p Pipeline) Compute(req TaxRequest, brackets []TaxBracket, residency float64, trace *Trace) TaxResult {
+	c := p.run(req, brackets, residency, trace)
+
+	unrounded, base := p, c.TotalTax
+	if p.rounding.Enabled() {
+		unrounded.rounding = RoundingPolicy{}
+		base = unrounded.run(req, brackets, residency, nil).TotalTax
+	}
+	next := req
+	next.Income++
+	marginalRate := unrounded.run(next, brackets, residency, nil).TotalTax - base
+
+	effectiveRate := 0.0
+	if req.TotalIncome() > 0 {
+		effectiveRate = c.TotalTax / req.TotalIncome()
+	}
+
+	return TaxResult{
+		TotalTax:       c.TotalTax,
+		PerBracket:     c.PerBracket,
+		EffectiveRate:  effectiveRate,
+		MarginalRate:   marginalRate,
+		BasicTax:       c.BasicTax,
+		Lines:          c.Lines,
+		AMT:            c.AMT,
+		SelfEmployment: c.SelfEmployment,
+	}
+}
+
+func (p Pipeline) run(req TaxRequest, brackets []TaxBracket, residency float64, trace *Trace) *Computation {
+	c := &Computation{
+		Request:            req,
+		Income:             req.TotalIncome(),
+		GrossedUpDividends: req.Dividends,
+		Brackets:           brackets,
+		PerBracket:         make(map[string]float64),
+		Residency:          residency,
+		Rounding:           p.rounding,
+		Trace:              trace,
+	}
+
+	for _, rule := range p.rules {
+		rule.Apply(c)
+	}
+	for _, line := range c.Contributions {
+		c.TotalTax += line.Amount
+		c.Lines = append(c.Lines, line)
+	}
+
+	return c
+}
+
+// AddLine records the amount a rule contributed to the total tax
+func (c *Computation) AddLine(rule TaxRule, ruleType string, amount float64) {
+	c.TotalTax += amount
+	c.Lines = append(c.Lines, TaxLine{Name: rule.Name(), Type: ruleType, Amount: amount})
+}
+
+// LineTotal returns the sum of the lines of the rule type recorded so far
+func (c *Computation) LineTotal(ruleType string) float64 {
+	var total float64
+	for _, line := range c.Lines {
+		if line.Type == ruleType {
+			total += line.Amount
+		}
+	}
+	return total
+}
+
+// AddContribution records a contribution the rule charges on top of the
+// tax. Credits, clawbacks and minimum taxes do not see it.
+func (c *Computation) AddContribution(rule TaxRule, ruleType string, amount float64) {
+	c.Contributions = append(c.Contributions, TaxLine{Name: rule.Name(), Type: ruleType, Amount: amount})
+}
+
+// AddProratedLine records the contribution of a rule that may be prorated
+// for residency, along with its full-year equivalent
+func (c *Computation) AddProratedLine(rule TaxRule, ruleType string, amount, fullYear float64, prorate bool) {
+	if !prorate || c.Residency == 1 {
+		c.AddLine(rule, ruleType, amount)
+		return
+	}
+	c.TotalTax += amount
+	c.Lines = append(c.Lines, TaxLine{
+		Name:           rule.Name(),
+		Type:           ruleType,
+		Amount:         amount,
+		Prorated:       true,
+		FullYearAmount: fullYear,
+	})
+}
+
+// Factor returns the residency factor when the rule is prorated, 1 otherwise
+func (c *Computation) Factor(prorate bool) float64 {
+	if !prorate {
+		return 1
+	}
+	return c.Residency
+}
+
+// Prorated scales an amount or threshold by the residency factor when the
+// rule is prorated
+func (c *Computation) Prorated(v float64, prorate bool) float64 {
+	return v * c.Factor(prorate)
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedRule adds a fixed amount, used to check ordering and registration
+type fixedRule struct {
+	name   string
+	phase  Phase
+	amount float64
+}
+
+func (r fixedRule) Name() string         { return r.name }
+func (r fixedRule) Phase() Phase         { return r.phase }
+func (r fixedRule) Apply(c *Computation) { c.AddLine(r, "fixed", r.amount) }
+
+func TestNewRule(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          RuleSpec
+		expectedName  string
+		expectedError string
+	}{
+		{name: "Name defaults to type", spec: RuleSpec{Type: RuleBrackets}, expectedName: RuleBrackets},
+		{name: "Explicit name", spec: RuleSpec{Type: RuleFlat, Name: "health levy", Rate: 0.02}, expectedName: "health levy"},
+		{name: "Unknown type", spec: RuleSpec{Type: "lottery"}, expectedError: `unknown rule type "lottery"`},
+		{name: "Invalid rate", spec: RuleSpec{Type: RuleSurtax, Rate: 1.5}, expectedError: "rate 1.5 must be between 0 and 1"},
+		{name: "Invalid surtax tier rate", spec: RuleSpec{Type: RuleSurtax, Tiers: []RuleTier{{Threshold: 100, Rate: 2}}}, expectedError: "rate 2 must be between 0 and 1"},
+		{name: "Negative surtax tier threshold", spec: RuleSpec{Type: RuleSurtax, Tiers: []RuleTier{{Threshold: -1, Rate: 0.2}}}, expectedError: "threshold must be non-negative"},
+		{name: "Negative credit amount", spec: RuleSpec{Type: RuleCredit, Rate: 0.15, Amount: -1}, expectedError: "amount must be non-negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := NewRule(tt.spec)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedName, rule.Name())
+		})
+	}
+}
+
+func TestRegisterRuleType(t *testing.T) {
+	RegisterRuleType("test_fixed", func(spec RuleSpec) (TaxRule, error) {
+		return fixedRule{name: spec.Name, phase: PhaseBase, amount: spec.Amount}, nil
+	})
+
+	pipeline, err := NewPipeline([]RuleSpec{{Type: "test_fixed", Amount: 42}})
+	assert.NoError(t, err)
+
+	result := pipeline.Compute(TaxRequest{Income: 1000}, nil, 1, nil)
+	assert.Equal(t, 42.0, result.TotalTax)
+	assert.Equal(t, []TaxLine{{Name: "test_fixed", Type: "fixed", Amount: 42}}, result.Lines)
+}
+
+func TestPipeline_PhaseOrder(t *testing.T) {
+	// The phase is passed through the amount so the specs can be shuffled
+	RegisterRuleType("test_phase", func(spec RuleSpec) (TaxRule, error) {
+		return fixedRule{name: spec.Name, phase: Phase(spec.Amount), amount: spec.Amount}, nil
+	})
+	sorted, err := NewPipeline([]RuleSpec{
+		{Type: "test_phase", Name: "minimum", Amount: float64(PhaseMinimum)},
+		{Type: "test_phase", Name: "credit", Amount: float64(PhaseCredit)},
+		{Type: "test_phase", Name: "base", Amount: float64(PhaseBase)},
+		{Type: "test_phase", Name: "surtax", Amount: float64(PhaseSurtax)},
+	})
+	assert.NoError(t, err)
+
+	result := sorted.Compute(TaxRequest{}, nil, 1, nil)
+	names := make([]string, len(result.Lines))
+	for i, line := range result.Lines {
+		names[i] = line.Name
+	}
+	assert.Equal(t, []string{"base", "surtax", "credit", "minimum"}, names)
+}
+
+func TestFindRuleSet(t *testing.T) {
+	sets := []RuleSet{
+		{Jurisdiction: "", Year: 2022, Rules: []RuleSpec{{Type: RuleBrackets}}},
+		{Jurisdiction: "ON", Year: 2022, Rules: []RuleSpec{{Type: RuleBrackets}, {Type: RuleSurtax, Rate: 0.2}}},
+	}
+
+	tests := []struct {
+		name          string
+		jurisdiction  string
+		year          int
+		expectedFound bool
+		expectedRules int
+	}{
+		{name: "Default jurisdiction", jurisdiction: "", year: 2022, expectedFound: true, expectedRules: 1},
+		{name: "Named jurisdiction", jurisdiction: "ON", year: 2022, expectedFound: true, expectedRules: 2},
+		{name: "Unknown year", jurisdiction: "ON", year: 2021, expectedFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, found := FindRuleSet(sets, tt.jurisdiction, tt.year)
+			assert.Equal(t, tt.expectedFound, found)
+			assert.Len(t, rules, tt.expectedRules)
+		})
+	}
+}
+
+func TestValidateRuleSets(t *testing.T) {
+	assert.NoError(t, ValidateRuleSets([]RuleSet{{Year: 2022, Rules: []RuleSpec{{Type: RuleBrackets}}}}))
+
+	err := ValidateRuleSets([]RuleSet{{Jurisdiction: "ON", Year: 2022, Rules: []RuleSpec{{Type: "unknown"}}}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `rule set "ON"/2022`)
+}
+
+func TestDefaultPipeline(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0.1},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+
+	result := DefaultPipeline().Compute(TaxRequest{Income: 30000}, brackets, 1, nil)
+	plain := ComputeTax(30000, brackets)
+
+	assert.Equal(t, plain.TotalTax, result.TotalTax)
+	assert.Equal(t, plain.PerBracket, result.PerBracket)
+	assert.Equal(t, plain.EffectiveRate, result.EffectiveRate)
+	assert.Equal(t, []TaxLine{{Name: "brackets", Type: RuleBrackets, Amount: plain.TotalTax}}, result.Lines)
+}
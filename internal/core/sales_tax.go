@@ -0,0 +1,254 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Sales tax categories. Zero-rated supplies are taxable at a rate of zero,
+// exempt supplies are outside the tax altogether; both carry no tax but
+// invoices report them separately.
+const (
+	CategoryStandard  = "standard"
+	CategoryZeroRated = "zero_rated"
+	CategoryExempt    = "exempt"
+)
+
+var ErrNoSalesTaxRate = errors.New("no sales tax rate in effect")
+
+type (
+	// SalesTaxComponent is one tax stacked on a sale, such as the federal
+	// and provincial parts of a harmonized rate. A compound component is
+	// levied on the price plus the components before it.
+	SalesTaxComponent struct {
+		Name     string  `json:"name"`
+		Rate     float64 `json:"rate"`
+		Compound bool    `json:"compound,omitempty"`
+	}
+
+	// SalesTaxRate is the set of components of a jurisdiction in effect
+	// between two dates, both inclusive and formatted with DateFormat. An
+	// empty EffectiveTo means the rate has no end date.
+	SalesTaxRate struct {
+		Jurisdiction  string              `json:"jurisdiction"`
+		EffectiveFrom string              `json:"effective_from"`
+		EffectiveTo   string              `json:"effective_to,omitempty"`
+		Components    []SalesTaxComponent `json:"components"`
+		Source        string              `json:"source,omitempty"`
+		Version       string              `json:"version,omitempty"`
+	}
+
+	// SalesTaxItem is one line of an invoice. The category defaults to
+	// CategoryStandard.
+	SalesTaxItem struct {
+		Description string  `json:"description,omitempty"`
+		Amount      float64 `json:"amount"`
+		Category    string  `json:"category,omitempty"`
+	}
+
+	// SalesTaxRequest holds an invoice. Inclusive means the item amounts
+	// already include the tax.
+	SalesTaxRequest struct {
+		Jurisdiction string         `json:"jurisdiction"`
+		Date         string         `json:"date"`
+		Inclusive    bool           `json:"inclusive,omitempty"`
+		Items        []SalesTaxItem `json:"items"`
+	}
+
+	// SalesTaxLine is the tax of one component, per item or for the invoice
+	SalesTaxLine struct {
+		Name   string  `json:"name"`
+		Rate   float64 `json:"rate"`
+		Amount float64 `json:"amount"`
+	}
+
+	// SalesTaxItemResult splits an item into its price before tax and its tax
+	SalesTaxItemResult struct {
+		Description string         `json:"description,omitempty"`
+		Category    string         `json:"category"`
+		Net         float64        `json:"net"`
+		Tax         float64        `json:"tax"`
+		Gross       float64        `json:"gross"`
+		Components  []SalesTaxLine `json:"components,omitempty"`
+	}
+
+	// SalesTaxResult is the tax of an invoice, rounded to cents per item and
+	// component. Net plus Tax always equals Gross.
+	SalesTaxResult struct {
+		Jurisdiction string               `json:"jurisdiction"`
+		Date         string               `json:"date"`
+		Inclusive    bool                 `json:"inclusive"`
+		Net          float64              `json:"net"`
+		Tax          float64              `json:"tax"`
+		Gross        float64              `json:"gross"`
+		Components   []SalesTaxLine       `json:"components"`
+		Items        []SalesTaxItemResult `json:"items"`
+		Source       string               `json:"source,omitempty"`
+		Version      string               `json:"version,omitempty"`
+	}
+)
+
+// Dates parses the effective dates of the rate. An open end date is
+// returned as the zero time.
+func (r SalesTaxRate) Dates() (from, to time.Time, err error) {
+	return TaxSchedule{EffectiveFrom: r.EffectiveFrom, EffectiveTo: r.EffectiveTo}.Dates()
+}
+
+// Validate checks the dates and that every component rate is between 0 and 1
+func (r SalesTaxRate) Validate() error {
+	if _, _, err := r.Dates(); err != nil {
+		return err
+	}
+	for _, c := range r.Components {
+		if err := validateRate(c.Rate); err != nil {
+			return fmt.Errorf("component %q: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// SalesTaxRateAsOf returns the rate of the jurisdiction in effect on the date
+func SalesTaxRateAsOf(rates []SalesTaxRate, jurisdiction string, date time.Time) (SalesTaxRate, error) {
+	for _, r := range rates {
+		if r.Jurisdiction != jurisdiction {
+			continue
+		}
+		from, to, err := r.Dates()
+		if err != nil {
+			return SalesTaxRate{}, err
+		}
+		if !date.Before(from) && (to.IsZero() || !date.After(to)) {
+			return r, nil
+		}
+	}
+	return SalesTaxRate{}, fmt.Errorf("%w for %q on %s", ErrNoSalesTaxRate, jurisdiction, date.Format(DateFormat))
+}
+
+// ComputeSalesTax taxes every item of the invoice at the components of the
+// rate in effect on its date. Tax-inclusive amounts are split back into the
+// price and the tax, so that the gross stays the amount charged.
+func ComputeSalesTax(req SalesTaxRequest, rates []SalesTaxRate) (SalesTaxResult, error) {
+	date, err := time.Parse(DateFormat, req.Date)
+	if err != nil {
+		return SalesTaxResult{}, fmt.Errorf("invalid date %q: %w", req.Date, err)
+	}
+
+	rate, err := SalesTaxRateAsOf(rates, req.Jurisdiction, date)
+	if err != nil {
+		return SalesTaxResult{}, err
+	}
+	if err := rate.Validate(); err != nil {
+		return SalesTaxResult{}, fmt.Errorf("invalid sales tax rate for %q: %w", req.Jurisdiction, err)
+	}
+
+	result := SalesTaxResult{
+		Jurisdiction: req.Jurisdiction,
+		Date:         req.Date,
+		Inclusive:    req.Inclusive,
+		Components:   make([]SalesTaxLine, len(rate.Components)),
+		Source:       rate.Source,
+		Version:      rate.Version,
+	}
+	for i, c := range rate.Components {
+		result.Components[i] = SalesTaxLine{Name: c.Name, Rate: c.Rate}
+	}
+
+	for _, item := range req.Items {
+		itemResult, err := computeSalesTaxItem(item, rate.Components, req.Inclusive)
+		if err != nil {
+			return SalesTaxResult{}, err
+		}
+		for i, line := range itemResult.Components {
+			result.Components[i].Amount = roundCents(result.Components[i].Amount + line.Amount)
+		}
+		result.Net = roundCents(result.Net + itemResult.Net)
+		result.Tax = roundCents(result.Tax + itemResult.Tax)
+		result.Gross = roundCents(result.Gross + itemResult.Gross)
+		result.Items = append(result.Items, itemResult)
+	}
+
+	return result, nil
+}
+
+// computeSalesTaxItem splits one item into its net price and the tax of each
+// component
+func computeSalesTaxItem(item SalesTaxItem, components []SalesTaxComponent, inclusive bool) (SalesTaxItemResult, error) {
+	category := item.Category
+	if category == "" {
+		category = CategoryStandard
+	}
+	if item.Amount < 0 {
+		return SalesTaxItemResult{}, fmt.Errorf("item %q: amount must be non-negative", item.Description)
+	}
+
+	result := SalesTaxItemResult{Description: item.Description, Category: category}
+	switch category {
+	case CategoryExempt:
+		// Exempt supplies carry no tax components at all
+		result.Net, result.Gross = roundCents(item.Amount), roundCents(item.Amount)
+		return result, nil
+	case CategoryZeroRated:
+		result.Net, result.Gross = roundCents(item.Amount), roundCents(item.Amount)
+		for _, c := range components {
+			result.Components = append(result.Components, SalesTaxLine{Name: c.Name})
+		}
+		return result, nil
+	case CategoryStandard:
+	default:
+		return SalesTaxItemResult{}, fmt.Errorf("item %q: unknown category %q", item.Description, item.Category)
+	}
+
+	// The tax is linear in the price, so the tax on one dollar gives the
+	// price back from a tax-inclusive amount
+	net := item.Amount
+	if inclusive {
+		net = item.Amount / (1 + stackedTax(1, components, nil))
+	}
+
+	lines := make([]SalesTaxLine, 0, len(components))
+	stackedTax(net, components, func(c SalesTaxComponent, tax float64) {
+		lines = append(lines, SalesTaxLine{Name: c.Name, Rate: c.Rate, Amount: roundCents(tax)})
+	})
+
+	for _, line := range lines {
+		result.Tax += line.Amount
+	}
+	result.Tax = roundCents(result.Tax)
+	result.Components = lines
+
+	if inclusive {
+		// Rounding lands on the net price, the amount charged is fixed
+		result.Gross = roundCents(item.Amount)
+		result.Net = roundCents(result.Gross - result.Tax)
+	} else {
+		result.Net = roundCents(net)
+		result.Gross = roundCents(result.Net + result.Tax)
+	}
+
+	return result, nil
+}
+
+// stackedTax returns the total tax of the components on a net price,
+// calling fn with the tax of each component when it is not nil
+func stackedTax(net float64, components []SalesTaxComponent, fn func(SalesTaxComponent, float64)) float64 {
+	total := 0.0
+	for _, c := range components {
+		base := net
+		if c.Compound {
+			base += total
+		}
+		tax := base * c.Rate
+		if fn != nil {
+			fn(c, tax)
+		}
+		total += tax
+	}
+	return total
+}
+
+// roundCents rounds an amount to the nearest cent
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
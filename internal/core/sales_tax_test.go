@@ -0,0 +1,180 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeSalesTax(t *testing.T) {
+	rates := []SalesTaxRate{
+		{
+			Jurisdiction:  "ON",
+			EffectiveFrom: "2008-01-01",
+			EffectiveTo:   "2010-06-30",
+			Components:    []SalesTaxComponent{{Name: "gst", Rate: 0.05}},
+		},
+		{
+			Jurisdiction:  "ON",
+			EffectiveFrom: "2010-07-01",
+			Components:    []SalesTaxComponent{{Name: "federal", Rate: 0.05}, {Name: "provincial", Rate: 0.08}},
+		},
+		{
+			Jurisdiction:  "QC",
+			EffectiveFrom: "2012-01-01",
+			EffectiveTo:   "2012-12-31",
+			Components:    []SalesTaxComponent{{Name: "gst", Rate: 0.05}, {Name: "qst", Rate: 0.095, Compound: true}},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		req                SalesTaxRequest
+		expectedNet        float64
+		expectedTax        float64
+		expectedGross      float64
+		expectedComponents []SalesTaxLine
+		expectedError      string
+	}{
+		{
+			name:          "Tax-exclusive with stacked components",
+			req:           SalesTaxRequest{Jurisdiction: "ON", Date: "2022-05-01", Items: []SalesTaxItem{{Amount: 100}}},
+			expectedNet:   100,
+			expectedTax:   13,
+			expectedGross: 113,
+			expectedComponents: []SalesTaxLine{
+				{Name: "federal", Rate: 0.05, Amount: 5},
+				{Name: "provincial", Rate: 0.08, Amount: 8},
+			},
+		},
+		{
+			name:          "Tax-inclusive",
+			req:           SalesTaxRequest{Jurisdiction: "ON", Date: "2022-05-01", Inclusive: true, Items: []SalesTaxItem{{Amount: 113}}},
+			expectedNet:   100,
+			expectedTax:   13,
+			expectedGross: 113,
+			expectedComponents: []SalesTaxLine{
+				{Name: "federal", Rate: 0.05, Amount: 5},
+				{Name: "provincial", Rate: 0.08, Amount: 8},
+			},
+		},
+		{
+			name:          "Tax-inclusive rounding lands on the net price",
+			req:           SalesTaxRequest{Jurisdiction: "ON", Date: "2022-05-01", Inclusive: true, Items: []SalesTaxItem{{Amount: 10}}},
+			expectedNet:   8.85,
+			expectedTax:   1.15,
+			expectedGross: 10,
+			expectedComponents: []SalesTaxLine{
+				{Name: "federal", Rate: 0.05, Amount: 0.44},
+				{Name: "provincial", Rate: 0.08, Amount: 0.71},
+			},
+		},
+		{
+			name:          "Rate in effect on the date",
+			req:           SalesTaxRequest{Jurisdiction: "ON", Date: "2010-06-30", Items: []SalesTaxItem{{Amount: 100}}},
+			expectedNet:   100,
+			expectedTax:   5,
+			expectedGross: 105,
+			expectedComponents: []SalesTaxLine{
+				{Name: "gst", Rate: 0.05, Amount: 5},
+			},
+		},
+		{
+			name:          "Compound component on the price plus the tax before it",
+			req:           SalesTaxRequest{Jurisdiction: "QC", Date: "2012-03-01", Items: []SalesTaxItem{{Amount: 100}}},
+			expectedNet:   100,
+			expectedTax:   14.98,
+			expectedGross: 114.98,
+			expectedComponents: []SalesTaxLine{
+				{Name: "gst", Rate: 0.05, Amount: 5},
+				{Name: "qst", Rate: 0.095, Amount: 9.98},
+			},
+		},
+		{
+			name: "Zero-rated and exempt items carry no tax",
+			req: SalesTaxRequest{Jurisdiction: "ON", Date: "2022-05-01", Items: []SalesTaxItem{
+				{Description: "books", Amount: 100},
+				{Description: "groceries", Amount: 50, Category: CategoryZeroRated},
+				{Description: "rent", Amount: 1000, Category: CategoryExempt},
+			}},
+			expectedNet:   1150,
+			expectedTax:   13,
+			expectedGross: 1163,
+			expectedComponents: []SalesTaxLine{
+				{Name: "federal", Rate: 0.05, Amount: 5},
+				{Name: "provincial", Rate: 0.08, Amount: 8},
+			},
+		},
+		{
+			name:          "No rate for the jurisdiction",
+			req:           SalesTaxRequest{Jurisdiction: "BC", Date: "2022-05-01", Items: []SalesTaxItem{{Amount: 100}}},
+			expectedError: "no sales tax rate in effect",
+		},
+		{
+			name:          "No rate on the date",
+			req:           SalesTaxRequest{Jurisdiction: "QC", Date: "2013-01-01", Items: []SalesTaxItem{{Amount: 100}}},
+			expectedError: "no sales tax rate in effect",
+		},
+		{
+			name:          "Invalid date",
+			req:           SalesTaxRequest{Jurisdiction: "ON", Date: "01/05/2022", Items: []SalesTaxItem{{Amount: 100}}},
+			expectedError: "invalid date",
+		},
+		{
+			name:          "Unknown category",
+			req:           SalesTaxRequest{Jurisdiction: "ON", Date: "2022-05-01", Items: []SalesTaxItem{{Amount: 100, Category: "luxury"}}},
+			expectedError: "unknown category",
+		},
+		{
+			name:          "Negative amount",
+			req:           SalesTaxRequest{Jurisdiction: "ON", Date: "2022-05-01", Items: []SalesTaxItem{{Amount: -1}}},
+			expectedError: "amount must be non-negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeSalesTax(tt.req, rates)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedNet, result.Net, 0.0001)
+			assert.InDelta(t, tt.expectedTax, result.Tax, 0.0001)
+			assert.InDelta(t, tt.expectedGross, result.Gross, 0.0001)
+			assert.InDelta(t, result.Gross, result.Net+result.Tax, 0.0001)
+			assert.Len(t, result.Components, len(tt.expectedComponents))
+			for i, line := range tt.expectedComponents {
+				assert.Equal(t, line.Name, result.Components[i].Name)
+				assert.InDelta(t, line.Rate, result.Components[i].Rate, 0.0001)
+				assert.InDelta(t, line.Amount, result.Components[i].Amount, 0.0001)
+			}
+			assert.Len(t, result.Items, len(tt.req.Items))
+		})
+	}
+}
+
+func TestComputeSalesTax_Categories(t *testing.T) {
+	rates := []SalesTaxRate{{
+		Jurisdiction:  "ON",
+		EffectiveFrom: "2010-07-01",
+		Components:    []SalesTaxComponent{{Name: "federal", Rate: 0.05}, {Name: "provincial", Rate: 0.08}},
+	}}
+
+	result, err := ComputeSalesTax(SalesTaxRequest{Jurisdiction: "ON", Date: "2022-05-01", Items: []SalesTaxItem{
+		{Amount: 100},
+		{Amount: 50, Category: CategoryZeroRated},
+		{Amount: 1000, Category: CategoryExempt},
+	}}, rates)
+	assert.NoError(t, err)
+
+	assert.Equal(t, CategoryStandard, result.Items[0].Category)
+	assert.Len(t, result.Items[0].Components, 2)
+
+	// Zero-rated supplies list the components at no tax, exempt ones none
+	assert.Equal(t, []SalesTaxLine{{Name: "federal"}, {Name: "provincial"}}, result.Items[1].Components)
+	assert.Empty(t, result.Items[2].Components)
+}
@@ -0,0 +1,192 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// MaxScenarios bounds how many scenarios a single request can compare
+const MaxScenarios = 20
+
+// Scenario modification operations
+const (
+	OperationSet = "set"
+	OperationAdd = "add"
+)
+
+// scenarioFields are the request amounts a scenario can modify, by JSON name
+var scenarioFields = map[string]func(*TaxRequest) *float64{
+	"income":             func(r *TaxRequest) *float64 { return &r.Income },
+	"capital_gains":      func(r *TaxRequest) *float64 { return &r.CapitalGains },
+	"business_income":    func(r *TaxRequest) *float64 { return &r.BusinessIncome },
+	"dividends":          func(r *TaxRequest) *float64 { return &r.Dividends },
+	"amt_carryforward":   func(r *TaxRequest) *float64 { return &r.AMTCarryForward },
+	"withheld":           func(r *TaxRequest) *float64 { return &r.Withheld },
+	"instalments":        func(r *TaxRequest) *float64 { return &r.Instalments },
+	"refundable_credits": func(r *TaxRequest) *float64 { return &r.RefundableCredits },
+}
+
+type (
+	// Modification changes one amount of the base profile. Year limits it to
+	// one year of the profile, every year when zero. Operation is
+	// OperationSet, the default, or OperationAdd; a negative value added
+	// moves an amount out of a field.
+	Modification struct {
+		Year      int     `json:"year,omitempty"`
+		Field     string  `json:"field"`
+		Operation string  `json:"operation,omitempty"`
+		Value     float64 `json:"value"`
+	}
+
+	// Scenario is a named list of modifications to the base profile
+	Scenario struct {
+		Name          string         `json:"name"`
+		Modifications []Modification `json:"modifications"`
+	}
+
+	// ScenarioRequest holds a base profile, one request per year, and the
+	// scenarios to compare with it
+	ScenarioRequest struct {
+		Base      []TaxRequest `json:"base"`
+		Scenarios []Scenario   `json:"scenarios"`
+	}
+
+	// ScenarioDifference is a scenario's change from the base case
+	ScenarioDifference struct {
+		TotalIncome float64 `json:"total_income"`
+		TotalTax    float64 `json:"total_tax"`
+		AfterTax    float64 `json:"after_tax"`
+	}
+
+	// ScenarioOutcome is the tax of a profile over all its years. Rank and
+	// Difference are only set on scenarios.
+	ScenarioOutcome struct {
+		Name        string              `json:"name"`
+		Rank        int                 `json:"rank,omitempty"`
+		Years       []YearResult        `json:"years"`
+		TotalIncome float64             `json:"total_income"`
+		TotalTax    float64             `json:"total_tax"`
+		AfterTax    float64             `json:"after_tax"`
+		Difference  *ScenarioDifference `json:"difference,omitempty"`
+	}
+
+	// ScenarioResult lists the scenarios from the best to the worst after-tax
+	// income, with the base case they are compared to
+	ScenarioResult struct {
+		Base      ScenarioOutcome   `json:"base"`
+		Scenarios []ScenarioOutcome `json:"scenarios"`
+	}
+)
+
+// ApplyScenario returns a copy of the base profile with the scenario's
+// modifications applied in order
+func ApplyScenario(base []TaxRequest, scenario Scenario) ([]TaxRequest, error) {
+	if scenario.Name == "" {
+		return nil, errors.New("scenario name is required")
+	}
+
+	profile := make([]TaxRequest, len(base))
+	copy(profile, base)
+
+	for _, m := range scenario.Modifications {
+		field, ok := scenarioFields[m.Field]
+		if !ok {
+			return nil, fmt.Errorf("scenario %q: unknown field %q", scenario.Name, m.Field)
+		}
+
+		matched := false
+		for i := range profile {
+			if m.Year != 0 && profile[i].Year != m.Year {
+				continue
+			}
+			matched = true
+
+			v := field(&profile[i])
+			switch m.Operation {
+			case "", OperationSet:
+				*v = m.Value
+			case OperationAdd:
+				*v += m.Value
+			default:
+				return nil, fmt.Errorf("scenario %q: unknown operation %q", scenario.Name, m.Operation)
+			}
+			if *v < 0 {
+				return nil, fmt.Errorf("scenario %q: %s of %d would be negative", scenario.Name, m.Field, profile[i].Year)
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("scenario %q: year %d is not in the base profile", scenario.Name, m.Year)
+		}
+	}
+
+	return profile, nil
+}
+
+// ValidateScenarios checks that the base profile lists each year once and
+// that every scenario has a unique name and applies to the base profile
+func ValidateScenarios(req ScenarioRequest) error {
+	seenYears := make(map[int]bool)
+	for _, year := range req.Base {
+		if seenYears[year.Year] {
+			return fmt.Errorf("year %d is listed more than once", year.Year)
+		}
+		seenYears[year.Year] = true
+	}
+
+	seenNames := make(map[string]bool)
+	for _, scenario := range req.Scenarios {
+		if seenNames[scenario.Name] {
+			return fmt.Errorf("scenario %q is listed more than once", scenario.Name)
+		}
+		seenNames[scenario.Name] = true
+
+		if _, err := ApplyScenario(req.Base, scenario); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewScenarioOutcome totals the results of a profile. results[i] belongs to
+// profile[i]. Realized gains of a ledger count as income.
+func NewScenarioOutcome(name string, profile []TaxRequest, results []TaxResult) ScenarioOutcome {
+	outcome := ScenarioOutcome{Name: name, Years: make([]YearResult, len(profile))}
+	for i, req := range profile {
+		outcome.Years[i] = YearResult{Year: req.Year, TaxResult: results[i]}
+		outcome.TotalIncome += req.TotalIncome()
+		if results[i].Ledger != nil && results[i].Ledger.NetGain > 0 {
+			outcome.TotalIncome += results[i].Ledger.NetGain
+		}
+		outcome.TotalTax += results[i].TotalTax
+	}
+	outcome.AfterTax = outcome.TotalIncome - outcome.TotalTax
+	return outcome
+}
+
+// RankScenarios compares every scenario with the base case and ranks them by
+// after-tax income, then by tax. Ties keep the order of the request.
+func RankScenarios(base ScenarioOutcome, scenarios []ScenarioOutcome) ScenarioResult {
+	ranked := make([]ScenarioOutcome, len(scenarios))
+	copy(ranked, scenarios)
+
+	for i := range ranked {
+		ranked[i].Difference = &ScenarioDifference{
+			TotalIncome: ranked[i].TotalIncome - base.TotalIncome,
+			TotalTax:    ranked[i].TotalTax - base.TotalTax,
+			AfterTax:    ranked[i].AfterTax - base.AfterTax,
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].AfterTax != ranked[j].AfterTax {
+			return ranked[i].AfterTax > ranked[j].AfterTax
+		}
+		return ranked[i].TotalTax < ranked[j].TotalTax
+	})
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+
+	return ScenarioResult{Base: base, Scenarios: ranked}
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyScenario(t *testing.T) {
+	base := []TaxRequest{
+		{Income: 80000, Year: 2021},
+		{Income: 80000, Year: 2022, Withheld: 1000},
+	}
+
+	tests := []struct {
+		name          string
+		scenario      Scenario
+		expected      []TaxRequest
+		expectedError string
+	}{
+		{
+			name: "Add to one year",
+			scenario: Scenario{Name: "bonus next year", Modifications: []Modification{
+				{Year: 2022, Field: "income", Operation: OperationAdd, Value: 20000},
+			}},
+			expected: []TaxRequest{
+				{Income: 80000, Year: 2021},
+				{Income: 100000, Year: 2022, Withheld: 1000},
+			},
+		},
+		{
+			name: "Set every year",
+			scenario: Scenario{Name: "salary to business income", Modifications: []Modification{
+				{Field: "income", Value: 30000},
+				{Field: "business_income", Operation: OperationAdd, Value: 50000},
+			}},
+			expected: []TaxRequest{
+				{Income: 30000, Year: 2021, BusinessIncome: 50000},
+				{Income: 30000, Year: 2022, Withheld: 1000, BusinessIncome: 50000},
+			},
+		},
+		{
+			name: "Convert salary to dividends",
+			scenario: Scenario{Name: "dividends", Modifications: []Modification{
+				{Year: 2022, Field: "income", Operation: OperationAdd, Value: -40000},
+				{Year: 2022, Field: "dividends", Operation: OperationAdd, Value: 40000},
+			}},
+			expected: []TaxRequest{
+				{Income: 80000, Year: 2021},
+				{Income: 40000, Year: 2022, Withheld: 1000, Dividends: 40000},
+			},
+		},
+		{
+			name:          "Missing name",
+			scenario:      Scenario{Modifications: []Modification{{Field: "income", Value: 1}}},
+			expectedError: "scenario name is required",
+		},
+		{
+			name:          "Unknown field",
+			scenario:      Scenario{Name: "s", Modifications: []Modification{{Field: "salary", Value: 1}}},
+			expectedError: `unknown field "salary"`,
+		},
+		{
+			name:          "Unknown operation",
+			scenario:      Scenario{Name: "s", Modifications: []Modification{{Field: "income", Operation: "multiply", Value: 2}}},
+			expectedError: `unknown operation "multiply"`,
+		},
+		{
+			name:          "Year outside the profile",
+			scenario:      Scenario{Name: "s", Modifications: []Modification{{Year: 2020, Field: "income", Value: 1}}},
+			expectedError: "year 2020 is not in the base profile",
+		},
+		{
+			name:          "Negative amount",
+			scenario:      Scenario{Name: "s", Modifications: []Modification{{Year: 2021, Field: "income", Operation: OperationAdd, Value: -90000}}},
+			expectedError: "income of 2021 would be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := ApplyScenario(base, tt.scenario)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, profile)
+			// The base profile is left untouched
+			assert.Equal(t, 80000.0, base[1].Income)
+		})
+	}
+}
+
+func TestValidateScenarios(t *testing.T) {
+	base := []TaxRequest{{Income: 80000, Year: 2021}}
+	bonus := Scenario{Name: "bonus", Modifications: []Modification{{Field: "income", Operation: OperationAdd, Value: 1000}}}
+
+	tests := []struct {
+		name          string
+		req           ScenarioRequest
+		expectedError string
+	}{
+		{name: "Valid scenarios", req: ScenarioRequest{Base: base, Scenarios: []Scenario{bonus}}},
+		{
+			name:          "Duplicate base year",
+			req:           ScenarioRequest{Base: append(base, base[0]), Scenarios: []Scenario{bonus}},
+			expectedError: "year 2021 is listed more than once",
+		},
+		{
+			name:          "Duplicate scenario name",
+			req:           ScenarioRequest{Base: base, Scenarios: []Scenario{bonus, bonus}},
+			expectedError: `scenario "bonus" is listed more than once`,
+		},
+		{
+			name: "Modification that does not apply",
+			req: ScenarioRequest{Base: base, Scenarios: []Scenario{
+				{Name: "s", Modifications: []Modification{{Field: "salary", Value: 1}}},
+			}},
+			expectedError: `scenario "s": unknown field "salary"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateScenarios(tt.req)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRankScenarios(t *testing.T) {
+	profile := []TaxRequest{{Income: 80000, Year: 2021}, {Income: 80000, Year: 2022}}
+	base := NewScenarioOutcome("base", profile, []TaxResult{{TotalTax: 16000}, {TotalTax: 16000}})
+
+	assert.InDelta(t, 160000, base.TotalIncome, 0.0001)
+	assert.InDelta(t, 32000, base.TotalTax, 0.0001)
+	assert.InDelta(t, 128000, base.AfterTax, 0.0001)
+
+	thisYear := NewScenarioOutcome("bonus this year",
+		[]TaxRequest{{Income: 100000, Year: 2021}, {Income: 80000, Year: 2022}},
+		[]TaxResult{{TotalTax: 23000}, {TotalTax: 16000}})
+	nextYear := NewScenarioOutcome("bonus next year",
+		[]TaxRequest{{Income: 80000, Year: 2021}, {Income: 100000, Year: 2022}},
+		[]TaxResult{{TotalTax: 16000}, {TotalTax: 22000}})
+	noBonus := NewScenarioOutcome("no bonus", profile, []TaxResult{{TotalTax: 16000}, {TotalTax: 16000}})
+
+	result := RankScenarios(base, []ScenarioOutcome{noBonus, thisYear, nextYear})
+
+	assert.Equal(t, base, result.Base)
+	assert.Len(t, result.Scenarios, 3)
+
+	names := make([]string, len(result.Scenarios))
+	for i, s := range result.Scenarios {
+		names[i] = s.Name
+		assert.Equal(t, i+1, s.Rank)
+	}
+	assert.Equal(t, []string{"bonus next year", "bonus this year", "no bonus"}, names)
+
+	assert.Equal(t, &ScenarioDifference{TotalIncome: 20000, TotalTax: 6000, AfterTax: 14000}, result.Scenarios[0].Difference)
+	assert.Equal(t, &ScenarioDifference{}, result.Scenarios[2].Difference)
+}
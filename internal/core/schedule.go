@@ -0,0 +1,226 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var ErrNoSchedule = errors.New("no tax schedule in effect")
+
+type (
+	// TaxSchedule is a set of brackets in effect between two dates, both
+	// inclusive and formatted with DateFormat. An empty EffectiveTo means the
+	// schedule has no end date.
+	// Source and Version identify where the schedule was loaded from.
+	TaxSchedule struct {
+		EffectiveFrom string       `json:"effective_from"`
+		EffectiveTo   string       `json:"effective_to,omitempty"`
+		Brackets      []TaxBracket `json:"brackets"`
+		Source        string       `json:"source,omitempty"`
+		Version       string       `json:"version,omitempty"`
+	}
+
+	// PeriodResult is the share of the annual tax attributed to one schedule
+	// when a year is split between several of them.
+	PeriodResult struct {
+		EffectiveFrom string             `json:"effective_from"`
+		EffectiveTo   string             `json:"effective_to"`
+		Days          int                `json:"days"`
+		Weight        float64            `json:"weight"`
+		TotalTax      float64            `json:"total_tax"`
+		PerBracket    map[string]float64 `json:"per_bracket"`
+	}
+)
+
+// WholeYearSchedule wraps brackets in a schedule covering the whole year
+func WholeYearSchedule(year int, brackets []TaxBracket) TaxSchedule {
+	return TaxSchedule{
+		EffectiveFrom: fmt.Sprintf("%d-01-01", year),
+		EffectiveTo:   fmt.Sprintf("%d-12-31", year),
+		Brackets:      brackets,
+	}
+}
+
+// Dates parses the effective dates of the schedule. An open end date is
+// returned as the zero time.
+func (s TaxSchedule) Dates() (from, to time.Time, err error) {
+	if from, err = time.Parse(DateFormat, s.EffectiveFrom); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid effective from date %q: %w", s.EffectiveFrom, err)
+	}
+	if s.EffectiveTo == "" {
+		return from, time.Time{}, nil
+	}
+	if to, err = time.Parse(DateFormat, s.EffectiveTo); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid effective to date %q: %w", s.EffectiveTo, err)
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("schedule ends on %s before it starts on %s", s.EffectiveTo, s.EffectiveFrom)
+	}
+	return from, to, nil
+}
+
+// ScheduleAsOf returns the schedule in effect on the given date
+func ScheduleAsOf(schedules []TaxSchedule, date time.Time) (TaxSchedule, error) {
+	for _, s := range schedules {
+		from, to, err := s.Dates()
+		if err != nil {
+			return TaxSchedule{}, err
+		}
+		if !date.Before(from) && (to.IsZero() || !date.After(to)) {
+			return s, nil
+		}
+	}
+	return TaxSchedule{}, fmt.Errorf("%w on %s", ErrNoSchedule, date.Format(DateFormat))
+}
+
+// ComputeBlended calculates the tax for a year covered by one or more
+// schedules. The pipeline runs against each schedule with the full income
+// and the results are weighted by the share of the year each schedule is in
+// effect; the periods are listed in the result when there is more than one.
+// residency is the factor passed on to Pipeline.Compute.
+func ComputeBlended(req TaxRequest, schedules []TaxSchedule, pipeline Pipeline, residency float64, trace *Trace) (TaxResult, error) {
+	year := req.Year
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	yearDays := daysBetween(yearStart, yearEnd)
+
+	type period struct {
+		from, to time.Time
+		schedule TaxSchedule
+	}
+
+	var periods []period
+	for _, s := range schedules {
+		from, to, err := s.Dates()
+		if err != nil {
+			return TaxResult{}, err
+		}
+		if to.IsZero() || to.After(yearEnd) {
+			to = yearEnd
+		}
+		if from.Before(yearStart) {
+			from = yearStart
+		}
+		if from.After(to) {
+			continue
+		}
+		periods = append(periods, period{from: from, to: to, schedule: s})
+	}
+
+	sort.Slice(periods, func(i, j int) bool { return periods[i].from.Before(periods[j].from) })
+
+	// The periods must cover every day of the year exactly once
+	next := yearStart
+	for _, p := range periods {
+		if !p.from.Equal(next) {
+			return TaxResult{}, fmt.Errorf("%w on %s", ErrNoSchedule, next.Format(DateFormat))
+		}
+		next = p.to.AddDate(0, 0, 1)
+	}
+	if !next.After(yearEnd) {
+		return TaxResult{}, fmt.Errorf("%w on %s", ErrNoSchedule, next.Format(DateFormat))
+	}
+
+	if len(periods) == 1 {
+		traceSchedule(trace, periods[0].schedule)
+		return pipeline.Compute(req, periods[0].schedule.Brackets, residency, trace), nil
+	}
+
+	result := TaxResult{PerBracket: make(map[string]float64)}
+	for i, p := range periods {
+		days := daysBetween(p.from, p.to)
+		weight := float64(days) / float64(yearDays)
+
+		traceSchedule(trace, p.schedule)
+		full := pipeline.Compute(req, p.schedule.Brackets, residency, trace)
+		trace.Addf(StageSchedule, fmt.Sprintf("%.2f × %d / %d = %.2f", full.TotalTax, days, yearDays, full.TotalTax*weight), full.TotalTax*weight,
+			"Tax prorated to %s - %s", p.from.Format(DateFormat), p.to.Format(DateFormat))
+
+		perBand := make(map[string]float64, len(full.PerBracket))
+		for k, v := range full.PerBracket {
+			perBand[k] = v * weight
+			result.PerBracket[k] += v * weight
+		}
+
+		// Every period runs the same pipeline, so lines line up by position
+		for j, line := range full.Lines {
+			if i == 0 {
+				result.Lines = append(result.Lines, TaxLine{Name: line.Name, Type: line.Type, Prorated: line.Prorated})
+			}
+			result.Lines[j].Amount += line.Amount * weight
+			result.Lines[j].FullYearAmount += line.FullYearAmount * weight
+		}
+
+		result.TotalTax += full.TotalTax * weight
+		result.BasicTax += full.BasicTax * weight
+		result.MarginalRate += full.MarginalRate * weight
+		if full.AMT != nil {
+			if result.AMT == nil {
+				result.AMT = &AMTResult{}
+			}
+			result.AMT.addWeighted(*full.AMT, weight)
+		}
+		// The contribution does not depend on the brackets, so every period
+		// reports the same one
+		result.SelfEmployment = full.SelfEmployment
+		result.Periods = append(result.Periods, PeriodResult{
+			EffectiveFrom: p.from.Format(DateFormat),
+			EffectiveTo:   p.to.Format(DateFormat),
+			Days:          days,
+			Weight:        weight,
+			TotalTax:      full.TotalTax * weight,
+			PerBracket:    perBand,
+		})
+	}
+
+	// Each period rounded its brackets, but prorating leaves fractions, so
+	// the blended bands are rounded again and the bracket tax follows them
+	if policy := pipeline.Rounding(); policy.Step == RoundBracket {
+		var adjustment float64
+		for k, v := range result.PerBracket {
+			rounded := policy.Round(v)
+			adjustment += rounded - v
+			result.PerBracket[k] = rounded
+		}
+		trace.Add(StageRounding, "Prorated bands rounded", fmt.Sprintf("%s, %+.4f", policy.describe(), adjustment), adjustment)
+		result.TotalTax += adjustment
+		result.BasicTax += adjustment
+		for j := range result.Lines {
+			if result.Lines[j].Type == RuleBrackets {
+				result.Lines[j].Amount += adjustment
+				break
+			}
+		}
+	}
+
+	if req.TotalIncome() > 0 {
+		result.EffectiveRate = result.TotalTax / req.TotalIncome()
+	}
+
+	return result, nil
+}
+
+// traceSchedule records which schedule is about to be applied
+func traceSchedule(trace *Trace, s TaxSchedule) {
+	source := s.Source
+	if source == "" {
+		source = "unknown source"
+	}
+	version := s.Version
+	if version == "" {
+		version = "unversioned"
+	}
+	to := s.EffectiveTo
+	if to == "" {
+		to = "open"
+	}
+	trace.Addf(StageSchedule, "", float64(len(s.Brackets)), "Schedule %s - %s from %s (%s) with %d brackets",
+		s.EffectiveFrom, to, source, version, len(s.Brackets))
+}
+
+// daysBetween counts the days from start to end, both inclusive
+func daysBetween(start, end time.Time) int {
+	return int(end.Sub(start).Hours()/24) + 1
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleAsOf(t *testing.T) {
+	schedules := []TaxSchedule{
+		{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-06-30", Brackets: []TaxBracket{{Min: 0, Rate: 0.1}}},
+		{EffectiveFrom: "2022-07-01", Brackets: []TaxBracket{{Min: 0, Rate: 0.2}}},
+	}
+
+	tests := []struct {
+		name          string
+		date          string
+		expectedRate  float64
+		expectedError error
+	}{
+		{name: "First day", date: "2022-01-01", expectedRate: 0.1},
+		{name: "Last day of first schedule", date: "2022-06-30", expectedRate: 0.1},
+		{name: "Open-ended schedule", date: "2023-03-01", expectedRate: 0.2},
+		{name: "Before any schedule", date: "2021-12-31", expectedError: ErrNoSchedule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, err := time.Parse(DateFormat, tt.date)
+			assert.NoError(t, err)
+
+			schedule, err := ScheduleAsOf(schedules, date)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRate, schedule.Brackets[0].Rate)
+		})
+	}
+}
+
+func TestTaxSchedule_Dates(t *testing.T) {
+	tests := []struct {
+		name        string
+		schedule    TaxSchedule
+		expectError bool
+	}{
+		{name: "Valid", schedule: TaxSchedule{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-12-31"}},
+		{name: "Open-ended", schedule: TaxSchedule{EffectiveFrom: "2022-01-01"}},
+		{name: "Bad format", schedule: TaxSchedule{EffectiveFrom: "01/01/2022"}, expectError: true},
+		{name: "Ends before start", schedule: TaxSchedule{EffectiveFrom: "2022-06-01", EffectiveTo: "2022-01-01"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.schedule.Dates()
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestComputeBlended(t *testing.T) {
+	low := []TaxBracket{{Min: 0, Max: 0, Rate: 0.1}}
+	high := []TaxBracket{{Min: 0, Max: 0, Rate: 0.2}}
+
+	tests := []struct {
+		name            string
+		year            int
+		schedules       []TaxSchedule
+		expectedTotal   float64
+		expectedPeriods []int
+		expectedError   error
+	}{
+		{
+			name:          "Single schedule matches plain calculation",
+			year:          2022,
+			schedules:     []TaxSchedule{WholeYearSchedule(2022, low)},
+			expectedTotal: 10000,
+		},
+		{
+			name: "Mid-year rate change",
+			year: 2022,
+			schedules: []TaxSchedule{
+				{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-07-01", Brackets: low},
+				{EffectiveFrom: "2022-07-02", Brackets: high},
+			},
+			expectedTotal:   10000*182/365.0 + 20000*183/365.0,
+			expectedPeriods: []int{182, 183},
+		},
+		{
+			name: "Schedules spanning year ends are clipped",
+			year: 2020,
+			schedules: []TaxSchedule{
+				{EffectiveFrom: "2019-01-01", EffectiveTo: "2020-03-31", Brackets: low},
+				{EffectiveFrom: "2020-04-01", EffectiveTo: "2021-12-31", Brackets: high},
+			},
+			expectedTotal:   10000*91/366.0 + 20000*275/366.0,
+			expectedPeriods: []int{91, 275},
+		},
+		{
+			name: "Gap between schedules",
+			year: 2022,
+			schedules: []TaxSchedule{
+				{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-06-30", Brackets: low},
+				{EffectiveFrom: "2022-08-01", Brackets: high},
+			},
+			expectedError: ErrNoSchedule,
+		},
+		{
+			name: "Year not fully covered",
+			year: 2022,
+			schedules: []TaxSchedule{
+				{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-06-30", Brackets: low},
+			},
+			expectedError: ErrNoSchedule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeBlended(TaxRequest{Income: 100000, Year: tt.year}, tt.schedules, DefaultPipeline(), 1, nil)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+			assert.InDelta(t, tt.expectedTotal/100000, result.EffectiveRate, 0.0001)
+			assert.Len(t, result.Periods, len(tt.expectedPeriods))
+			for i, days := range tt.expectedPeriods {
+				assert.Equal(t, days, result.Periods[i].Days)
+			}
+		})
+	}
+}
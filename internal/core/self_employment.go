@@ -0,0 +1,104 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// RuleSelfEmployment is the self-employment contribution rule type
+const RuleSelfEmployment = "self_employment"
+
+// SelfEmploymentResult reports the payroll contributions a self-employed
+// taxpayer pays as both employee and employer, and the part of them deducted
+// from income. The employer's share is the deductible one.
+type SelfEmploymentResult struct {
+	BusinessIncome       float64 `json:"business_income"`
+	ContributoryEarnings float64 `json:"contributory_earnings"`
+	Rate                 float64 `json:"rate"`
+	Contribution         float64 `json:"contribution"`
+	EmployeeShare        float64 `json:"employee_share"`
+	EmployerShare        float64 `json:"employer_share"`
+	Deduction            float64 `json:"deduction"`
+}
+
+func init() {
+	RegisterRuleType(RuleSelfEmployment, func(spec RuleSpec) (TaxRule, error) {
+		if err := validateRate(spec.Rate); err != nil {
+			return nil, err
+		}
+		if spec.Threshold < 0 || spec.Amount < 0 {
+			return nil, errors.New("exemption and maximum earnings must be non-negative")
+		}
+		inclusion := spec.Inclusion
+		if inclusion == 0 {
+			inclusion = 1
+		}
+		if inclusion < 0 || inclusion > 1 {
+			return nil, fmt.Errorf("inclusion %v must be between 0 and 1", spec.Inclusion)
+		}
+		deductible := spec.Deductible
+		if deductible == 0 {
+			deductible = 0.5
+		}
+		if deductible < 0 || deductible > 1 {
+			return nil, fmt.Errorf("deductible share %v must be between 0 and 1", spec.Deductible)
+		}
+		return selfEmploymentRule{
+			name:        spec.Name,
+			rate:        spec.Rate,
+			exemption:   spec.Threshold,
+			maxEarnings: spec.Amount,
+			inclusion:   inclusion,
+			deductible:  deductible,
+			prorate:     spec.Prorate,
+		}, nil
+	})
+}
+
+// selfEmploymentRule charges the combined employee and employer contribution
+// rate on net business income, scaled by the inclusion rate and capped at
+// the maximum contributory earnings, above the basic exemption. The
+// deductible share of the contribution, half by default, is deducted from
+// income before the brackets apply. The contribution is added to the total
+// after every rule has run, so credits and minimum taxes leave it alone.
+type selfEmploymentRule struct {
+	name        string
+	rate        float64
+	exemption   float64
+	maxEarnings float64
+	inclusion   float64
+	deductible  float64
+	prorate     bool
+}
+
+func (r selfEmploymentRule) Name() string { return r.name }
+func (r selfEmploymentRule) Phase() Phase { return PhaseIncome }
+
+func (r selfEmploymentRule) Apply(c *Computation) {
+	business := math.Max(0, c.Request.BusinessIncome)
+	earnings := business * r.inclusion
+	if r.maxEarnings > 0 {
+		earnings = math.Min(earnings, c.Prorated(r.maxEarnings, r.prorate))
+	}
+	exemption := c.Prorated(r.exemption, r.prorate)
+	contribution := math.Max(0, earnings-exemption) * r.rate
+	deduction := contribution * r.deductible
+
+	c.Trace.Addf(StageContribution, fmt.Sprintf("max(0, %.2f - %.2f) × %.4f = %.2f", earnings, exemption, r.rate, contribution), contribution,
+		"Self-employment contribution %s", r.name)
+	c.Trace.Addf(StageDeduction, fmt.Sprintf("%.2f × %.4f = %.2f", contribution, r.deductible, deduction), -deduction,
+		"Deduction for the self-employment contribution %s", r.name)
+
+	c.Income -= deduction
+	c.AddContribution(r, RuleSelfEmployment, contribution)
+	c.SelfEmployment = &SelfEmploymentResult{
+		BusinessIncome:       business,
+		ContributoryEarnings: earnings,
+		Rate:                 r.rate,
+		Contribution:         contribution,
+		EmployeeShare:        contribution - deduction,
+		EmployerShare:        deduction,
+		Deduction:            deduction,
+	}
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfEmploymentRule(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+
+	tests := []struct {
+		name          string
+		spec          RuleSpec
+		req           TaxRequest
+		expectedTotal float64
+		expected      *SelfEmploymentResult
+	}{
+		{
+			name:          "Contribution above the exemption, half deducted",
+			spec:          RuleSpec{Type: RuleSelfEmployment, Rate: 0.1, Threshold: 2000},
+			req:           TaxRequest{BusinessIncome: 42000},
+			expectedTotal: 4000 + 6000,
+			expected: &SelfEmploymentResult{
+				BusinessIncome: 42000, ContributoryEarnings: 42000, Rate: 0.1,
+				Contribution: 4000, EmployeeShare: 2000, EmployerShare: 2000, Deduction: 2000,
+			},
+		},
+		{
+			name:          "Earnings capped and scaled by the inclusion rate",
+			spec:          RuleSpec{Type: RuleSelfEmployment, Rate: 0.1, Amount: 30000, Inclusion: 0.5, Deductible: 1},
+			req:           TaxRequest{Income: 10000, BusinessIncome: 80000},
+			expectedTotal: 3000 + (90000-3000-10000)*0.2,
+			expected: &SelfEmploymentResult{
+				BusinessIncome: 80000, ContributoryEarnings: 30000, Rate: 0.1,
+				Contribution: 3000, EmployeeShare: 0, EmployerShare: 3000, Deduction: 3000,
+			},
+		},
+		{
+			name:          "No business income",
+			spec:          RuleSpec{Type: RuleSelfEmployment, Rate: 0.1, Threshold: 2000},
+			req:           TaxRequest{Income: 20000},
+			expectedTotal: 2000,
+			expected:      &SelfEmploymentResult{Rate: 0.1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline([]RuleSpec{{Type: RuleBrackets}, tt.spec})
+			assert.NoError(t, err)
+
+			result := pipeline.Compute(tt.req, brackets, 1, nil)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+			assert.Equal(t, tt.expected, result.SelfEmployment)
+		})
+	}
+}
+
+func TestSelfEmploymentRule_Validation(t *testing.T) {
+	_, err := NewRule(RuleSpec{Type: RuleSelfEmployment, Rate: 0.1, Deductible: 2})
+	assert.ErrorContains(t, err, "deductible share")
+
+	_, err = NewRule(RuleSpec{Type: RuleSelfEmployment, Rate: 0.1, Threshold: -1})
+	assert.ErrorContains(t, err, "must be non-negative")
+}
+
+func TestSelfEmploymentRule_NotTax(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	contribution := RuleSpec{Type: RuleSelfEmployment, Rate: 0.1}
+	// Business income of 30000 contributes 3000, half deducted, and leaves
+	// 3700 of tax on 28500 of taxable income
+	req := TaxRequest{BusinessIncome: 30000}
+
+	tests := []struct {
+		name          string
+		rule          RuleSpec
+		expectedTotal float64
+	}{
+		{
+			name:          "Credit is capped at the tax",
+			rule:          RuleSpec{Type: RuleCredit, Rate: 1, Amount: 5000},
+			expectedTotal: 3000,
+		},
+		{
+			name:          "Minimum tax is compared with the tax alone",
+			rule:          RuleSpec{Type: RuleMinimumTax, Rate: 0.2},
+			expectedTotal: 28500*0.2 + 3000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline([]RuleSpec{{Type: RuleBrackets}, contribution, tt.rule})
+			assert.NoError(t, err)
+
+			result := pipeline.Compute(req, brackets, 1, nil)
+
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+			last := result.Lines[len(result.Lines)-1]
+			assert.Equal(t, RuleSelfEmployment, last.Type)
+			assert.InDelta(t, 3000, last.Amount, 0.0001)
+		})
+	}
+}
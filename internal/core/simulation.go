@@ -0,0 +1,249 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Income distributions
+const (
+	DistributionNormal    = "normal"
+	DistributionLognormal = "lognormal"
+	DistributionEmpirical = "empirical"
+)
+
+// DefaultTrials is the number of draws of a simulation that does not set it,
+// MaxTrials the most a single simulation may run and MaxPipelineTrials the
+// most it may run on a year taxed through its pipeline, where each draw
+// evaluates every rule
+const (
+	DefaultTrials     = 10000
+	MaxTrials         = 1000000
+	MaxPipelineTrials = 100000
+)
+
+// cancelCheckInterval is how many draws run between checks of the context
+const cancelCheckInterval = 1000
+
+// DefaultPercentiles are reported when a simulation does not list its own
+var DefaultPercentiles = []float64{5, 25, 50, 75, 95}
+
+var ErrTooManyTrials = errors.New("simulation has too many trials")
+
+type (
+	// Distribution describes uncertain income. Normal and lognormal
+	// distributions are given by the mean and standard deviation of the
+	// income itself; empirical ones draw from Samples with replacement.
+	// Negative draws are taxed as no income.
+	Distribution struct {
+		Type    string    `json:"type"`
+		Mean    float64   `json:"mean,omitempty"`
+		StdDev  float64   `json:"std_dev,omitempty"`
+		Samples []float64 `json:"samples,omitempty"`
+	}
+
+	// SimulationRequest runs Trials draws of the distribution through the
+	// tax of Year under the rules of Jurisdiction. The same seed always
+	// gives the same result.
+	SimulationRequest struct {
+		Year         int          `json:"year"`
+		Jurisdiction string       `json:"jurisdiction,omitempty"`
+		Distribution Distribution `json:"distribution"`
+		Trials       int          `json:"trials,omitempty"`
+		Seed         int64        `json:"seed"`
+		Percentiles  []float64    `json:"percentiles,omitempty"`
+	}
+
+	// SimulationPercentile is the income and the tax at a percentile of the
+	// draws
+	SimulationPercentile struct {
+		Percentile float64 `json:"percentile"`
+		Income     float64 `json:"income"`
+		Tax        float64 `json:"tax"`
+	}
+
+	// BracketProbability is the share of draws reaching into a bracket
+	BracketProbability struct {
+		Min         float64 `json:"min"`
+		Max         float64 `json:"max,omitempty"`
+		Rate        float64 `json:"rate"`
+		Probability float64 `json:"probability"`
+	}
+
+	SimulationResult struct {
+		Year           int                    `json:"year"`
+		Trials         int                    `json:"trials"`
+		Seed           int64                  `json:"seed"`
+		ExpectedIncome float64                `json:"expected_income"`
+		ExpectedTax    float64                `json:"expected_tax"`
+		StdDevTax      float64                `json:"std_dev_tax"`
+		Percentiles    []SimulationPercentile `json:"percentiles"`
+		Brackets       []BracketProbability   `json:"brackets"`
+	}
+)
+
+// Validate checks the distribution parameters
+func (d Distribution) Validate() error {
+	switch d.Type {
+	case DistributionNormal:
+		if d.StdDev < 0 {
+			return errors.New("standard deviation must be non-negative")
+		}
+	case DistributionLognormal:
+		if d.Mean <= 0 || d.StdDev < 0 {
+			return errors.New("lognormal mean must be positive and standard deviation non-negative")
+		}
+	case DistributionEmpirical:
+		if len(d.Samples) == 0 {
+			return errors.New("empirical distribution needs at least one sample")
+		}
+	default:
+		return fmt.Errorf("unknown distribution %q", d.Type)
+	}
+	return nil
+}
+
+// sampler returns a function drawing incomes from the distribution
+func (d Distribution) sampler(rng *rand.Rand) func() float64 {
+	switch d.Type {
+	case DistributionLognormal:
+		// Parameters of the underlying normal giving the requested mean and
+		// standard deviation of income
+		sigma := math.Sqrt(math.Log(1 + d.StdDev*d.StdDev/(d.Mean*d.Mean)))
+		mu := math.Log(d.Mean) - sigma*sigma/2
+		return func() float64 { return math.Exp(mu + sigma*rng.NormFloat64()) }
+	case DistributionEmpirical:
+		return func() float64 { return d.Samples[rng.Intn(len(d.Samples))] }
+	default:
+		return func() float64 { return d.Mean + d.StdDev*rng.NormFloat64() }
+	}
+}
+
+// ComputeSimulation draws incomes from the distribution and taxes each one
+// in the year, reporting the expected tax, percentiles and how likely each
+// bracket of the schedule in effect at the end of the year is reached.
+// Years whose tax is their brackets alone are evaluated on the compiled
+// brackets, every other year through its pipeline, which allows fewer
+// trials. The simulation stops early when ctx is done.
+func ComputeSimulation(ctx context.Context, req SimulationRequest, year TaxYear) (SimulationResult, error) {
+	if err := req.Distribution.Validate(); err != nil {
+		return SimulationResult{}, err
+	}
+
+	trials := req.Trials
+	if trials == 0 {
+		trials = DefaultTrials
+	}
+	compiled, ok := year.compiled()
+	limit := MaxTrials
+	if !ok {
+		limit = MaxPipelineTrials
+	}
+	if trials < 0 || trials > limit {
+		return SimulationResult{}, fmt.Errorf("%w: %d, at most %d", ErrTooManyTrials, trials, limit)
+	}
+
+	percentiles := req.Percentiles
+	if len(percentiles) == 0 {
+		percentiles = DefaultPercentiles
+	}
+	for _, p := range percentiles {
+		if p < 0 || p > 100 {
+			return SimulationResult{}, fmt.Errorf("percentile %g must be between 0 and 100", p)
+		}
+	}
+
+	closing, err := year.ClosingSchedule()
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	brackets := closing.Brackets
+	schedule, err := CompileBrackets(brackets)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+
+	taxAt := func(income float64) (float64, error) {
+		return year.Tax(TaxRequest{Income: income, Jurisdiction: req.Jurisdiction})
+	}
+	if ok {
+		taxAt = func(income float64) (float64, error) {
+			return compiled.Tax(income), nil
+		}
+	}
+
+	draw := req.Distribution.sampler(rand.New(rand.NewSource(req.Seed)))
+	incomes := make([]float64, trials)
+	taxes := make([]float64, trials)
+	// top[j] counts the draws whose highest bracket is j
+	top := make([]int, len(brackets))
+
+	var sumIncome, sumTax, sumTaxSq float64
+	for i := range incomes {
+		if i%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return SimulationResult{}, err
+			}
+		}
+
+		income := math.Max(0, draw())
+		tax, err := taxAt(income)
+		if err != nil {
+			return SimulationResult{}, err
+		}
+
+		incomes[i], taxes[i] = income, tax
+		sumIncome += income
+		sumTax += tax
+		sumTaxSq += tax * tax
+
+		if k := schedule.bracket(income); k >= 0 {
+			top[k]++
+		}
+	}
+
+	n := float64(trials)
+	result := SimulationResult{
+		Year:           year.Year,
+		Trials:         trials,
+		Seed:           req.Seed,
+		ExpectedIncome: sumIncome / n,
+		ExpectedTax:    sumTax / n,
+		StdDevTax:      math.Sqrt(math.Max(0, sumTaxSq/n-(sumTax/n)*(sumTax/n))),
+		Percentiles:    make([]SimulationPercentile, len(percentiles)),
+		Brackets:       make([]BracketProbability, len(brackets)),
+	}
+
+	sort.Float64s(incomes)
+	sort.Float64s(taxes)
+	for i, p := range percentiles {
+		k := nearestRank(p, trials)
+		result.Percentiles[i] = SimulationPercentile{Percentile: p, Income: incomes[k], Tax: taxes[k]}
+	}
+
+	// A draw reaching into a bracket crosses every bracket below it
+	reached := 0
+	for j := len(brackets) - 1; j >= 0; j-- {
+		reached += top[j]
+		b := brackets[j]
+		result.Brackets[j] = BracketProbability{Min: b.Min, Max: b.Max, Rate: b.Rate, Probability: float64(reached) / n}
+	}
+
+	return result, nil
+}
+
+// nearestRank returns the index of the percentile in n sorted values
+func nearestRank(p float64, n int) int {
+	k := int(math.Ceil(p/100*float64(n))) - 1
+	if k < 0 {
+		return 0
+	}
+	if k >= n {
+		return n - 1
+	}
+	return k
+}
@@ -0,0 +1,188 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeSimulation(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 20000, Rate: 0.1},
+		{Min: 20000, Max: 50000, Rate: 0.2},
+		{Min: 50000, Max: 0, Rate: 0.3},
+	}
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, brackets)}, Pipeline: DefaultPipeline()}
+
+	tests := []struct {
+		name                  string
+		req                   SimulationRequest
+		expectedIncome        float64
+		expectedTax           float64
+		tolerance             float64
+		expectedProbabilities []float64
+		probabilityTolerance  float64
+		expectedError         string
+	}{
+		{
+			name:                  "Certain income",
+			req:                   SimulationRequest{Distribution: Distribution{Type: DistributionNormal, Mean: 60000}, Trials: 100},
+			expectedIncome:        60000,
+			expectedTax:           2000 + 6000 + 3000,
+			expectedProbabilities: []float64{1, 1, 1},
+		},
+		{
+			name: "Empirical samples",
+			req: SimulationRequest{
+				Distribution: Distribution{Type: DistributionEmpirical, Samples: []float64{10000, 30000, 70000}},
+				Trials:       300000,
+				Seed:         7,
+			},
+			expectedIncome:        110000 / 3.0,
+			expectedTax:           (1000 + 4000 + 14000) / 3.0,
+			tolerance:             200,
+			expectedProbabilities: []float64{1, 2 / 3.0, 1 / 3.0},
+			probabilityTolerance:  0.01,
+		},
+		{
+			name:                  "Normal income",
+			req:                   SimulationRequest{Distribution: Distribution{Type: DistributionNormal, Mean: 50000, StdDev: 10000}, Trials: 200000, Seed: 1},
+			expectedIncome:        50000,
+			tolerance:             200,
+			expectedProbabilities: []float64{1, 0.9987, 0.5},
+			probabilityTolerance:  0.01,
+		},
+		{
+			name:                  "Lognormal income with the given mean",
+			req:                   SimulationRequest{Distribution: Distribution{Type: DistributionLognormal, Mean: 50000, StdDev: 20000}, Trials: 200000, Seed: 3},
+			expectedIncome:        50000,
+			tolerance:             300,
+			expectedProbabilities: []float64{1},
+			probabilityTolerance:  0.0001,
+		},
+		{
+			name:          "Unknown distribution",
+			req:           SimulationRequest{Distribution: Distribution{Type: "uniform"}},
+			expectedError: `unknown distribution "uniform"`,
+		},
+		{
+			name:          "Empirical without samples",
+			req:           SimulationRequest{Distribution: Distribution{Type: DistributionEmpirical}},
+			expectedError: "at least one sample",
+		},
+		{
+			name:          "Lognormal with a non-positive mean",
+			req:           SimulationRequest{Distribution: Distribution{Type: DistributionLognormal, StdDev: 1}},
+			expectedError: "lognormal mean must be positive",
+		},
+		{
+			name:          "Too many trials",
+			req:           SimulationRequest{Distribution: Distribution{Type: DistributionNormal, Mean: 1}, Trials: MaxTrials + 1},
+			expectedError: "simulation has too many trials",
+		},
+		{
+			name:          "Percentile out of range",
+			req:           SimulationRequest{Distribution: Distribution{Type: DistributionNormal, Mean: 1}, Percentiles: []float64{101}},
+			expectedError: "percentile 101 must be between 0 and 100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeSimulation(context.Background(), tt.req, year)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedIncome, result.ExpectedIncome, tt.tolerance+0.0001)
+			if tt.expectedTax > 0 {
+				assert.InDelta(t, tt.expectedTax, result.ExpectedTax, tt.tolerance+0.0001)
+			}
+			assert.Len(t, result.Brackets, len(brackets))
+			for i, p := range tt.expectedProbabilities {
+				assert.InDelta(t, p, result.Brackets[i].Probability, tt.probabilityTolerance+0.0001)
+			}
+		})
+	}
+}
+
+func TestComputeSimulation_Percentiles(t *testing.T) {
+	brackets := []TaxBracket{{Min: 0, Max: 0, Rate: 0.1}}
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, brackets)}, Pipeline: DefaultPipeline()}
+	req := SimulationRequest{
+		Distribution: Distribution{Type: DistributionEmpirical, Samples: []float64{10000, 20000, 30000}},
+		Trials:       10000,
+		Seed:         42,
+		Percentiles:  []float64{0, 50, 100},
+	}
+
+	result, err := ComputeSimulation(context.Background(), req, year)
+	assert.NoError(t, err)
+	assert.Equal(t, []SimulationPercentile{
+		{Percentile: 0, Income: 10000, Tax: 1000},
+		{Percentile: 50, Income: 20000, Tax: 2000},
+		{Percentile: 100, Income: 30000, Tax: 3000},
+	}, result.Percentiles)
+
+	// The same seed gives the same draws
+	again, err := ComputeSimulation(context.Background(), req, year)
+	assert.NoError(t, err)
+	assert.Equal(t, result, again)
+
+	req.Seed = 43
+	other, err := ComputeSimulation(context.Background(), req, year)
+	assert.NoError(t, err)
+	assert.NotEqual(t, result.ExpectedTax, other.ExpectedTax)
+}
+
+func TestComputeSimulation_DefaultTrials(t *testing.T) {
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, []TaxBracket{{Min: 0, Max: 0, Rate: 0.1}})}, Pipeline: DefaultPipeline()}
+	result, err := ComputeSimulation(context.Background(), SimulationRequest{Distribution: Distribution{Type: DistributionNormal, Mean: -5000, StdDev: 1000}}, year)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTrials, result.Trials)
+	assert.Len(t, result.Percentiles, len(DefaultPercentiles))
+	// Negative draws are taxed as no income
+	assert.Equal(t, 0.0, result.ExpectedTax)
+	assert.Equal(t, 0.0, result.Brackets[0].Probability)
+}
+
+func TestComputeSimulation_Pipeline(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 20000, Rate: 0.1},
+		{Min: 20000, Max: 0, Rate: 0.2},
+	}
+	pipeline, err := NewPipeline([]RuleSpec{{Type: RuleBrackets}, {Type: RuleFlat, Rate: 0.05, Threshold: 50000}})
+	assert.NoError(t, err)
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, brackets)}, Pipeline: pipeline}
+
+	result, err := ComputeSimulation(context.Background(), SimulationRequest{Distribution: Distribution{Type: DistributionNormal, Mean: 60000}, Trials: 100}, year)
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 2000+8000+500, result.ExpectedTax, 0.0001)
+	assert.Equal(t, []float64{1, 1}, []float64{result.Brackets[0].Probability, result.Brackets[1].Probability})
+}
+
+func TestComputeSimulation_PipelineTrials(t *testing.T) {
+	pipeline, err := NewPipeline([]RuleSpec{{Type: RuleBrackets}, {Type: RuleFlat, Rate: 0.05, Threshold: 50000}})
+	assert.NoError(t, err)
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, []TaxBracket{{Min: 0, Max: 0, Rate: 0.1}})}, Pipeline: pipeline}
+
+	_, err = ComputeSimulation(context.Background(), SimulationRequest{Distribution: Distribution{Type: DistributionNormal, Mean: 1}, Trials: MaxPipelineTrials + 1}, year)
+
+	assert.ErrorIs(t, err, ErrTooManyTrials)
+	assert.Contains(t, err.Error(), "at most 100000")
+}
+
+func TestComputeSimulation_Cancelled(t *testing.T) {
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, []TaxBracket{{Min: 0, Max: 0, Rate: 0.1}})}, Pipeline: DefaultPipeline()}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ComputeSimulation(ctx, SimulationRequest{Distribution: Distribution{Type: DistributionNormal, Mean: 1}}, year)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
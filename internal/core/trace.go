@@ -0,0 +1,72 @@
+package core
+
+import "fmt"
+
+// Trace stages, in the order they normally appear
+const (
+	StageInput        = "input"
+	StageSchedule     = "schedule"
+	StageBracket      = "bracket"
+	StageContribution = "contribution"
+	StageDeduction    = "deduction"
+	StageSurtax       = "surtax"
+	StageCredit       = "credit"
+	StageClawback     = "clawback"
+	StageMinimum      = "minimum"
+	StageRounding     = "rounding"
+	StageTotal        = "total"
+	StageBalance      = "balance"
+)
+
+// TraceStep is one entry of the audit trail of a calculation
+type TraceStep struct {
+	Step        int     `json:"step"`
+	Stage       string  `json:"stage"`
+	Description string  `json:"description"`
+	Formula     string  `json:"formula,omitempty"`
+	Value       float64 `json:"value"`
+}
+
+// Trace collects the steps of a calculation. A nil *Trace is valid and
+// records nothing, so calculations can trace unconditionally.
+type Trace struct {
+	steps []TraceStep
+}
+
+// NewTrace returns an empty trace when enabled, nil otherwise
+func NewTrace(enabled bool) *Trace {
+	if !enabled {
+		return nil
+	}
+	return &Trace{}
+}
+
+// Add appends a step to the trace
+func (t *Trace) Add(stage, description, formula string, value float64) {
+	if t == nil {
+		return
+	}
+	t.steps = append(t.steps, TraceStep{
+		Step:        len(t.steps) + 1,
+		Stage:       stage,
+		Description: description,
+		Formula:     formula,
+		Value:       value,
+	})
+}
+
+// Addf appends a step with a formatted description
+func (t *Trace) Addf(stage, formula string, value float64, format string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	t.Add(stage, fmt.Sprintf(format, args...), formula, value)
+}
+
+// Steps returns the recorded steps
+func (t *Trace) Steps() []TraceStep {
+	if t == nil {
+		return nil
+	}
+	return t.steps
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		expected []TraceStep
+	}{
+		{
+			name:     "Disabled trace records nothing",
+			enabled:  false,
+			expected: nil,
+		},
+		{
+			name:    "Enabled trace numbers steps in order",
+			enabled: true,
+			expected: []TraceStep{
+				{Step: 1, Stage: StageInput, Description: "Income", Formula: "income = 100.00", Value: 100},
+				{Step: 2, Stage: StageTotal, Description: "Total tax for 2022", Formula: "10.00", Value: 10},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace := NewTrace(tt.enabled)
+			trace.Add(StageInput, "Income", "income = 100.00", 100)
+			trace.Addf(StageTotal, "10.00", 10, "Total tax for %d", 2022)
+
+			assert.Equal(t, tt.expected, trace.Steps())
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package core
+
+import "fmt"
+
+// PayFrequency is how often an employee is paid during the year
+type PayFrequency string
+
+const (
+	Weekly      PayFrequency = "weekly"
+	Biweekly    PayFrequency = "biweekly"
+	SemiMonthly PayFrequency = "semi-monthly"
+	Monthly     PayFrequency = "monthly"
+)
+
+// PeriodsPerYear returns the number of pay periods in a year, or 0 if the
+// frequency is not recognised.
+func (f PayFrequency) PeriodsPerYear() int {
+	switch f {
+	case Weekly:
+		return 52
+	case Biweekly:
+		return 26
+	case SemiMonthly:
+		return 24
+	case Monthly:
+		return 12
+	default:
+		return 0
+	}
+}
+
+type (
+	// WithholdingRequest describes a single paycheque. PayPeriod is the
+	// 1-based number of the current period, at most the periods in the year;
+	// it is required when year-to-date amounts are supplied. The rules of Jurisdiction apply to the annual
+	// tax.
+	WithholdingRequest struct {
+		Gross        float64      `json:"gross"`
+		Frequency    PayFrequency `json:"frequency"`
+		Year         int          `json:"year"`
+		Jurisdiction string       `json:"jurisdiction,omitempty"`
+		PayPeriod    int          `json:"pay_period,omitempty"`
+		YTDGross     float64      `json:"ytd_gross,omitempty"`
+		YTDWithheld  float64      `json:"ytd_withheld,omitempty"`
+	}
+
+	WithholdingResult struct {
+		Withholding      float64   `json:"withholding"`
+		ProjectedIncome  float64   `json:"projected_income"`
+		PeriodsPerYear   int       `json:"periods_per_year"`
+		PeriodsRemaining int       `json:"periods_remaining"`
+		Annual           TaxResult `json:"annual"`
+	}
+)
+
+// ComputeWithholding annualizes the paycheque, calculates the tax of the
+// year on it and spreads the tax still owing over the remaining periods.
+// The income for the year is projected as the year-to-date gross plus the
+// current gross for every remaining period, so on the last paycheque the
+// projection equals actual income and the withholding settles the year.
+func ComputeWithholding(req WithholdingRequest, year TaxYear) (WithholdingResult, error) {
+	periods := req.Frequency.PeriodsPerYear()
+	if periods == 0 {
+		return WithholdingResult{}, fmt.Errorf("invalid pay frequency %q", req.Frequency)
+	}
+	if req.PayPeriod < 0 || req.PayPeriod > periods {
+		return WithholdingResult{}, fmt.Errorf("pay period %d is outside 1 to %d", req.PayPeriod, periods)
+	}
+
+	period := req.PayPeriod
+	if period == 0 {
+		period = 1
+	}
+	remaining := periods - period + 1
+
+	projected := req.YTDGross + req.Gross*float64(remaining)
+	annual, err := year.Calculate(TaxRequest{Income: projected, Jurisdiction: req.Jurisdiction})
+	if err != nil {
+		return WithholdingResult{}, err
+	}
+
+	withholding := (annual.TotalTax - req.YTDWithheld) / float64(remaining)
+	if withholding < 0 {
+		withholding = 0
+	}
+
+	return WithholdingResult{
+		Withholding:      withholding,
+		ProjectedIncome:  projected,
+		PeriodsPerYear:   periods,
+		PeriodsRemaining: remaining,
+		Annual:           annual,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayFrequency_PeriodsPerYear(t *testing.T) {
+	tests := []struct {
+		frequency PayFrequency
+		expected  int
+	}{
+		{Weekly, 52},
+		{Biweekly, 26},
+		{SemiMonthly, 24},
+		{Monthly, 12},
+		{PayFrequency("daily"), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.frequency), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.frequency.PeriodsPerYear())
+		})
+	}
+}
+
+func TestComputeWithholding(t *testing.T) {
+	brackets := []TaxBracket{
+		{Min: 0, Max: 12000, Rate: 0},
+		{Min: 12000, Max: 0, Rate: 0.2},
+	}
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, brackets)}, Pipeline: DefaultPipeline()}
+
+	tests := []struct {
+		name                string
+		req                 WithholdingRequest
+		expectedWithholding float64
+		expectedProjected   float64
+		expectedRemaining   int
+	}{
+		{
+			name:                "Monthly without year-to-date",
+			req:                 WithholdingRequest{Gross: 5000, Frequency: Monthly, Year: 2022},
+			expectedWithholding: 800, // (60000-12000)*0.2/12
+			expectedProjected:   60000,
+			expectedRemaining:   12,
+		},
+		{
+			name:                "Biweekly without year-to-date",
+			req:                 WithholdingRequest{Gross: 2600, Frequency: Biweekly, Year: 2022},
+			expectedWithholding: 427.69, // (67600-12000)*0.2/26
+			expectedProjected:   67600,
+			expectedRemaining:   26,
+		},
+		{
+			name: "Last paycheque settles the year after a raise",
+			req: WithholdingRequest{
+				Gross:       10000,
+				Frequency:   Monthly,
+				Year:        2022,
+				PayPeriod:   12,
+				YTDGross:    55000,
+				YTDWithheld: 8000,
+			},
+			expectedWithholding: 2600, // (65000-12000)*0.2 - 8000
+			expectedProjected:   65000,
+			expectedRemaining:   1,
+		},
+		{
+			name: "Over-withheld year-to-date does not go negative",
+			req: WithholdingRequest{
+				Gross:       1000,
+				Frequency:   Monthly,
+				Year:        2022,
+				PayPeriod:   12,
+				YTDGross:    11000,
+				YTDWithheld: 500,
+			},
+			expectedWithholding: 0,
+			expectedProjected:   12000,
+			expectedRemaining:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ComputeWithholding(tt.req, year)
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedWithholding, result.Withholding, 0.01)
+			assert.InDelta(t, tt.expectedProjected, result.ProjectedIncome, 0.0001)
+			assert.Equal(t, tt.expectedRemaining, result.PeriodsRemaining)
+			assert.Equal(t, tt.req.Frequency.PeriodsPerYear(), result.PeriodsPerYear)
+		})
+	}
+}
+
+func TestComputeWithholding_InvalidPeriod(t *testing.T) {
+	year := TaxYear{Year: 2022, Schedules: []TaxSchedule{WholeYearSchedule(2022, []TaxBracket{{Min: 0, Max: 0, Rate: 0.2}})}, Pipeline: DefaultPipeline()}
+
+	tests := []struct {
+		name          string
+		req           WithholdingRequest
+		expectedError string
+	}{
+		{
+			name:          "Unknown frequency",
+			req:           WithholdingRequest{Gross: 1000, Frequency: PayFrequency("daily"), Year: 2022},
+			expectedError: `invalid pay frequency "daily"`,
+		},
+		{
+			name:          "Period after the last of the year",
+			req:           WithholdingRequest{Gross: 1000, Frequency: Weekly, Year: 2022, PayPeriod: 53},
+			expectedError: "pay period 53 is outside 1 to 52",
+		},
+		{
+			name:          "Negative period",
+			req:           WithholdingRequest{Gross: 1000, Frequency: Monthly, Year: 2022, PayPeriod: -3},
+			expectedError: "pay period -3 is outside 1 to 12",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ComputeWithholding(tt.req, year)
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type AveragingHandler struct {
+	tc TaxCalculator
+}
+
+func (h *AveragingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tax/averaging" {
+		logger.Log.Warn().Msgf("Invalid URL path: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var request core.AveragingRequest
+
+		// Decode JSON body
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			logger.Log.Error().Err(err).Msg("Invalid JSON body")
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		// Check for missing required fields
+		if len(request.Years) == 0 {
+			logger.Log.Warn().Msg("Missing required fields in request")
+			http.Error(w, "Missing required fields", http.StatusBadRequest)
+			return
+		}
+
+		if len(request.Years) < 2 {
+			logger.Log.Warn().Msgf("Not enough years to average: %d", len(request.Years))
+			http.Error(w, "At least two years are required", http.StatusBadRequest)
+			return
+		}
+
+		for _, year := range request.Years {
+			if year.Income < 0 {
+				logger.Log.Warn().Msgf("Invalid income value: %f for year %d, income must be non-negative", year.Income, year.Year)
+				http.Error(w, "Income must be non-negative", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Call the service
+		result, err := h.tc.CalculateAveraging(r.Context(), request)
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Error averaging income")
+			http.Error(w, "Error averaging income: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to encode response")
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		logger.Log.Warn().Msgf("Method %s not allowed for %s", r.Method, r.URL.Path)
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAveragingHandler(t *testing.T) {
+	years := []map[string]interface{}{
+		{"year": 2021, "income": 10000.0},
+		{"year": 2022, "income": 90000.0},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.AveragingRequest) (core.AveragingResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.AveragingResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"years": years},
+			mockFunc: func(ctx context.Context, req core.AveragingRequest) (core.AveragingResult, error) {
+				assert.Equal(t, []core.AveragingYear{{Year: 2021, Income: 10000}, {Year: 2022, Income: 90000}}, req.Years)
+				return core.AveragingResult{AverageIncome: 50000, StandardTax: 16000, AveragedTax: 12000, Saving: 4000, Beneficial: true}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.AveragingResult{AverageIncome: 50000, StandardTax: 16000, AveragedTax: 12000, Saving: 4000, Beneficial: true},
+		},
+		{
+			name:         "Missing years",
+			method:       "POST",
+			body:         map[string]interface{}{"jurisdiction": "ON"},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:         "Single year",
+			method:       "POST",
+			body:         map[string]interface{}{"years": years[:1]},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "At least two years are required",
+		},
+		{
+			name:         "Negative income",
+			method:       "POST",
+			body:         map[string]interface{}{"years": []map[string]interface{}{{"year": 2021, "income": -1.0}, {"year": 2022, "income": 1.0}}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Income must be non-negative",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"years": years},
+			mockFunc: func(ctx context.Context, req core.AveragingRequest) (core.AveragingResult, error) {
+				return core.AveragingResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error averaging income",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateAveragingFunc: tt.mockFunc}
+			handler := &AveragingHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/averaging", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.AveragingResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON, result)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.CompareRequest) (core.CompareResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.CompareResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"income": 50000.0, "years": []int{2021, 2022}},
+			mockFunc: func(ctx context.Context, req core.CompareRequest) (core.CompareResult, error) {
+				assert.Equal(t, []int{2021, 2022}, req.Years)
+				return core.CompareResult{
+					Income: 50000,
+					Results: []core.YearResult{
+						{Year: 2021, TaxResult: core.TaxResult{TotalTax: 9000}},
+						{Year: 2022, TaxResult: core.TaxResult{TotalTax: 9500}},
+					},
+					Deltas: []core.YearDelta{{FromYear: 2021, ToYear: 2022, TotalTax: 500}},
+				}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.CompareResult{
+				Income: 50000,
+				Results: []core.YearResult{
+					{Year: 2021, TaxResult: core.TaxResult{TotalTax: 9000}},
+					{Year: 2022, TaxResult: core.TaxResult{TotalTax: 9500}},
+				},
+				Deltas: []core.YearDelta{{FromYear: 2021, ToYear: 2022, TotalTax: 500}},
+			},
+		},
+		{
+			name:         "Missing years",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 50000.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:         "Single year",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 50000.0, "years": []int{2021}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "At least two years are required",
+		},
+		{
+			name:         "Negative income",
+			method:       "POST",
+			body:         map[string]interface{}{"income": -1.0, "years": []int{2021, 2022}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Income must be non-negative",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"income": 50000.0, "years": []int{2021, 2022}},
+			mockFunc: func(ctx context.Context, req core.CompareRequest) (core.CompareResult, error) {
+				return core.CompareResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error comparing tax years",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CompareYearsFunc: tt.mockFunc}
+			handler := &CompareHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/compare", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.CompareResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON, result)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
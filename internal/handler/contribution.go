@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type ContributionHandler struct {
+	tc TaxCalculator
+}
+
+func (h *ContributionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tax/contribution" {
+		logger.Log.Warn().Msgf("Invalid URL path: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var request core.ContributionRequest
+
+		// Decode JSON body
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			logger.Log.Error().Err(err).Msg("Invalid JSON body")
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		// Check for missing required fields
+		if request.Year == 0 {
+			logger.Log.Warn().Msg("Missing required fields in request")
+			http.Error(w, "Missing required fields", http.StatusBadRequest)
+			return
+		}
+
+		if request.Income < 0 || request.Room < 0 {
+			logger.Log.Warn().Msgf("Invalid income %f or room %f, amounts must be non-negative", request.Income, request.Room)
+			http.Error(w, "Income and room must be non-negative", http.StatusBadRequest)
+			return
+		}
+
+		// Call the service
+		result, err := h.tc.CalculateContribution(r.Context(), request)
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Error optimizing contribution")
+			http.Error(w, "Error optimizing contribution: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to encode response")
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		logger.Log.Warn().Msgf("Method %s not allowed for %s", r.Method, r.URL.Path)
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContributionHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.ContributionRequest) (core.ContributionResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.ContributionResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"income": 60000.0, "year": 2022, "room": 20000.0},
+			mockFunc: func(ctx context.Context, req core.ContributionRequest) (core.ContributionResult, error) {
+				assert.Equal(t, core.ContributionRequest{Income: 60000, Year: 2022, Room: 20000}, req)
+				return core.ContributionResult{
+					Income: 60000,
+					Room:   20000,
+					Bands:  []core.ContributionBand{{From: 0, To: 20000, Rate: 0.3, Saving: 6000}},
+				}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.ContributionResult{
+				Income: 60000,
+				Room:   20000,
+				Bands:  []core.ContributionBand{{From: 0, To: 20000, Rate: 0.3, Saving: 6000}},
+			},
+		},
+		{
+			name:         "Missing year",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 60000.0, "room": 20000.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:         "Negative room",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 60000.0, "year": 2022, "room": -1.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Income and room must be non-negative",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"income": 60000.0, "year": 2022, "room": 20000.0},
+			mockFunc: func(ctx context.Context, req core.ContributionRequest) (core.ContributionResult, error) {
+				return core.ContributionResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error optimizing contribution",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateContributionFunc: tt.mockFunc}
+			handler := &ContributionHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/contribution", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.ContributionResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON, result)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type CorporateHandler struct {
+	tc TaxCalculator
+}
+
+func (h *CorporateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tax/corporate" {
+		logger.Log.Warn().Msgf("Invalid URL path: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var request core.CorporateRequest
+
+		// Decode JSON body
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			logger.Log.Error().Err(err).Msg("Invalid JSON body")
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		// Check for missing required fields
+		if request.Year == 0 {
+			logger.Log.Warn().Msg("Missing required fields in request")
+			http.Error(w, "Missing required fields", http.StatusBadRequest)
+			return
+		}
+
+		if request.ActiveIncome < 0 || request.PassiveIncome < 0 {
+			logger.Log.Warn().Msgf("Invalid active income %f or passive income %f, amounts must be non-negative", request.ActiveIncome, request.PassiveIncome)
+			http.Error(w, "Active and passive income must be non-negative", http.StatusBadRequest)
+			return
+		}
+
+		// Call the service
+		result, err := h.tc.CalculateCorporateTax(r.Context(), request)
+		if errors.Is(err, core.ErrNoCorporateParameters) {
+			logger.Log.Warn().Err(err).Msg("No corporate parameters for the year")
+			http.Error(w, "No corporate parameters: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Error calculating corporate tax")
+			http.Error(w, "Error calculating corporate tax: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to encode response")
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		logger.Log.Warn().Msgf("Method %s not allowed for %s", r.Method, r.URL.Path)
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
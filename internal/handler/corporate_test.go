@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorporateHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.CorporateRequest) (core.CorporateResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.CorporateResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"year": 2022, "active_income": 600000.0, "passive_income": 90000.0},
+			mockFunc: func(ctx context.Context, req core.CorporateRequest) (core.CorporateResult, error) {
+				assert.Equal(t, core.CorporateRequest{Year: 2022, ActiveIncome: 600000, PassiveIncome: 90000}, req)
+				return core.CorporateResult{
+					BusinessLimit: 300000,
+					Grind:         200000,
+					TaxResult:     core.TaxResult{TotalTax: 72000, MarginalRate: 0.15},
+				}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.CorporateResult{
+				BusinessLimit: 300000,
+				Grind:         200000,
+				TaxResult:     core.TaxResult{TotalTax: 72000, MarginalRate: 0.15},
+			},
+		},
+		{
+			name:         "Missing year",
+			method:       "POST",
+			body:         map[string]interface{}{"active_income": 600000.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:         "Negative passive income",
+			method:       "POST",
+			body:         map[string]interface{}{"year": 2022, "active_income": 600000.0, "passive_income": -1.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Active and passive income must be non-negative",
+		},
+		{
+			name:   "No parameters for the year",
+			method: "POST",
+			body:   map[string]interface{}{"year": 2022, "active_income": 600000.0},
+			mockFunc: func(ctx context.Context, req core.CorporateRequest) (core.CorporateResult, error) {
+				return core.CorporateResult{}, fmt.Errorf("failed to fetch corporate parameters: %w for 2022", core.ErrNoCorporateParameters)
+			},
+			expectedCode: http.StatusNotFound,
+			expectedBody: "No corporate parameters",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"year": 2022, "active_income": 600000.0},
+			mockFunc: func(ctx context.Context, req core.CorporateRequest) (core.CorporateResult, error) {
+				return core.CorporateResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error calculating corporate tax",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateCorporateTaxFunc: tt.mockFunc}
+			handler := &CorporateHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/corporate", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.CorporateResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON, result)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
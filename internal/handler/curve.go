@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type CurveHandler struct {
+	tc TaxCalculator
+}
+
+func (h *CurveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tax/curve" {
+		logger.Log.Warn().Msgf("Invalid URL path: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+
+		// Check for missing required parameters
+		if query.Get("year") == "" || query.Get("to") == "" {
+			logger.Log.Warn().Msg("Missing required query parameters")
+			http.Error(w, "Missing required query parameters", http.StatusBadRequest)
+			return
+		}
+
+		request, err := parseCurveQuery(query.Get)
+		if err != nil {
+			logger.Log.Warn().Err(err).Msg("Invalid query parameters")
+			http.Error(w, "Invalid query parameters: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if request.From < 0 || request.To < request.From {
+			logger.Log.Warn().Msgf("Invalid income range: %.2f-%.2f", request.From, request.To)
+			http.Error(w, "Invalid income range", http.StatusBadRequest)
+			return
+		}
+
+		if !request.Breakpoints && request.Step <= 0 {
+			logger.Log.Warn().Msgf("Invalid step: %.2f", request.Step)
+			http.Error(w, "Step must be positive", http.StatusBadRequest)
+			return
+		}
+
+		// Call the service
+		result, err := h.tc.CalculateCurve(r.Context(), request)
+		if errors.Is(err, core.ErrTooManyPoints) {
+			logger.Log.Warn().Err(err).Msg("Curve has too many points")
+			http.Error(w, "Curve has too many points, increase step", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Error calculating tax curve")
+			http.Error(w, "Error calculating tax curve: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to encode response")
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", "GET, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		logger.Log.Warn().Msgf("Method %s not allowed for %s", r.Method, r.URL.Path)
+		w.Header().Set("Allow", "GET, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// parseCurveQuery converts the query parameters of /tax/curve into a request
+func parseCurveQuery(get func(string) string) (core.CurveRequest, error) {
+	var (
+		request core.CurveRequest
+		err     error
+	)
+
+	if request.Year, err = strconv.Atoi(get("year")); err != nil {
+		return core.CurveRequest{}, errors.New("year must be an integer")
+	}
+	request.Jurisdiction = get("jurisdiction")
+	if request.To, err = parseFinite(get("to")); err != nil {
+		return core.CurveRequest{}, errors.New("to must be a number")
+	}
+	if v := get("from"); v != "" {
+		if request.From, err = parseFinite(v); err != nil {
+			return core.CurveRequest{}, errors.New("from must be a number")
+		}
+	}
+	if v := get("step"); v != "" {
+		if request.Step, err = parseFinite(v); err != nil {
+			return core.CurveRequest{}, errors.New("step must be a number")
+		}
+	}
+	if v := get("breakpoints"); v != "" {
+		if request.Breakpoints, err = strconv.ParseBool(v); err != nil {
+			return core.CurveRequest{}, errors.New("breakpoints must be a boolean")
+		}
+	}
+
+	return request, nil
+}
+
+// parseFinite parses a number, rejecting NaN and infinities which
+// strconv.ParseFloat accepts but no income can be
+func parseFinite(v string) (float64, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, errors.New("number must be finite")
+	}
+	return f, nil
+}
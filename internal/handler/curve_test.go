@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurveHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		query          string
+		mockFunc       func(ctx context.Context, req core.CurveRequest) (core.CurveResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.CurveResult
+	}{
+		{
+			name:   "Success case",
+			method: "GET",
+			query:  "?year=2022&from=0&to=20000&step=10000",
+			mockFunc: func(ctx context.Context, req core.CurveRequest) (core.CurveResult, error) {
+				assert.Equal(t, core.CurveRequest{Year: 2022, From: 0, To: 20000, Step: 10000}, req)
+				return core.CurveResult{
+					Year: 2022,
+					Points: []core.CurvePoint{
+						{Income: 0, TotalTax: 0, EffectiveRate: 0, MarginalRate: 0.1},
+						{Income: 10000, TotalTax: 1000, EffectiveRate: 0.1, MarginalRate: 0.2},
+					},
+				}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.CurveResult{
+				Year: 2022,
+				Points: []core.CurvePoint{
+					{Income: 0, TotalTax: 0, EffectiveRate: 0, MarginalRate: 0.1},
+					{Income: 10000, TotalTax: 1000, EffectiveRate: 0.1, MarginalRate: 0.2},
+				},
+			},
+		},
+		{
+			name:   "Breakpoints mode without step",
+			method: "GET",
+			query:  "?year=2022&to=20000&breakpoints=true",
+			mockFunc: func(ctx context.Context, req core.CurveRequest) (core.CurveResult, error) {
+				assert.True(t, req.Breakpoints)
+				return core.CurveResult{Year: 2022}, nil
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "Jurisdiction",
+			method: "GET",
+			query:  "?year=2022&to=20000&step=10000&jurisdiction=ON",
+			mockFunc: func(ctx context.Context, req core.CurveRequest) (core.CurveResult, error) {
+				assert.Equal(t, "ON", req.Jurisdiction)
+				return core.CurveResult{Year: 2022}, nil
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Missing parameters",
+			method:       "GET",
+			query:        "?year=2022",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required query parameters",
+		},
+		{
+			name:         "Non-numeric parameter",
+			method:       "GET",
+			query:        "?year=2022&to=abc&step=1",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "to must be a number",
+		},
+		{
+			name:         "Non-finite parameter",
+			method:       "GET",
+			query:        "?year=2022&from=NaN&to=10&step=1",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "from must be a number",
+		},
+		{
+			name:         "Infinite parameter",
+			method:       "GET",
+			query:        "?year=2022&to=Inf&step=1",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "to must be a number",
+		},
+		{
+			name:         "Inverted range",
+			method:       "GET",
+			query:        "?year=2022&from=100&to=10&step=1",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid income range",
+		},
+		{
+			name:         "Missing step",
+			method:       "GET",
+			query:        "?year=2022&to=10",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Step must be positive",
+		},
+		{
+			name:   "Too many points",
+			method: "GET",
+			query:  "?year=2022&to=1000000000&step=1",
+			mockFunc: func(ctx context.Context, req core.CurveRequest) (core.CurveResult, error) {
+				return core.CurveResult{}, fmt.Errorf("failed to compute tax curve: %w", core.ErrTooManyPoints)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Curve has too many points",
+		},
+		{
+			name:   "Internal error from service",
+			method: "GET",
+			query:  "?year=2022&to=20000&step=1000",
+			mockFunc: func(ctx context.Context, req core.CurveRequest) (core.CurveResult, error) {
+				return core.CurveResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error calculating tax curve",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "GET, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "POST",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "GET, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateCurveFunc: tt.mockFunc}
+			handler := &CurveHandler{tc: mock}
+
+			req := httptest.NewRequest(tt.method, "/tax/curve"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.CurveResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON, result)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
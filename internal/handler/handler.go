@@ -3,8 +3,10 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/haninamaryia/tax-calculator/internal/core"
@@ -12,7 +14,20 @@ import (
 )
 
 type TaxCalculator interface {
-	CalculateTax(ctx context.Context, incomeStr string, yearStr string) (core.TaxResult, error)
+	Calculate(ctx context.Context, req core.TaxRequest) (core.TaxResult, error)
+	CalculateWithholding(ctx context.Context, req core.WithholdingRequest) (core.WithholdingResult, error)
+	CalculateGrossIncome(ctx context.Context, req core.ReverseRequest) (core.ReverseResult, error)
+	CalculateCurve(ctx context.Context, req core.CurveRequest) (core.CurveResult, error)
+	CompareYears(ctx context.Context, req core.CompareRequest) (core.CompareResult, error)
+	CalculateLosses(ctx context.Context, req core.LossRequest) (core.LossResult, error)
+	CalculateAveraging(ctx context.Context, req core.AveragingRequest) (core.AveragingResult, error)
+	CalculateContribution(ctx context.Context, req core.ContributionRequest) (core.ContributionResult, error)
+	CalculateHousehold(ctx context.Context, req core.HouseholdRequest) (core.HouseholdResult, error)
+	CalculateCorporateTax(ctx context.Context, req core.CorporateRequest) (core.CorporateResult, error)
+	CalculateSalesTax(ctx context.Context, req core.SalesTaxRequest) (core.SalesTaxResult, error)
+	CalculateLedger(ctx context.Context, req core.LedgerRequest) (core.LedgerResult, error)
+	CalculateScenarios(ctx context.Context, req core.ScenarioRequest) (core.ScenarioResult, error)
+	CalculateSimulation(ctx context.Context, req core.SimulationRequest) (core.SimulationResult, error)
 }
 
 type TaxCalculatorHandler struct {
@@ -31,6 +46,19 @@ func NewServer(port int, tc TaxCalculator) *http.Server {
 
 	mux.HandleFunc("/healthz", HealthCheckHandler)
 	mux.Handle("/tax", &TaxCalculatorHandler{tc})
+	mux.Handle("/tax/withholding", &WithholdingHandler{tc})
+	mux.Handle("/tax/reverse", &ReverseHandler{tc})
+	mux.Handle("/tax/curve", &CurveHandler{tc})
+	mux.Handle("/tax/compare", &CompareHandler{tc})
+	mux.Handle("/tax/losses", &LossHandler{tc})
+	mux.Handle("/tax/averaging", &AveragingHandler{tc})
+	mux.Handle("/tax/contribution", &ContributionHandler{tc})
+	mux.Handle("/tax/household", &HouseholdHandler{tc})
+	mux.Handle("/tax/corporate", &CorporateHandler{tc})
+	mux.Handle("/tax/sales", &SalesTaxHandler{tc})
+	mux.Handle("/tax/ledger", &LedgerHandler{tc})
+	mux.Handle("/tax/scenarios", &ScenarioHandler{tc})
+	mux.Handle("/tax/simulation", &SimulationHandler{tc})
 
 	return &http.Server{
 		Addr:           fmt.Sprintf(":%d", port),
@@ -51,8 +79,23 @@ func (t *TaxCalculatorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	switch r.Method {
 	case http.MethodPost:
 		var request struct {
-			Income interface{} `json:"income"`
-			Year   int         `json:"year"`
+			Income            interface{}        `json:"income"`
+			Year              int                `json:"year"`
+			Jurisdiction      string             `json:"jurisdiction"`
+			AsOf              string             `json:"as_of"`
+			Explain           bool               `json:"explain"`
+			CapitalGains      float64            `json:"capital_gains"`
+			AMTCarryForward   float64            `json:"amt_carryforward"`
+			ResidencyStart    string             `json:"residency_start"`
+			ResidencyEnd      string             `json:"residency_end"`
+			Withheld          float64            `json:"withheld"`
+			Instalments       float64            `json:"instalments"`
+			RefundableCredits float64            `json:"refundable_credits"`
+			PaymentDate       string             `json:"payment_date"`
+			InflationRate     *float64           `json:"inflation_rate"`
+			BusinessIncome    float64            `json:"business_income"`
+			Transactions      []core.Transaction `json:"transactions"`
+			Dividends         float64            `json:"dividends"`
 		}
 
 		// Decode JSON body
@@ -70,6 +113,7 @@ func (t *TaxCalculatorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		}
 
 		// Check for invalid income type
+		var income float64
 		switch v := request.Income.(type) {
 		case string:
 			logger.Log.Warn().Msg("Invalid income type: expected number but received string") // Log invalid income type
@@ -82,18 +126,126 @@ func (t *TaxCalculatorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 				http.Error(w, "Income must be non-negative", http.StatusBadRequest)
 				return
 			}
+			income = v
 		default:
 			logger.Log.Warn().Msg("Invalid income type") // Log invalid income type
 			http.Error(w, "Invalid income", http.StatusBadRequest)
 			return
 		}
 
-		// Convert income and year to strings as before
-		incomeStr := fmt.Sprintf("%.2f", request.Income)
-		yearStr := fmt.Sprintf("%d", request.Year)
+		// Check the optional amounts
+		if request.CapitalGains < 0 || request.AMTCarryForward < 0 {
+			logger.Log.Warn().Msg("Invalid capital gains or AMT carry-forward, amounts must be non-negative") // Log invalid amounts
+			http.Error(w, "Capital gains and AMT carry-forward must be non-negative", http.StatusBadRequest)
+			return
+		}
+		if request.Dividends < 0 {
+			logger.Log.Warn().Msgf("Invalid dividends: %f, amount must be non-negative", request.Dividends) // Log invalid amount
+			http.Error(w, "Dividends must be non-negative", http.StatusBadRequest)
+			return
+		}
+
+		// Check the optional as-of date
+		if request.AsOf != "" {
+			if _, err := time.Parse(core.DateFormat, request.AsOf); err != nil {
+				logger.Log.Warn().Msgf("Invalid as-of date: %s", request.AsOf) // Log invalid date
+				http.Error(w, "Invalid as_of date, expected format "+core.DateFormat, http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Check the optional business income
+		if request.BusinessIncome < 0 {
+			logger.Log.Warn().Msgf("Invalid business income: %f, amount must be non-negative", request.BusinessIncome) // Log invalid amount
+			http.Error(w, "Business income must be non-negative", http.StatusBadRequest)
+			return
+		}
+
+		// Check the optional transactions, whose gains are added to capital gains
+		if err := core.ValidateTransactions(request.Transactions); err != nil {
+			logger.Log.Warn().Err(err).Msg("Invalid transactions") // Log invalid transactions
+			http.Error(w, "Invalid transactions: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Check the optional payments
+		if request.Withheld < 0 || request.Instalments < 0 || request.RefundableCredits < 0 {
+			logger.Log.Warn().Msg("Invalid payments, amounts must be non-negative") // Log invalid amounts
+			http.Error(w, "Withheld, instalments and refundable credits must be non-negative", http.StatusBadRequest)
+			return
+		}
+
+		// Check the optional payment date
+		if request.PaymentDate != "" {
+			if _, err := time.Parse(core.DateFormat, request.PaymentDate); err != nil {
+				logger.Log.Warn().Msgf("Invalid payment date: %s", request.PaymentDate) // Log invalid date
+				http.Error(w, "Invalid payment_date, expected format "+core.DateFormat, http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Check the optional inflation rate used for projected years
+		if request.InflationRate != nil && (*request.InflationRate <= -1 || *request.InflationRate > 1) {
+			logger.Log.Warn().Msgf("Invalid inflation rate: %f", *request.InflationRate) // Log invalid rate
+			http.Error(w, "Invalid inflation_rate, must be above -1 and at most 1", http.StatusBadRequest)
+			return
+		}
+
+		// Check the optional residency dates
+		for _, date := range []string{request.ResidencyStart, request.ResidencyEnd} {
+			if date == "" {
+				continue
+			}
+			if _, err := time.Parse(core.DateFormat, date); err != nil {
+				logger.Log.Warn().Msgf("Invalid residency date: %s", date) // Log invalid date
+				http.Error(w, "Invalid residency date, expected format "+core.DateFormat, http.StatusBadRequest)
+				return
+			}
+		}
+		residency := core.TaxRequest{Year: request.Year, ResidencyStart: request.ResidencyStart, ResidencyEnd: request.ResidencyEnd}
+		if _, err := core.ComputeResidency(residency); err != nil {
+			logger.Log.Warn().Err(err).Msg("Invalid residency period") // Log invalid residency period
+			http.Error(w, "Invalid residency: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Explain mode can be requested in the body or with ?explain=true
+		explain := request.Explain
+		if v := r.URL.Query().Get("explain"); v != "" {
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				logger.Log.Warn().Msgf("Invalid explain parameter: %s", v) // Log invalid explain flag
+				http.Error(w, "Invalid explain parameter", http.StatusBadRequest)
+				return
+			}
+			explain = explain || parsed
+		}
 
 		// Call the service
-		result, err := t.tc.CalculateTax(r.Context(), incomeStr, yearStr)
+		result, err := t.tc.Calculate(r.Context(), core.TaxRequest{
+			Income:            income,
+			Year:              request.Year,
+			Jurisdiction:      request.Jurisdiction,
+			AsOf:              request.AsOf,
+			Explain:           explain,
+			CapitalGains:      request.CapitalGains,
+			AMTCarryForward:   request.AMTCarryForward,
+			ResidencyStart:    request.ResidencyStart,
+			ResidencyEnd:      request.ResidencyEnd,
+			Withheld:          request.Withheld,
+			Instalments:       request.Instalments,
+			RefundableCredits: request.RefundableCredits,
+			PaymentDate:       request.PaymentDate,
+			InflationRate:     request.InflationRate,
+			BusinessIncome:    request.BusinessIncome,
+			Transactions:      request.Transactions,
+			Dividends:         request.Dividends,
+		})
+		if errors.Is(err, core.ErrInsufficientUnits) {
+			logger.Log.Warn().Err(err).Msg("Invalid transactions") // Log invalid transactions
+			http.Error(w, "Invalid transactions: "+err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err != nil {
 			logger.Log.Error().Err(err).Msg("Error calculating tax") // Log error calculating tax
 			http.Error(w, "Error calculating tax: "+err.Error(), http.StatusInternalServerError)
@@ -16,19 +16,85 @@ import (
 
 // mockTaxCalculator is a test double
 type mockTaxCalculator struct {
-	CalculateTaxFunc func(ctx context.Context, incomeStr string, yearStr string) (core.TaxResult, error)
+	CalculateFunc             func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error)
+	CalculateWithholdingFunc  func(ctx context.Context, req core.WithholdingRequest) (core.WithholdingResult, error)
+	CalculateGrossIncomeFunc  func(ctx context.Context, req core.ReverseRequest) (core.ReverseResult, error)
+	CalculateCurveFunc        func(ctx context.Context, req core.CurveRequest) (core.CurveResult, error)
+	CompareYearsFunc          func(ctx context.Context, req core.CompareRequest) (core.CompareResult, error)
+	CalculateLossesFunc       func(ctx context.Context, req core.LossRequest) (core.LossResult, error)
+	CalculateAveragingFunc    func(ctx context.Context, req core.AveragingRequest) (core.AveragingResult, error)
+	CalculateContributionFunc func(ctx context.Context, req core.ContributionRequest) (core.ContributionResult, error)
+	CalculateHouseholdFunc    func(ctx context.Context, req core.HouseholdRequest) (core.HouseholdResult, error)
+	CalculateCorporateTaxFunc func(ctx context.Context, req core.CorporateRequest) (core.CorporateResult, error)
+	CalculateSalesTaxFunc     func(ctx context.Context, req core.SalesTaxRequest) (core.SalesTaxResult, error)
+	CalculateLedgerFunc       func(ctx context.Context, req core.LedgerRequest) (core.LedgerResult, error)
+	CalculateScenariosFunc    func(ctx context.Context, req core.ScenarioRequest) (core.ScenarioResult, error)
+	CalculateSimulationFunc   func(ctx context.Context, req core.SimulationRequest) (core.SimulationResult, error)
 }
 
-func (m *mockTaxCalculator) CalculateTax(ctx context.Context, incomeStr string, yearStr string) (core.TaxResult, error) {
-	return m.CalculateTaxFunc(ctx, incomeStr, yearStr)
+func (m *mockTaxCalculator) Calculate(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+	return m.CalculateFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateWithholding(ctx context.Context, req core.WithholdingRequest) (core.WithholdingResult, error) {
+	return m.CalculateWithholdingFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateGrossIncome(ctx context.Context, req core.ReverseRequest) (core.ReverseResult, error) {
+	return m.CalculateGrossIncomeFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateCurve(ctx context.Context, req core.CurveRequest) (core.CurveResult, error) {
+	return m.CalculateCurveFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CompareYears(ctx context.Context, req core.CompareRequest) (core.CompareResult, error) {
+	return m.CompareYearsFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateLosses(ctx context.Context, req core.LossRequest) (core.LossResult, error) {
+	return m.CalculateLossesFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateAveraging(ctx context.Context, req core.AveragingRequest) (core.AveragingResult, error) {
+	return m.CalculateAveragingFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateContribution(ctx context.Context, req core.ContributionRequest) (core.ContributionResult, error) {
+	return m.CalculateContributionFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateHousehold(ctx context.Context, req core.HouseholdRequest) (core.HouseholdResult, error) {
+	return m.CalculateHouseholdFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateCorporateTax(ctx context.Context, req core.CorporateRequest) (core.CorporateResult, error) {
+	return m.CalculateCorporateTaxFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateSalesTax(ctx context.Context, req core.SalesTaxRequest) (core.SalesTaxResult, error) {
+	return m.CalculateSalesTaxFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateLedger(ctx context.Context, req core.LedgerRequest) (core.LedgerResult, error) {
+	return m.CalculateLedgerFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateScenarios(ctx context.Context, req core.ScenarioRequest) (core.ScenarioResult, error) {
+	return m.CalculateScenariosFunc(ctx, req)
+}
+
+func (m *mockTaxCalculator) CalculateSimulation(ctx context.Context, req core.SimulationRequest) (core.SimulationResult, error) {
+	return m.CalculateSimulationFunc(ctx, req)
 }
 
 func TestTaxHandler(t *testing.T) {
 	tests := []struct {
 		name           string
 		method         string
+		query          string
 		body           map[string]interface{}
-		mockFunc       func(ctx context.Context, incomeStr string, yearStr string) (core.TaxResult, error)
+		mockFunc       func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error)
 		expectedCode   int
 		expectedBody   string
 		expectedHeader map[string]string
@@ -39,7 +105,7 @@ func TestTaxHandler(t *testing.T) {
 			name:   "Success case",
 			method: "POST",
 			body:   map[string]interface{}{"income": 10000.0, "year": 2022},
-			mockFunc: func(ctx context.Context, incomeStr, yearStr string) (core.TaxResult, error) {
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
 				return core.TaxResult{
 					TotalTax:      1234.56,
 					EffectiveRate: 0.123,
@@ -66,7 +132,7 @@ func TestTaxHandler(t *testing.T) {
 			body:         map[string]interface{}{}, // Missing income and year
 			expectedCode: http.StatusBadRequest,
 			expectedBody: "Missing required fields",
-			mockFunc: func(ctx context.Context, incomeStr, yearStr string) (core.TaxResult, error) {
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
 				return core.TaxResult{}, nil
 			},
 		},
@@ -77,7 +143,7 @@ func TestTaxHandler(t *testing.T) {
 			body:         map[string]interface{}{"income": "abc", "year": 2022}, // Invalid income format
 			expectedCode: http.StatusBadRequest,
 			expectedBody: "Invalid income",
-			mockFunc: func(ctx context.Context, incomeStr, yearStr string) (core.TaxResult, error) {
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
 				return core.TaxResult{}, nil
 			},
 		},
@@ -85,18 +151,221 @@ func TestTaxHandler(t *testing.T) {
 			name:   "Internal error from service",
 			method: "POST",
 			body:   map[string]interface{}{"income": 10000.0, "year": 2022},
-			mockFunc: func(ctx context.Context, incomeStr, yearStr string) (core.TaxResult, error) {
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
 				return core.TaxResult{}, errors.New("internal error")
 			},
 			expectedCode: http.StatusInternalServerError,
 			expectedBody: "Error calculating tax",
 		},
+		{
+			name:   "As-of date passed to service",
+			method: "POST",
+			body:   map[string]interface{}{"income": 10000.0, "year": 2022, "as_of": "2022-07-01"},
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				assert.Equal(t, core.TaxRequest{Income: 10000, Year: 2022, AsOf: "2022-07-01"}, req)
+				return core.TaxResult{TotalTax: 1000}, nil
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: "total_tax",
+		},
+		{
+			name:   "Jurisdiction passed to service",
+			method: "POST",
+			body:   map[string]interface{}{"income": 10000.0, "year": 2022, "jurisdiction": "ON"},
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				assert.Equal(t, "ON", req.Jurisdiction)
+				return core.TaxResult{
+					TotalTax: 1000,
+					Lines:    []core.TaxLine{{Name: "brackets", Type: core.RuleBrackets, Amount: 1000}},
+				}, nil
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"lines"`,
+		},
+		{
+			name:   "Capital gains and AMT carry-forward passed to service",
+			method: "POST",
+			body:   map[string]interface{}{"income": 10000.0, "year": 2022, "capital_gains": 5000.0, "amt_carryforward": 300.0},
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				assert.Equal(t, 5000.0, req.CapitalGains)
+				assert.Equal(t, 300.0, req.AMTCarryForward)
+				return core.TaxResult{
+					TotalTax: 1000,
+					AMT:      &core.AMTResult{Applies: core.AMTAppliesRegular},
+				}, nil
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"applies":"regular"`,
+		},
+		{
+			name:         "Negative capital gains",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 10000.0, "year": 2022, "capital_gains": -1.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Capital gains and AMT carry-forward must be non-negative",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
+		{
+			name:         "Invalid inflation rate",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 10000.0, "year": 2025, "inflation_rate": 2.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid inflation_rate",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
+		{
+			name:         "Negative business income",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 0.0, "year": 2022, "business_income": -1.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Business income must be non-negative",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
+		{
+			name:   "Invalid transaction",
+			method: "POST",
+			body: map[string]interface{}{"income": 0.0, "year": 2022, "transactions": []interface{}{
+				map[string]interface{}{"date": "2022-01-04", "security": "XYZ", "type": "gift", "quantity": 1.0, "price": 1.0},
+			}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid transactions",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
+		{
+			name:   "Sale exceeding the units held",
+			method: "POST",
+			body: map[string]interface{}{"income": 0.0, "year": 2022, "transactions": []interface{}{
+				map[string]interface{}{"date": "2022-01-04", "security": "XYZ", "type": "sell", "quantity": 1.0, "price": 1.0},
+			}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid transactions: insufficient units",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				_, err := core.ComputeLedger(req.Transactions)
+				return core.TaxResult{}, err
+			},
+		},
+		{
+			name:         "Negative dividends",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 0.0, "year": 2022, "dividends": -1.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Dividends must be non-negative",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
+		{
+			name:         "Negative withholding",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 10000.0, "year": 2022, "withheld": -1.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Withheld, instalments and refundable credits must be non-negative",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
+		{
+			name:         "Invalid payment date",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 10000.0, "year": 2022, "payment_date": "30/06/2023"},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid payment_date",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
+		{
+			name:         "Invalid residency date",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 10000.0, "year": 2022, "residency_start": "2022/07/01"},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid residency date",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
+		{
+			name:   "Residency ends before it starts",
+			method: "POST",
+			body: map[string]interface{}{"income": 10000.0, "year": 2022,
+				"residency_start": "2022-07-01", "residency_end": "2022-03-31"},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid residency: residency ends before it starts",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
+		{
+			name:   "Residency outside the tax year",
+			method: "POST",
+			body: map[string]interface{}{"income": 10000.0, "year": 2022,
+				"residency_start": "2023-02-01"},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid residency: residency does not overlap tax year 2022",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
+		{
+			name:         "Invalid as-of date",
+			method:       "POST",
+			body:         map[string]interface{}{"income": 10000.0, "year": 2022, "as_of": "07/01/2022"},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid as_of date",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
+		{
+			name:   "Explain query parameter",
+			method: "POST",
+			query:  "?explain=true",
+			body:   map[string]interface{}{"income": 10000.0, "year": 2022},
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				assert.True(t, req.Explain)
+				return core.TaxResult{
+					TotalTax: 1000,
+					Trace:    []core.TraceStep{{Step: 1, Stage: core.StageTotal, Description: "Total tax", Value: 1000}},
+				}, nil
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"trace"`,
+		},
+		{
+			name:   "Explain body field",
+			method: "POST",
+			body:   map[string]interface{}{"income": 10000.0, "year": 2022, "explain": true},
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				assert.True(t, req.Explain)
+				return core.TaxResult{}, nil
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Invalid explain parameter",
+			method:       "POST",
+			query:        "?explain=maybe",
+			body:         map[string]interface{}{"income": 10000.0, "year": 2022},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid explain parameter",
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+				return core.TaxResult{}, nil
+			},
+		},
 		{
 			name:           "OPTIONS method",
 			method:         "OPTIONS",
 			expectedCode:   http.StatusNoContent,
 			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
-			mockFunc: func(ctx context.Context, incomeStr, yearStr string) (core.TaxResult, error) {
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
 				return core.TaxResult{}, nil
 			},
 		},
@@ -105,7 +374,7 @@ func TestTaxHandler(t *testing.T) {
 			method:         "GET",
 			expectedCode:   http.StatusMethodNotAllowed,
 			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
-			mockFunc: func(ctx context.Context, incomeStr, yearStr string) (core.TaxResult, error) {
+			mockFunc: func(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
 				return core.TaxResult{}, nil
 			},
 		},
@@ -113,7 +382,7 @@ func TestTaxHandler(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mock := &mockTaxCalculator{CalculateTaxFunc: tt.mockFunc}
+			mock := &mockTaxCalculator{CalculateFunc: tt.mockFunc}
 			handler := &TaxCalculatorHandler{tc: mock}
 
 			var reqBody io.Reader
@@ -122,7 +391,7 @@ func TestTaxHandler(t *testing.T) {
 				reqBody = bytes.NewReader(b)
 			}
 
-			req := httptest.NewRequest(tt.method, "/tax", reqBody)
+			req := httptest.NewRequest(tt.method, "/tax"+tt.query, reqBody)
 			if tt.body != nil {
 				req.Header.Set("Content-Type", "application/json")
 			}
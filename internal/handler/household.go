@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type HouseholdHandler struct {
+	tc TaxCalculator
+}
+
+func (h *HouseholdHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tax/household" {
+		logger.Log.Warn().Msgf("Invalid URL path: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var request core.HouseholdRequest
+
+		// Decode JSON body
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			logger.Log.Error().Err(err).Msg("Invalid JSON body")
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		// Check for missing required fields
+		if request.Year == 0 || len(request.Members) == 0 {
+			logger.Log.Warn().Msg("Missing required fields in request")
+			http.Error(w, "Missing required fields", http.StatusBadRequest)
+			return
+		}
+
+		if len(request.Members) != 2 {
+			logger.Log.Warn().Msgf("Invalid household size: %d", len(request.Members))
+			http.Error(w, "A household has exactly two members", http.StatusBadRequest)
+			return
+		}
+
+		if request.Members[0].Income < 0 || request.Members[1].Income < 0 || request.Splittable < 0 {
+			logger.Log.Warn().Msg("Invalid household amounts, amounts must be non-negative")
+			http.Error(w, "Incomes and splittable amount must be non-negative", http.StatusBadRequest)
+			return
+		}
+
+		// Call the service
+		result, err := h.tc.CalculateHousehold(r.Context(), request)
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Error splitting household income")
+			http.Error(w, "Error splitting household income: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to encode response")
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		logger.Log.Warn().Msgf("Method %s not allowed for %s", r.Method, r.URL.Path)
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHouseholdHandler(t *testing.T) {
+	members := []map[string]interface{}{
+		{"name": "a", "income": 80000.0},
+		{"name": "b", "income": 10000.0},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.HouseholdRequest) (core.HouseholdResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.HouseholdResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"year": 2022, "members": members, "splittable": 20000.0},
+			mockFunc: func(ctx context.Context, req core.HouseholdRequest) (core.HouseholdResult, error) {
+				assert.Equal(t, []core.HouseholdMember{{Name: "a", Income: 80000}, {Name: "b", Income: 10000}}, req.Members)
+				assert.Equal(t, 20000.0, req.Splittable)
+				return core.HouseholdResult{Allocated: 20000, CombinedTax: 14000, TaxWithoutSplit: 18000, Saving: 4000}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.HouseholdResult{Allocated: 20000, CombinedTax: 14000, TaxWithoutSplit: 18000, Saving: 4000},
+		},
+		{
+			name:         "Missing members",
+			method:       "POST",
+			body:         map[string]interface{}{"year": 2022, "splittable": 20000.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:         "Three members",
+			method:       "POST",
+			body:         map[string]interface{}{"year": 2022, "members": append(members, members[0]), "splittable": 20000.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "A household has exactly two members",
+		},
+		{
+			name:         "Negative splittable amount",
+			method:       "POST",
+			body:         map[string]interface{}{"year": 2022, "members": members, "splittable": -1.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Incomes and splittable amount must be non-negative",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"year": 2022, "members": members, "splittable": 20000.0},
+			mockFunc: func(ctx context.Context, req core.HouseholdRequest) (core.HouseholdResult, error) {
+				return core.HouseholdResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error splitting household income",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateHouseholdFunc: tt.mockFunc}
+			handler := &HouseholdHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/household", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.HouseholdResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON, result)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type LedgerHandler struct {
+	tc TaxCalculator
+}
+
+func (h *LedgerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tax/ledger" {
+		logger.Log.Warn().Msgf("Invalid URL path: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var request core.LedgerRequest
+
+		// Decode JSON body
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			logger.Log.Error().Err(err).Msg("Invalid JSON body")
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		// Check for missing required fields
+		if len(request.Transactions) == 0 {
+			logger.Log.Warn().Msg("Missing required fields in request")
+			http.Error(w, "Missing required fields", http.StatusBadRequest)
+			return
+		}
+
+		if err := core.ValidateTransactions(request.Transactions); err != nil {
+			logger.Log.Warn().Err(err).Msg("Invalid transactions")
+			http.Error(w, "Invalid transactions: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Call the service
+		result, err := h.tc.CalculateLedger(r.Context(), request)
+		if errors.Is(err, core.ErrInsufficientUnits) {
+			logger.Log.Warn().Err(err).Msg("Invalid transactions")
+			http.Error(w, "Invalid transactions: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Error computing ledger")
+			http.Error(w, "Error computing ledger: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to encode response")
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		logger.Log.Warn().Msgf("Method %s not allowed for %s", r.Method, r.URL.Path)
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
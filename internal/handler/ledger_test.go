@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerHandler(t *testing.T) {
+	transactions := []interface{}{
+		map[string]interface{}{"date": "2022-01-04", "security": "XYZ", "type": "buy", "quantity": 100.0, "price": 10.0},
+		map[string]interface{}{"date": "2022-06-01", "security": "XYZ", "type": "sell", "quantity": 100.0, "price": 30.0},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.LedgerRequest) (core.LedgerResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.LedgerResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"transactions": transactions},
+			mockFunc: func(ctx context.Context, req core.LedgerRequest) (core.LedgerResult, error) {
+				assert.Equal(t, []core.Transaction{
+					{Date: "2022-01-04", Security: "XYZ", Type: core.TransactionBuy, Quantity: 100, Price: 10},
+					{Date: "2022-06-01", Security: "XYZ", Type: core.TransactionSell, Quantity: 100, Price: 30},
+				}, req.Transactions)
+				return core.LedgerResult{Years: []core.LedgerYear{{Year: 2022, Gains: 2000, NetGain: 2000}}}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.LedgerResult{Years: []core.LedgerYear{{Year: 2022, Gains: 2000, NetGain: 2000}}},
+		},
+		{
+			name:         "Missing transactions",
+			method:       "POST",
+			body:         map[string]interface{}{},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:   "Invalid transaction",
+			method: "POST",
+			body: map[string]interface{}{"transactions": []interface{}{
+				map[string]interface{}{"date": "2022-01-04", "security": "XYZ", "type": "buy", "quantity": 0.0, "price": 10.0},
+			}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid transactions",
+		},
+		{
+			name:   "Sale exceeding the units held",
+			method: "POST",
+			body:   map[string]interface{}{"transactions": transactions[1:]},
+			mockFunc: func(ctx context.Context, req core.LedgerRequest) (core.LedgerResult, error) {
+				return core.ComputeLedger(req.Transactions)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid transactions: insufficient units",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"transactions": transactions},
+			mockFunc: func(ctx context.Context, req core.LedgerRequest) (core.LedgerResult, error) {
+				return core.LedgerResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error computing ledger",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateLedgerFunc: tt.mockFunc}
+			handler := &LedgerHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/ledger", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.LedgerResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON, result)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
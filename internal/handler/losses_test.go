@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLossHandler(t *testing.T) {
+	years := []map[string]interface{}{
+		{"year": 2020, "income": 40000.0},
+		{"year": 2021, "non_capital_loss": 10000.0},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.LossRequest) (core.LossResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.LossResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"years": years},
+			mockFunc: func(ctx context.Context, req core.LossRequest) (core.LossResult, error) {
+				assert.Len(t, req.Years, 2)
+				assert.Equal(t, 10000.0, req.Years[1].NonCapitalLoss)
+				return core.LossResult{
+					Applications: []core.LossApplication{{LossYear: 2021, Kind: core.LossNonCapital, AppliedTo: 2020, Amount: 10000, Saving: 2000}},
+					Saving:       2000,
+				}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.LossResult{
+				Applications: []core.LossApplication{{LossYear: 2021, Kind: core.LossNonCapital, AppliedTo: 2020, Amount: 10000, Saving: 2000}},
+				Saving:       2000,
+			},
+		},
+		{
+			name:         "Missing years",
+			method:       "POST",
+			body:         map[string]interface{}{"jurisdiction": "ON"},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:         "Negative loss",
+			method:       "POST",
+			body:         map[string]interface{}{"years": []map[string]interface{}{{"year": 2020, "net_capital_loss": -1.0}}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Income, gains and losses must be non-negative",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"years": years},
+			mockFunc: func(ctx context.Context, req core.LossRequest) (core.LossResult, error) {
+				return core.LossResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error carrying losses",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateLossesFunc: tt.mockFunc}
+			handler := &LossHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/losses", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.LossResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON, result)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type ReverseHandler struct {
+	tc TaxCalculator
+}
+
+func (h *ReverseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tax/reverse" {
+		logger.Log.Warn().Msgf("Invalid URL path: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var request core.ReverseRequest
+
+		// Decode JSON body
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			logger.Log.Error().Err(err).Msg("Invalid JSON body")
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		// Check for missing required fields
+		if request.Year == 0 || (request.TargetNet == nil && request.TargetTax == nil) {
+			logger.Log.Warn().Msg("Missing required fields in request")
+			http.Error(w, "Missing required fields", http.StatusBadRequest)
+			return
+		}
+
+		if request.TargetNet != nil && request.TargetTax != nil {
+			logger.Log.Warn().Msg("Both target net and target tax supplied")
+			http.Error(w, "Only one of target_net or target_tax is allowed", http.StatusBadRequest)
+			return
+		}
+
+		if (request.TargetNet != nil && *request.TargetNet < 0) || (request.TargetTax != nil && *request.TargetTax < 0) {
+			logger.Log.Warn().Msg("Invalid target, target must be non-negative")
+			http.Error(w, "Target must be non-negative", http.StatusBadRequest)
+			return
+		}
+
+		// Call the service
+		result, err := h.tc.CalculateGrossIncome(r.Context(), request)
+		if errors.Is(err, core.ErrTargetUnreachable) {
+			logger.Log.Warn().Err(err).Msg("Target is not reachable")
+			http.Error(w, "Target is not reachable", http.StatusUnprocessableEntity)
+			return
+		}
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Error calculating gross income")
+			http.Error(w, "Error calculating gross income: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to encode response")
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		logger.Log.Warn().Msgf("Method %s not allowed for %s", r.Method, r.URL.Path)
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverseHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.ReverseRequest) (core.ReverseResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.ReverseResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"target_net": 40000.0, "year": 2022},
+			mockFunc: func(ctx context.Context, req core.ReverseRequest) (core.ReverseResult, error) {
+				assert.NotNil(t, req.TargetNet)
+				assert.Nil(t, req.TargetTax)
+				return core.ReverseResult{
+					GrossIncome: 50000,
+					NetIncome:   40000,
+					TaxResult:   core.TaxResult{TotalTax: 10000, EffectiveRate: 0.2},
+				}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.ReverseResult{
+				GrossIncome: 50000,
+				NetIncome:   40000,
+				TaxResult:   core.TaxResult{TotalTax: 10000, EffectiveRate: 0.2},
+			},
+		},
+		{
+			name:         "Missing target",
+			method:       "POST",
+			body:         map[string]interface{}{"year": 2022},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:         "Both targets",
+			method:       "POST",
+			body:         map[string]interface{}{"target_net": 1.0, "target_tax": 1.0, "year": 2022},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Only one of target_net or target_tax is allowed",
+		},
+		{
+			name:         "Negative target",
+			method:       "POST",
+			body:         map[string]interface{}{"target_tax": -1.0, "year": 2022},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Target must be non-negative",
+		},
+		{
+			name:   "Unreachable target",
+			method: "POST",
+			body:   map[string]interface{}{"target_tax": 1e9, "year": 2022},
+			mockFunc: func(ctx context.Context, req core.ReverseRequest) (core.ReverseResult, error) {
+				return core.ReverseResult{}, fmt.Errorf("failed to solve for gross income: %w", core.ErrTargetUnreachable)
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "Target is not reachable",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"target_net": 40000.0, "year": 2022},
+			mockFunc: func(ctx context.Context, req core.ReverseRequest) (core.ReverseResult, error) {
+				return core.ReverseResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error calculating gross income",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateGrossIncomeFunc: tt.mockFunc}
+			handler := &ReverseHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/reverse", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.ReverseResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON.GrossIncome, result.GrossIncome)
+				assert.Equal(t, tt.expectedJSON.NetIncome, result.NetIncome)
+				assert.Equal(t, tt.expectedJSON.TotalTax, result.TotalTax)
+				assert.Equal(t, tt.expectedJSON.EffectiveRate, result.EffectiveRate)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
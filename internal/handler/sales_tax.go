@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type SalesTaxHandler struct {
+	tc TaxCalculator
+}
+
+func (h *SalesTaxHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tax/sales" {
+		logger.Log.Warn().Msgf("Invalid URL path: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var request core.SalesTaxRequest
+
+		// Decode JSON body
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			logger.Log.Error().Err(err).Msg("Invalid JSON body")
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		// Check for missing required fields
+		if request.Jurisdiction == "" || request.Date == "" || len(request.Items) == 0 {
+			logger.Log.Warn().Msg("Missing required fields in request")
+			http.Error(w, "Missing required fields", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := time.Parse(core.DateFormat, request.Date); err != nil {
+			logger.Log.Warn().Msgf("Invalid sales date: %s", request.Date)
+			http.Error(w, "Invalid date, expected format "+core.DateFormat, http.StatusBadRequest)
+			return
+		}
+
+		for _, item := range request.Items {
+			if item.Amount < 0 {
+				logger.Log.Warn().Msgf("Invalid item amount %f, amounts must be non-negative", item.Amount)
+				http.Error(w, "Item amounts must be non-negative", http.StatusBadRequest)
+				return
+			}
+			switch item.Category {
+			case "", core.CategoryStandard, core.CategoryZeroRated, core.CategoryExempt:
+			default:
+				logger.Log.Warn().Msgf("Invalid item category %q", item.Category)
+				http.Error(w, "Item category must be standard, zero_rated or exempt", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Call the service
+		result, err := h.tc.CalculateSalesTax(r.Context(), request)
+		if errors.Is(err, core.ErrNoSalesTaxRate) {
+			logger.Log.Warn().Err(err).Msg("No sales tax rate in effect")
+			http.Error(w, "No sales tax rate in effect: "+err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Error calculating sales tax")
+			http.Error(w, "Error calculating sales tax: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to encode response")
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		logger.Log.Warn().Msgf("Method %s not allowed for %s", r.Method, r.URL.Path)
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSalesTaxHandler(t *testing.T) {
+	items := []interface{}{map[string]interface{}{"amount": 100.0, "category": "standard"}}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.SalesTaxRequest) (core.SalesTaxResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.SalesTaxResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"jurisdiction": "ON", "date": "2022-05-01", "inclusive": true, "items": items},
+			mockFunc: func(ctx context.Context, req core.SalesTaxRequest) (core.SalesTaxResult, error) {
+				assert.Equal(t, core.SalesTaxRequest{
+					Jurisdiction: "ON",
+					Date:         "2022-05-01",
+					Inclusive:    true,
+					Items:        []core.SalesTaxItem{{Amount: 100, Category: core.CategoryStandard}},
+				}, req)
+				return core.SalesTaxResult{Jurisdiction: "ON", Date: "2022-05-01", Inclusive: true, Net: 88.5, Tax: 11.5, Gross: 100}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.SalesTaxResult{Jurisdiction: "ON", Date: "2022-05-01", Inclusive: true, Net: 88.5, Tax: 11.5, Gross: 100},
+		},
+		{
+			name:         "Missing items",
+			method:       "POST",
+			body:         map[string]interface{}{"jurisdiction": "ON", "date": "2022-05-01"},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:         "Missing date",
+			method:       "POST",
+			body:         map[string]interface{}{"jurisdiction": "ON", "items": items},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:   "Negative amount",
+			method: "POST",
+			body: map[string]interface{}{"jurisdiction": "ON", "date": "2022-05-01",
+				"items": []interface{}{map[string]interface{}{"amount": -1.0}}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Item amounts must be non-negative",
+		},
+		{
+			name:   "Unknown category",
+			method: "POST",
+			body: map[string]interface{}{"jurisdiction": "ON", "date": "2022-05-01",
+				"items": []interface{}{map[string]interface{}{"amount": 1.0, "category": "luxury"}}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Item category must be standard, zero_rated or exempt",
+		},
+		{
+			name:         "Invalid date",
+			method:       "POST",
+			body:         map[string]interface{}{"jurisdiction": "ON", "date": "05/01/2022", "items": items},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid date, expected format 2006-01-02",
+		},
+		{
+			name:   "No rate in effect on the date",
+			method: "POST",
+			body:   map[string]interface{}{"jurisdiction": "ON", "date": "1990-01-01", "items": items},
+			mockFunc: func(ctx context.Context, req core.SalesTaxRequest) (core.SalesTaxResult, error) {
+				return core.SalesTaxResult{}, fmt.Errorf("%w for \"ON\" on 1990-01-01", core.ErrNoSalesTaxRate)
+			},
+			expectedCode: http.StatusNotFound,
+			expectedBody: "No sales tax rate in effect",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"jurisdiction": "ON", "date": "2022-05-01", "items": items},
+			mockFunc: func(ctx context.Context, req core.SalesTaxRequest) (core.SalesTaxResult, error) {
+				return core.SalesTaxResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error calculating sales tax",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateSalesTaxFunc: tt.mockFunc}
+			handler := &SalesTaxHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/sales", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.SalesTaxResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON, result)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
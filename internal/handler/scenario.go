@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type ScenarioHandler struct {
+	tc TaxCalculator
+}
+
+func (h *ScenarioHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tax/scenarios" {
+		logger.Log.Warn().Msgf("Invalid URL path: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var request core.ScenarioRequest
+
+		// Decode JSON body
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			logger.Log.Error().Err(err).Msg("Invalid JSON body")
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		// Check for missing required fields
+		if len(request.Base) == 0 || len(request.Scenarios) == 0 {
+			logger.Log.Warn().Msg("Missing required fields in request")
+			http.Error(w, "Missing required fields", http.StatusBadRequest)
+			return
+		}
+
+		if len(request.Scenarios) > core.MaxScenarios {
+			logger.Log.Warn().Msgf("Too many scenarios: %d", len(request.Scenarios))
+			http.Error(w, fmt.Sprintf("At most %d scenarios can be compared", core.MaxScenarios), http.StatusBadRequest)
+			return
+		}
+
+		for _, year := range request.Base {
+			if year.Year == 0 {
+				logger.Log.Warn().Msg("Missing year in base profile")
+				http.Error(w, "Missing required fields", http.StatusBadRequest)
+				return
+			}
+			if year.Income < 0 {
+				logger.Log.Warn().Msgf("Invalid income %f for year %d, income must be non-negative", year.Income, year.Year)
+				http.Error(w, "Income must be non-negative", http.StatusBadRequest)
+				return
+			}
+			if _, err := core.ComputeResidency(year); err != nil {
+				logger.Log.Warn().Err(err).Msgf("Invalid residency period for year %d", year.Year)
+				http.Error(w, fmt.Sprintf("Invalid residency for year %d: %s", year.Year, err.Error()), http.StatusBadRequest)
+				return
+			}
+		}
+
+		for _, scenario := range request.Scenarios {
+			if scenario.Name == "" {
+				logger.Log.Warn().Msg("Missing scenario name")
+				http.Error(w, "Every scenario needs a name", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Check that every modification applies to the base profile
+		if err := core.ValidateScenarios(request); err != nil {
+			logger.Log.Warn().Err(err).Msg("Invalid scenarios")
+			http.Error(w, "Invalid scenarios: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Call the service
+		result, err := h.tc.CalculateScenarios(r.Context(), request)
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Error comparing scenarios")
+			http.Error(w, "Error comparing scenarios: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to encode response")
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		logger.Log.Warn().Msgf("Method %s not allowed for %s", r.Method, r.URL.Path)
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScenarioHandler(t *testing.T) {
+	base := []interface{}{map[string]interface{}{"income": 80000.0, "year": 2021}}
+	scenarios := []interface{}{map[string]interface{}{
+		"name":          "bonus",
+		"modifications": []interface{}{map[string]interface{}{"field": "income", "operation": "add", "value": 10000.0}},
+	}}
+	tooMany := make([]interface{}, core.MaxScenarios+1)
+	for i := range tooMany {
+		tooMany[i] = scenarios[0]
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.ScenarioRequest) (core.ScenarioResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.ScenarioResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"base": base, "scenarios": scenarios},
+			mockFunc: func(ctx context.Context, req core.ScenarioRequest) (core.ScenarioResult, error) {
+				assert.Equal(t, core.ScenarioRequest{
+					Base: []core.TaxRequest{{Income: 80000, Year: 2021}},
+					Scenarios: []core.Scenario{{Name: "bonus", Modifications: []core.Modification{
+						{Field: "income", Operation: core.OperationAdd, Value: 10000},
+					}}},
+				}, req)
+				return core.ScenarioResult{
+					Base:      core.ScenarioOutcome{Name: "base", TotalTax: 16000},
+					Scenarios: []core.ScenarioOutcome{{Name: "bonus", Rank: 1, TotalTax: 19000, Difference: &core.ScenarioDifference{TotalTax: 3000}}},
+				}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.ScenarioResult{
+				Base:      core.ScenarioOutcome{Name: "base", TotalTax: 16000},
+				Scenarios: []core.ScenarioOutcome{{Name: "bonus", Rank: 1, TotalTax: 19000, Difference: &core.ScenarioDifference{TotalTax: 3000}}},
+			},
+		},
+		{
+			name:         "Missing scenarios",
+			method:       "POST",
+			body:         map[string]interface{}{"base": base},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:   "Missing base year",
+			method: "POST",
+			body: map[string]interface{}{"base": []interface{}{map[string]interface{}{"income": 80000.0}},
+				"scenarios": scenarios},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:         "Too many scenarios",
+			method:       "POST",
+			body:         map[string]interface{}{"base": base, "scenarios": tooMany},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "At most 20 scenarios can be compared",
+		},
+		{
+			name:   "Negative base income",
+			method: "POST",
+			body: map[string]interface{}{"base": []interface{}{map[string]interface{}{"income": -1.0, "year": 2021}},
+				"scenarios": scenarios},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Income must be non-negative",
+		},
+		{
+			name:   "Base year residency outside the tax year",
+			method: "POST",
+			body: map[string]interface{}{"base": []interface{}{map[string]interface{}{"income": 1.0, "year": 2021,
+				"residency_end": "2020-12-31"}}, "scenarios": scenarios},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid residency for year 2021: residency does not overlap tax year 2021",
+		},
+		{
+			name:   "Unnamed scenario",
+			method: "POST",
+			body: map[string]interface{}{"base": base,
+				"scenarios": []interface{}{map[string]interface{}{"modifications": []interface{}{}}}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Every scenario needs a name",
+		},
+		{
+			name:   "Unknown modification field",
+			method: "POST",
+			body: map[string]interface{}{"base": base, "scenarios": []interface{}{map[string]interface{}{
+				"name":          "salary",
+				"modifications": []interface{}{map[string]interface{}{"field": "salary", "value": 1.0}},
+			}}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `Invalid scenarios: scenario "salary": unknown field "salary"`,
+		},
+		{
+			name:   "Modification making an amount negative",
+			method: "POST",
+			body: map[string]interface{}{"base": base, "scenarios": []interface{}{map[string]interface{}{
+				"name":          "cut",
+				"modifications": []interface{}{map[string]interface{}{"field": "income", "operation": "add", "value": -90000.0}},
+			}}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "would be negative",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"base": base, "scenarios": scenarios},
+			mockFunc: func(ctx context.Context, req core.ScenarioRequest) (core.ScenarioResult, error) {
+				return core.ScenarioResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error comparing scenarios",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateScenariosFunc: tt.mockFunc}
+			handler := &ScenarioHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/scenarios", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.ScenarioResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON, result)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type SimulationHandler struct {
+	tc TaxCalculator
+}
+
+func (h *SimulationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tax/simulation" {
+		logger.Log.Warn().Msgf("Invalid URL path: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var request core.SimulationRequest
+
+		// Decode JSON body
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			logger.Log.Error().Err(err).Msg("Invalid JSON body")
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		// Check for missing required fields
+		if request.Year == 0 || request.Distribution.Type == "" {
+			logger.Log.Warn().Msg("Missing required fields in request")
+			http.Error(w, "Missing required fields", http.StatusBadRequest)
+			return
+		}
+
+		if err := request.Distribution.Validate(); err != nil {
+			logger.Log.Warn().Err(err).Msg("Invalid income distribution")
+			http.Error(w, "Invalid distribution: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if request.Trials < 0 || request.Trials > core.MaxTrials {
+			logger.Log.Warn().Msgf("Invalid number of trials: %d", request.Trials)
+			http.Error(w, fmt.Sprintf("Trials must be between 0 and %d", core.MaxTrials), http.StatusBadRequest)
+			return
+		}
+
+		for _, p := range request.Percentiles {
+			if p < 0 || p > 100 {
+				logger.Log.Warn().Msgf("Invalid percentile: %g", p)
+				http.Error(w, "Percentiles must be between 0 and 100", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Call the service
+		result, err := h.tc.CalculateSimulation(r.Context(), request)
+		if errors.Is(err, core.ErrTooManyTrials) {
+			logger.Log.Warn().Err(err).Msg("Simulation has too many trials")
+			http.Error(w, fmt.Sprintf("Trials must be between 0 and %d for years taxed by rules", core.MaxPipelineTrials), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Error running simulation")
+			http.Error(w, "Error running simulation: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to encode response")
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		logger.Log.Warn().Msgf("Method %s not allowed for %s", r.Method, r.URL.Path)
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulationHandler(t *testing.T) {
+	normal := map[string]interface{}{"type": "normal", "mean": 60000.0, "std_dev": 15000.0}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.SimulationRequest) (core.SimulationResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.SimulationResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"year": 2022, "distribution": normal, "trials": 5000, "seed": 42},
+			mockFunc: func(ctx context.Context, req core.SimulationRequest) (core.SimulationResult, error) {
+				assert.Equal(t, core.SimulationRequest{
+					Year:         2022,
+					Distribution: core.Distribution{Type: core.DistributionNormal, Mean: 60000, StdDev: 15000},
+					Trials:       5000,
+					Seed:         42,
+				}, req)
+				return core.SimulationResult{Year: 2022, Trials: 5000, Seed: 42, ExpectedTax: 9000}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.SimulationResult{Year: 2022, Trials: 5000, Seed: 42, ExpectedTax: 9000},
+		},
+		{
+			name:         "Missing distribution",
+			method:       "POST",
+			body:         map[string]interface{}{"year": 2022},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:         "Invalid distribution",
+			method:       "POST",
+			body:         map[string]interface{}{"year": 2022, "distribution": map[string]interface{}{"type": "empirical"}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid distribution",
+		},
+		{
+			name:         "Too many trials",
+			method:       "POST",
+			body:         map[string]interface{}{"year": 2022, "distribution": normal, "trials": core.MaxTrials + 1},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Trials must be between 0 and 1000000",
+		},
+		{
+			name:         "Percentile out of range",
+			method:       "POST",
+			body:         map[string]interface{}{"year": 2022, "distribution": normal, "percentiles": []float64{50, 101}},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Percentiles must be between 0 and 100",
+		},
+		{
+			name:   "Too many trials for a pipeline year",
+			method: "POST",
+			body:   map[string]interface{}{"year": 2022, "distribution": normal, "trials": core.MaxPipelineTrials + 1},
+			mockFunc: func(ctx context.Context, req core.SimulationRequest) (core.SimulationResult, error) {
+				return core.SimulationResult{}, fmt.Errorf("failed to run simulation: %w", core.ErrTooManyTrials)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Trials must be between 0 and 100000 for years taxed by rules",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"year": 2022, "distribution": normal},
+			mockFunc: func(ctx context.Context, req core.SimulationRequest) (core.SimulationResult, error) {
+				return core.SimulationResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error running simulation",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateSimulationFunc: tt.mockFunc}
+			handler := &SimulationHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/simulation", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.SimulationResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON, result)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
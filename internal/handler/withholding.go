@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type WithholdingHandler struct {
+	tc TaxCalculator
+}
+
+func (h *WithholdingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tax/withholding" {
+		logger.Log.Warn().Msgf("Invalid URL path: %s", r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var request core.WithholdingRequest
+
+		// Decode JSON body
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			logger.Log.Error().Err(err).Msg("Invalid JSON body")
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		// Check for missing required fields
+		if request.Frequency == "" || request.Year == 0 {
+			logger.Log.Warn().Msg("Missing required fields in request")
+			http.Error(w, "Missing required fields", http.StatusBadRequest)
+			return
+		}
+
+		if request.Frequency.PeriodsPerYear() == 0 {
+			logger.Log.Warn().Msgf("Invalid pay frequency: %s", request.Frequency)
+			http.Error(w, "Invalid pay frequency", http.StatusBadRequest)
+			return
+		}
+
+		if periods := request.Frequency.PeriodsPerYear(); request.PayPeriod < 0 || request.PayPeriod > periods {
+			logger.Log.Warn().Msgf("Invalid pay period %d for %s pay", request.PayPeriod, request.Frequency)
+			http.Error(w, fmt.Sprintf("Pay period must be between 1 and %d", periods), http.StatusBadRequest)
+			return
+		}
+
+		if request.Gross < 0 || request.YTDGross < 0 || request.YTDWithheld < 0 {
+			logger.Log.Warn().Msg("Invalid pay amounts, amounts must be non-negative")
+			http.Error(w, "Pay amounts must be non-negative", http.StatusBadRequest)
+			return
+		}
+
+		// Call the service
+		result, err := h.tc.CalculateWithholding(r.Context(), request)
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Error calculating withholding")
+			http.Error(w, "Error calculating withholding: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to encode response")
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodOptions:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		logger.Log.Warn().Msgf("Method %s not allowed for %s", r.Method, r.URL.Path)
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
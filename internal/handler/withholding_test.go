@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithholdingHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           map[string]interface{}
+		mockFunc       func(ctx context.Context, req core.WithholdingRequest) (core.WithholdingResult, error)
+		expectedCode   int
+		expectedBody   string
+		expectedHeader map[string]string
+		validateJSON   bool
+		expectedJSON   core.WithholdingResult
+	}{
+		{
+			name:   "Success case",
+			method: "POST",
+			body:   map[string]interface{}{"gross": 5000.0, "frequency": "monthly", "year": 2022},
+			mockFunc: func(ctx context.Context, req core.WithholdingRequest) (core.WithholdingResult, error) {
+				assert.Equal(t, core.Monthly, req.Frequency)
+				return core.WithholdingResult{
+					Withholding:      800,
+					ProjectedIncome:  60000,
+					PeriodsPerYear:   12,
+					PeriodsRemaining: 12,
+				}, nil
+			},
+			expectedCode: http.StatusOK,
+			validateJSON: true,
+			expectedJSON: core.WithholdingResult{
+				Withholding:      800,
+				ProjectedIncome:  60000,
+				PeriodsPerYear:   12,
+				PeriodsRemaining: 12,
+			},
+		},
+		{
+			name:         "Missing body parameters",
+			method:       "POST",
+			body:         map[string]interface{}{"gross": 5000.0},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Missing required fields",
+		},
+		{
+			name:         "Invalid frequency",
+			method:       "POST",
+			body:         map[string]interface{}{"gross": 5000.0, "frequency": "daily", "year": 2022},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid pay frequency",
+		},
+		{
+			name:         "Pay period after the last of the year",
+			method:       "POST",
+			body:         map[string]interface{}{"gross": 1000.0, "frequency": "weekly", "year": 2022, "pay_period": 53},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Pay period must be between 1 and 52",
+		},
+		{
+			name:         "Negative pay period",
+			method:       "POST",
+			body:         map[string]interface{}{"gross": 1000.0, "frequency": "monthly", "year": 2022, "pay_period": -1},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Pay period must be between 1 and 12",
+		},
+		{
+			name:         "Negative gross",
+			method:       "POST",
+			body:         map[string]interface{}{"gross": -5000.0, "frequency": "monthly", "year": 2022},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Pay amounts must be non-negative",
+		},
+		{
+			name:   "Internal error from service",
+			method: "POST",
+			body:   map[string]interface{}{"gross": 5000.0, "frequency": "monthly", "year": 2022},
+			mockFunc: func(ctx context.Context, req core.WithholdingRequest) (core.WithholdingResult, error) {
+				return core.WithholdingResult{}, errors.New("internal error")
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Error calculating withholding",
+		},
+		{
+			name:           "OPTIONS method",
+			method:         "OPTIONS",
+			expectedCode:   http.StatusNoContent,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         "GET",
+			expectedCode:   http.StatusMethodNotAllowed,
+			expectedHeader: map[string]string{"Allow": "POST, OPTIONS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTaxCalculator{CalculateWithholdingFunc: tt.mockFunc}
+			handler := &WithholdingHandler{tc: mock}
+
+			var reqBody io.Reader
+			if tt.body != nil {
+				b, _ := json.Marshal(tt.body)
+				reqBody = bytes.NewReader(b)
+			}
+
+			req := httptest.NewRequest(tt.method, "/tax/withholding", reqBody)
+			if tt.body != nil {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			for k, v := range tt.expectedHeader {
+				assert.Equal(t, v, w.Header().Get(k))
+			}
+
+			if tt.validateJSON {
+				var result core.WithholdingResult
+				err := json.NewDecoder(w.Body).Decode(&result)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedJSON.Withholding, result.Withholding)
+				assert.Equal(t, tt.expectedJSON.ProjectedIncome, result.ProjectedIncome)
+				assert.Equal(t, tt.expectedJSON.PeriodsPerYear, result.PeriodsPerYear)
+				assert.Equal(t, tt.expectedJSON.PeriodsRemaining, result.PeriodsRemaining)
+			} else if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
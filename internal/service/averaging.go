@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to average income across a block of years
+func (s *taxService) CalculateAveraging(ctx context.Context, req core.AveragingRequest) (core.AveragingResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
+	if err != nil {
+		return core.AveragingResult{}, err
+	}
+
+	if len(req.Years) < 2 {
+		logger.Log.Error().Msgf("Not enough years to average: %d", len(req.Years))
+		return core.AveragingResult{}, fmt.Errorf("at least two years are required")
+	}
+
+	seen := make(map[int]bool)
+	years := make([]core.TaxYear, len(req.Years))
+	for i, year := range req.Years {
+		if seen[year.Year] {
+			logger.Log.Error().Msgf("Duplicate year in averaging: %d", year.Year)
+			return core.AveragingResult{}, fmt.Errorf("year %d is listed more than once", year.Year)
+		}
+		seen[year.Year] = true
+
+		if year.Income < 0 {
+			logger.Log.Error().Msgf("Invalid income for year %d: %.2f", year.Year, year.Income)
+			return core.AveragingResult{}, fmt.Errorf("invalid income for year %d", year.Year)
+		}
+
+		taxYear, err := s.taxYear(ctx, req.Jurisdiction, year.Year, nil)
+		if err != nil {
+			return core.AveragingResult{}, err
+		}
+		years[i] = taxYear
+	}
+
+	result, err := core.ComputeAveraging(req, years)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to average income")
+		return core.AveragingResult{}, err
+	}
+
+	logger.Log.Info().Msgf("Averaged income across %d years, saving: %.2f", len(req.Years), result.Saving)
+
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateAveraging(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+
+	tests := []struct {
+		name           string
+		req            core.AveragingRequest
+		mockErr        error
+		expectedError  string
+		expectedSaving float64
+	}{
+		{
+			name: "Averaging uses the zero-rate band of every year",
+			req: core.AveragingRequest{Years: []core.AveragingYear{
+				{Year: 2021, Income: 0},
+				{Year: 2022, Income: 40000},
+			}},
+			expectedSaving: 6000 - 2*2000,
+		},
+		{
+			name:          "Single year",
+			req:           core.AveragingRequest{Years: []core.AveragingYear{{Year: 2022, Income: 40000}}},
+			expectedError: "at least two years are required",
+		},
+		{
+			name: "Duplicate year",
+			req: core.AveragingRequest{Years: []core.AveragingYear{
+				{Year: 2022, Income: 0},
+				{Year: 2022, Income: 40000},
+			}},
+			expectedError: "year 2022 is listed more than once",
+		},
+		{
+			name: "Negative income",
+			req: core.AveragingRequest{Years: []core.AveragingYear{
+				{Year: 2021, Income: -1},
+				{Year: 2022, Income: 40000},
+			}},
+			expectedError: "invalid income for year 2021",
+		},
+		{
+			name: "Storage error",
+			req: core.AveragingRequest{Years: []core.AveragingYear{
+				{Year: 2021, Income: 0},
+				{Year: 2022, Income: 40000},
+			}},
+			mockErr:       errors.New("storage down"),
+			expectedError: "failed to fetch tax brackets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{brackets: brackets, err: tt.mockErr}
+
+			svc := service.NewTaxService(mock)
+			result, err := svc.CalculateAveraging(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedSaving, result.Saving, 0.0001)
+		})
+	}
+}
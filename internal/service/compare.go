@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to compare the tax on one income across several years
+func (s *taxService) CompareYears(ctx context.Context, req core.CompareRequest) (core.CompareResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
+	if err != nil {
+		return core.CompareResult{}, err
+	}
+
+	if req.Income < 0 {
+		logger.Log.Error().Msgf("Invalid income: %.2f", req.Income)
+		return core.CompareResult{}, fmt.Errorf("invalid income")
+	}
+
+	if len(req.Years) < 2 {
+		logger.Log.Error().Msgf("Not enough years to compare: %v", req.Years)
+		return core.CompareResult{}, fmt.Errorf("at least two years are required")
+	}
+
+	seen := make(map[int]bool)
+	years := make([]core.TaxYear, len(req.Years))
+	for i, year := range req.Years {
+		if seen[year] {
+			logger.Log.Error().Msgf("Duplicate year in comparison: %d", year)
+			return core.CompareResult{}, fmt.Errorf("year %d is listed more than once", year)
+		}
+		seen[year] = true
+
+		taxYear, err := s.taxYear(ctx, req.Jurisdiction, year, nil)
+		if err != nil {
+			return core.CompareResult{}, err
+		}
+		years[i] = taxYear
+	}
+
+	result, err := core.ComputeComparison(req, years)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to compare years")
+		return core.CompareResult{}, err
+	}
+
+	logger.Log.Info().Msgf("Compared tax for income: %.2f across years: %v", req.Income, req.Years)
+
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareYears(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0.1},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+
+	tests := []struct {
+		name          string
+		req           core.CompareRequest
+		mockErr       error
+		expectedError string
+	}{
+		{
+			name: "Two years",
+			req:  core.CompareRequest{Income: 50000, Years: []int{2019, 2022}},
+		},
+		{
+			name: "Published and projected years",
+			req:  core.CompareRequest{Income: 50000, Years: []int{2022, 2024}},
+		},
+		{
+			name:          "Single year",
+			req:           core.CompareRequest{Income: 50000, Years: []int{2019}},
+			expectedError: "at least two years are required",
+		},
+		{
+			name:          "Duplicate year",
+			req:           core.CompareRequest{Income: 50000, Years: []int{2019, 2019}},
+			expectedError: "year 2019 is listed more than once",
+		},
+		{
+			name:          "Negative income",
+			req:           core.CompareRequest{Income: -1, Years: []int{2019, 2022}},
+			expectedError: "invalid income",
+		},
+		{
+			name:          "Unsupported year",
+			req:           core.CompareRequest{Income: 50000, Years: []int{2019, 2040}},
+			expectedError: "tax year 2040 is not supported",
+		},
+		{
+			name:          "Error from storage",
+			req:           core.CompareRequest{Income: 50000, Years: []int{2019, 2022}},
+			mockErr:       errors.New("failed to fetch"),
+			expectedError: "failed to fetch tax brackets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{
+				brackets: brackets,
+				err:      tt.mockErr,
+			}
+
+			svc := service.NewTaxService(mock)
+			result, err := svc.CompareYears(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, result.Results, len(tt.req.Years))
+			assert.Len(t, result.Deltas, len(tt.req.Years)-1)
+			assert.Equal(t, 0.0, result.Deltas[0].TotalTax)
+			assert.Empty(t, result.Deltas[0].ScheduleChanges)
+			for i, year := range tt.req.Years {
+				tax, err := svc.Calculate(context.Background(), core.TaxRequest{Income: tt.req.Income, Year: year})
+				assert.NoError(t, err)
+				assert.Equal(t, tax.TotalTax, result.Results[i].TotalTax)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to find the tax saved by a retirement contribution
+func (s *taxService) CalculateContribution(ctx context.Context, req core.ContributionRequest) (core.ContributionResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
+	if err != nil {
+		return core.ContributionResult{}, err
+	}
+
+	if req.Income < 0 {
+		logger.Log.Error().Msgf("Invalid income: %.2f", req.Income)
+		return core.ContributionResult{}, fmt.Errorf("invalid income")
+	}
+
+	if req.Room < 0 {
+		logger.Log.Error().Msgf("Invalid contribution room: %.2f", req.Room)
+		return core.ContributionResult{}, fmt.Errorf("contribution room must be non-negative")
+	}
+
+	year, err := s.taxYear(ctx, req.Jurisdiction, req.Year, nil)
+	if err != nil {
+		return core.ContributionResult{}, err
+	}
+
+	result, err := core.ComputeContribution(req, year)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to optimize contribution")
+		return core.ContributionResult{}, err
+	}
+
+	logger.Log.Info().Msgf("Optimized contribution for income: %.2f, room: %.2f, year: %d", req.Income, req.Room, req.Year)
+
+	return result, nil
+}
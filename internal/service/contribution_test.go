@@ -0,0 +1,79 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateContribution(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+
+	tests := []struct {
+		name                    string
+		req                     core.ContributionRequest
+		mockErr                 error
+		expectedError           string
+		expectedRecommendations int
+		projected               bool
+	}{
+		{
+			name:                    "Room crossing a bracket boundary",
+			req:                     core.ContributionRequest{Income: 15000, Year: 2022, Room: 10000},
+			expectedRecommendations: 2,
+		},
+		{
+			name:                    "Projected year",
+			req:                     core.ContributionRequest{Income: 15000, Year: 2024, Room: 10000},
+			expectedRecommendations: 2,
+			projected:               true,
+		},
+		{
+			name:          "Negative room",
+			req:           core.ContributionRequest{Income: 15000, Year: 2022, Room: -1},
+			expectedError: "contribution room must be non-negative",
+		},
+		{
+			name:          "Negative income",
+			req:           core.ContributionRequest{Income: -1, Year: 2022, Room: 1000},
+			expectedError: "invalid income",
+		},
+		{
+			name:          "Unsupported year",
+			req:           core.ContributionRequest{Income: 15000, Year: 2018, Room: 1000},
+			expectedError: "tax year 2018 is not supported",
+		},
+		{
+			name:          "Storage error",
+			req:           core.ContributionRequest{Income: 15000, Year: 2022, Room: 1000},
+			mockErr:       errors.New("storage down"),
+			expectedError: "failed to fetch tax brackets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{brackets: brackets, err: tt.mockErr}
+
+			svc := service.NewTaxService(mock)
+			result, err := svc.CalculateContribution(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, result.Recommendations, tt.expectedRecommendations)
+			assert.Equal(t, tt.projected, result.Projection != nil)
+		})
+	}
+}
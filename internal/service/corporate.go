@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to calculate the income tax of a corporation
+func (s *taxService) CalculateCorporateTax(ctx context.Context, req core.CorporateRequest) (core.CorporateResult, error) {
+	if req.ActiveIncome < 0 {
+		logger.Log.Error().Msgf("Invalid active income: %.2f", req.ActiveIncome)
+		return core.CorporateResult{}, fmt.Errorf("invalid income")
+	}
+
+	if req.PassiveIncome < 0 {
+		logger.Log.Error().Msgf("Invalid passive income: %.2f", req.PassiveIncome)
+		return core.CorporateResult{}, fmt.Errorf("passive income must be non-negative")
+	}
+
+	if s.corporate == nil {
+		logger.Log.Error().Msg("Corporate storage is not configured")
+		return core.CorporateResult{}, fmt.Errorf("corporate parameters are not configured")
+	}
+
+	if err := s.ValidateTaxYear(ctx, strconv.Itoa(req.Year)); err != nil {
+		logger.Log.Error().Err(err).Msgf("Invalid tax year: %d", req.Year)
+		return core.CorporateResult{}, err
+	}
+
+	params, err := s.corporate.FetchCorporateParameters(ctx, req.Year)
+	if err != nil {
+		logger.Log.Error().Err(err).Msgf("Failed to fetch corporate parameters for year %d", req.Year)
+		return core.CorporateResult{}, fmt.Errorf("failed to fetch corporate parameters: %w", err)
+	}
+
+	result, err := core.ComputeCorporateTax(req, params)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to compute corporate tax")
+		return core.CorporateResult{}, err
+	}
+
+	logger.Log.Info().Msgf("Calculated corporate tax: %.2f for active income: %.2f, year: %d", result.TotalTax, req.ActiveIncome, req.Year)
+
+	return result, nil
+}
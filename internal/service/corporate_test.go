@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mock corporate storage
+type mockCorporateStorage struct {
+	params core.CorporateParameters
+	err    error
+}
+
+func (m *mockCorporateStorage) FetchCorporateParameters(ctx context.Context, year int) (core.CorporateParameters, error) {
+	if m.err != nil {
+		return core.CorporateParameters{}, m.err
+	}
+	return m.params, nil
+}
+
+func TestCalculateCorporateTax(t *testing.T) {
+	params := core.CorporateParameters{
+		SmallBusinessRate: 0.09,
+		GeneralRate:       0.15,
+		BusinessLimit:     500000,
+		PassiveThreshold:  50000,
+		GrindRate:         5,
+	}
+
+	tests := []struct {
+		name          string
+		req           core.CorporateRequest
+		params        core.CorporateParameters
+		mockErr       error
+		unconfigured  bool
+		expectedError string
+		expectedTotal float64
+	}{
+		{
+			name:          "Ground business limit",
+			req:           core.CorporateRequest{Year: 2022, ActiveIncome: 600000, PassiveIncome: 90000},
+			params:        params,
+			expectedTotal: 300000*0.09 + 300000*0.15,
+		},
+		{
+			name:          "Negative active income",
+			req:           core.CorporateRequest{Year: 2022, ActiveIncome: -1},
+			params:        params,
+			expectedError: "invalid income",
+		},
+		{
+			name:          "Negative passive income",
+			req:           core.CorporateRequest{Year: 2022, ActiveIncome: 1000, PassiveIncome: -1},
+			params:        params,
+			expectedError: "passive income must be non-negative",
+		},
+		{
+			name:          "Unsupported year",
+			req:           core.CorporateRequest{Year: 2018, ActiveIncome: 1000},
+			params:        params,
+			expectedError: "tax year 2018 is not supported",
+		},
+		{
+			name:          "Invalid parameters",
+			req:           core.CorporateRequest{Year: 2022, ActiveIncome: 1000},
+			params:        core.CorporateParameters{GeneralRate: 1.5},
+			expectedError: "invalid corporate parameters",
+		},
+		{
+			name:          "No parameters for the year",
+			req:           core.CorporateRequest{Year: 2021, ActiveIncome: 1000},
+			mockErr:       fmt.Errorf("%w for 2021", core.ErrNoCorporateParameters),
+			expectedError: "no corporate parameters for 2021",
+		},
+		{
+			name:          "Storage not configured",
+			req:           core.CorporateRequest{Year: 2022, ActiveIncome: 1000},
+			unconfigured:  true,
+			expectedError: "corporate parameters are not configured",
+		},
+		{
+			name:          "Storage error",
+			req:           core.CorporateRequest{Year: 2022, ActiveIncome: 1000},
+			mockErr:       errors.New("storage down"),
+			expectedError: "failed to fetch corporate parameters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []service.Option
+			if !tt.unconfigured {
+				opts = append(opts, service.WithCorporateStorage(&mockCorporateStorage{params: tt.params, err: tt.mockErr}))
+			}
+
+			svc := service.NewTaxService(&mockStorage{}, opts...)
+			result, err := svc.CalculateCorporateTax(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to sample tax, effective and marginal rates over an income range
+func (s *taxService) CalculateCurve(ctx context.Context, req core.CurveRequest) (core.CurveResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
+	if err != nil {
+		return core.CurveResult{}, err
+	}
+
+	if err := s.checkTaxYear(ctx, req.Year); err != nil {
+		return core.CurveResult{}, err
+	}
+
+	if req.From < 0 || req.To < req.From {
+		logger.Log.Error().Msgf("Invalid income range: %.2f-%.2f", req.From, req.To)
+		return core.CurveResult{}, fmt.Errorf("invalid income range")
+	}
+
+	if !req.Breakpoints && req.Step <= 0 {
+		logger.Log.Error().Msgf("Invalid step: %.2f", req.Step)
+		return core.CurveResult{}, fmt.Errorf("step must be positive")
+	}
+
+	year, err := s.taxYear(ctx, req.Jurisdiction, req.Year, nil)
+	if err != nil {
+		return core.CurveResult{}, err
+	}
+
+	result, err := core.ComputeCurve(req, year)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to compute tax curve")
+		return core.CurveResult{}, fmt.Errorf("failed to compute tax curve: %w", err)
+	}
+
+	logger.Log.Info().Msgf("Calculated tax curve with %d points for year: %d", len(result.Points), req.Year)
+
+	return result, nil
+}
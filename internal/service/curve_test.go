@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateCurve(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0.1},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+
+	tests := []struct {
+		name           string
+		req            core.CurveRequest
+		mockErr        error
+		expectedError  string
+		expectedPoints int
+	}{
+		{
+			name:           "Step grid",
+			req:            core.CurveRequest{Year: 2022, From: 0, To: 20000, Step: 5000},
+			expectedPoints: 5,
+		},
+		{
+			name:           "Breakpoints only",
+			req:            core.CurveRequest{Year: 2022, From: 0, To: 20000, Breakpoints: true},
+			expectedPoints: 3,
+		},
+		{
+			name:           "Projected year",
+			req:            core.CurveRequest{Year: 2024, From: 0, To: 20000, Step: 5000},
+			expectedPoints: 5,
+		},
+		{
+			name:          "Unsupported year",
+			req:           core.CurveRequest{Year: 2040, From: 0, To: 20000, Step: 5000},
+			expectedError: "tax year 2040 is not supported",
+		},
+		{
+			name:          "Inverted range",
+			req:           core.CurveRequest{Year: 2022, From: 20000, To: 0, Step: 5000},
+			expectedError: "invalid income range",
+		},
+		{
+			name:          "Missing step",
+			req:           core.CurveRequest{Year: 2022, From: 0, To: 20000},
+			expectedError: "step must be positive",
+		},
+		{
+			name:          "Too many points",
+			req:           core.CurveRequest{Year: 2022, From: 0, To: 1e9, Step: 1},
+			expectedError: "curve has too many points",
+		},
+		{
+			name:          "Error from storage",
+			req:           core.CurveRequest{Year: 2022, From: 0, To: 20000, Step: 5000},
+			mockErr:       errors.New("failed to fetch"),
+			expectedError: "failed to fetch tax brackets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{
+				brackets: brackets,
+				err:      tt.mockErr,
+			}
+
+			svc := service.NewTaxService(mock)
+			result, err := svc.CalculateCurve(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.req.Year, result.Year)
+			assert.Len(t, result.Points, tt.expectedPoints)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to find the pension income split that minimizes a
+// household's combined tax
+func (s *taxService) CalculateHousehold(ctx context.Context, req core.HouseholdRequest) (core.HouseholdResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
+	if err != nil {
+		return core.HouseholdResult{}, err
+	}
+
+	if len(req.Members) != 2 {
+		logger.Log.Error().Msgf("Invalid household size: %d", len(req.Members))
+		return core.HouseholdResult{}, fmt.Errorf("a household has exactly two members")
+	}
+
+	for _, member := range req.Members {
+		if member.Income < 0 {
+			logger.Log.Error().Msgf("Invalid income: %.2f", member.Income)
+			return core.HouseholdResult{}, fmt.Errorf("invalid income")
+		}
+	}
+
+	if req.Splittable < 0 {
+		logger.Log.Error().Msgf("Invalid splittable amount: %.2f", req.Splittable)
+		return core.HouseholdResult{}, fmt.Errorf("splittable amount must be non-negative")
+	}
+
+	year, err := s.taxYear(ctx, req.Jurisdiction, req.Year, nil)
+	if err != nil {
+		return core.HouseholdResult{}, err
+	}
+
+	result, err := core.ComputeHousehold(req, year)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to split household income")
+		return core.HouseholdResult{}, err
+	}
+
+	logger.Log.Info().Msgf("Allocated %.2f of %.2f splittable income, saving: %.2f", result.Allocated, req.Splittable, result.Saving)
+
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateHousehold(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	members := []core.HouseholdMember{{Name: "a", Income: 30000}, {Name: "b", Income: 0}}
+
+	tests := []struct {
+		name              string
+		req               core.HouseholdRequest
+		mockErr           error
+		expectedError     string
+		expectedAllocated float64
+	}{
+		{
+			name:              "Fill the second member's zero band",
+			req:               core.HouseholdRequest{Year: 2022, Members: members, Splittable: 15000},
+			expectedAllocated: 10000,
+		},
+		{
+			name:          "Single member",
+			req:           core.HouseholdRequest{Year: 2022, Members: members[:1], Splittable: 15000},
+			expectedError: "a household has exactly two members",
+		},
+		{
+			name:          "Negative splittable amount",
+			req:           core.HouseholdRequest{Year: 2022, Members: members, Splittable: -1},
+			expectedError: "splittable amount must be non-negative",
+		},
+		{
+			name:          "Negative income",
+			req:           core.HouseholdRequest{Year: 2022, Members: []core.HouseholdMember{{Income: -1}, {Income: 0}}},
+			expectedError: "invalid income",
+		},
+		{
+			name:          "Unsupported year",
+			req:           core.HouseholdRequest{Year: 2018, Members: members, Splittable: 15000},
+			expectedError: "tax year 2018 is not supported",
+		},
+		{
+			name:          "Storage error",
+			req:           core.HouseholdRequest{Year: 2022, Members: members, Splittable: 15000},
+			mockErr:       errors.New("storage down"),
+			expectedError: "failed to fetch tax brackets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{brackets: brackets, err: tt.mockErr}
+
+			svc := service.NewTaxService(mock)
+			result, err := svc.CalculateHousehold(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedAllocated, result.Allocated, 0.0001)
+			assert.Len(t, result.Members, 2)
+		})
+	}
+}
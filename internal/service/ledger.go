@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to build the capital gains ledger of a list of transactions
+func (s *taxService) CalculateLedger(ctx context.Context, req core.LedgerRequest) (core.LedgerResult, error) {
+	if len(req.Transactions) == 0 {
+		logger.Log.Error().Msg("Ledger request without transactions")
+		return core.LedgerResult{}, fmt.Errorf("at least one transaction is required")
+	}
+
+	result, err := core.ComputeLedger(req.Transactions)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to compute ledger")
+		return core.LedgerResult{}, err
+	}
+
+	logger.Log.Info().Msgf("Computed ledger of %d transactions: %d dispositions over %d years", len(req.Transactions), len(result.Dispositions), len(result.Years))
+
+	return result, nil
+}
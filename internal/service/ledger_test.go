@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateLedger(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           core.LedgerRequest
+		expectedError string
+		expectedYears []core.LedgerYear
+	}{
+		{
+			name: "Gains split by year",
+			req: core.LedgerRequest{Transactions: []core.Transaction{
+				{Date: "2021-01-04", Security: "XYZ", Type: core.TransactionBuy, Quantity: 100, Price: 10},
+				{Date: "2021-06-01", Security: "XYZ", Type: core.TransactionSell, Quantity: 50, Price: 20},
+				{Date: "2022-06-01", Security: "XYZ", Type: core.TransactionSell, Quantity: 50, Price: 5},
+			}},
+			expectedYears: []core.LedgerYear{
+				{Year: 2021, Gains: 500, NetGain: 500},
+				{Year: 2022, Losses: 250, NetGain: -250},
+			},
+		},
+		{
+			name:          "No transactions",
+			req:           core.LedgerRequest{},
+			expectedError: "at least one transaction is required",
+		},
+		{
+			name: "Sale without a purchase",
+			req: core.LedgerRequest{Transactions: []core.Transaction{
+				{Date: "2022-06-01", Security: "XYZ", Type: core.TransactionSell, Quantity: 50, Price: 5},
+			}},
+			expectedError: "exceeds the 0 units held",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewTaxService(&mockStorage{})
+			result, err := svc.CalculateLedger(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedYears, result.Years)
+		})
+	}
+}
+
+func TestCalculate_Ledger(t *testing.T) {
+	brackets := []core.TaxBracket{{Min: 0, Max: 0, Rate: 0.1}}
+	transactions := []core.Transaction{
+		{Date: "2022-01-04", Security: "XYZ", Type: core.TransactionBuy, Quantity: 100, Price: 10},
+		{Date: "2022-06-01", Security: "XYZ", Type: core.TransactionSell, Quantity: 100, Price: 30},
+	}
+
+	svc := service.NewTaxService(&mockStorage{brackets: brackets})
+
+	result, err := svc.Calculate(context.Background(), core.TaxRequest{Income: 10000, Year: 2022, CapitalGains: 500, Transactions: transactions})
+	assert.NoError(t, err)
+	assert.InDelta(t, (10000+500+2000)*0.1, result.TotalTax, 0.0001)
+	assert.Equal(t, &core.LedgerYear{Year: 2022, Gains: 2000, NetGain: 2000}, result.Ledger)
+
+	_, err = svc.Calculate(context.Background(), core.TaxRequest{Income: 10000, Year: 2022, Transactions: transactions[1:]})
+	assert.ErrorContains(t, err, "exceeds the 0 units held")
+}
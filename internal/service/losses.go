@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to carry losses across several years
+func (s *taxService) CalculateLosses(ctx context.Context, req core.LossRequest) (core.LossResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
+	if err != nil {
+		return core.LossResult{}, err
+	}
+
+	if len(req.Years) == 0 {
+		logger.Log.Error().Msg("No years to carry losses across")
+		return core.LossResult{}, fmt.Errorf("at least one year is required")
+	}
+
+	seen := make(map[int]bool)
+	years := make([]core.TaxYear, len(req.Years))
+	for i, year := range req.Years {
+		if seen[year.Year] {
+			logger.Log.Error().Msgf("Duplicate year in loss calculation: %d", year.Year)
+			return core.LossResult{}, fmt.Errorf("year %d is listed more than once", year.Year)
+		}
+		seen[year.Year] = true
+
+		if year.Income < 0 || year.CapitalGains < 0 || year.NonCapitalLoss < 0 || year.NetCapitalLoss < 0 {
+			logger.Log.Error().Msgf("Invalid amounts for year %d", year.Year)
+			return core.LossResult{}, fmt.Errorf("amounts for year %d must be non-negative", year.Year)
+		}
+
+		taxYear, err := s.taxYear(ctx, req.Jurisdiction, year.Year, nil)
+		if err != nil {
+			return core.LossResult{}, err
+		}
+		years[i] = taxYear
+	}
+
+	result, err := core.ComputeLosses(req, years, s.carryRules)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to carry losses")
+		return core.LossResult{}, err
+	}
+
+	logger.Log.Info().Msgf("Carried losses across %d years, saving: %.2f", len(req.Years), result.Saving)
+
+	return result, nil
+}
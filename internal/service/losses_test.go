@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateLosses(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+
+	tests := []struct {
+		name           string
+		req            core.LossRequest
+		rules          []core.CarryRule
+		mockErr        error
+		expectedError  string
+		expectedSaving float64
+	}{
+		{
+			name: "Loss carried back",
+			req: core.LossRequest{Years: []core.LossYear{
+				{Year: 2020, Income: 40000},
+				{Year: 2021, NonCapitalLoss: 10000},
+			}},
+			expectedSaving: 2000,
+		},
+		{
+			name: "Carry rule forbids carrying back",
+			req: core.LossRequest{Years: []core.LossYear{
+				{Year: 2020, Income: 40000},
+				{Year: 2021, NonCapitalLoss: 10000},
+			}},
+			rules:          []core.CarryRule{{Year: 2021, NonCapitalForward: -1}},
+			expectedSaving: 0,
+		},
+		{
+			name:          "No years",
+			req:           core.LossRequest{},
+			expectedError: "at least one year is required",
+		},
+		{
+			name: "Duplicate year",
+			req: core.LossRequest{Years: []core.LossYear{
+				{Year: 2020, Income: 40000},
+				{Year: 2020, NonCapitalLoss: 10000},
+			}},
+			expectedError: "year 2020 is listed more than once",
+		},
+		{
+			name:          "Negative loss",
+			req:           core.LossRequest{Years: []core.LossYear{{Year: 2020, NonCapitalLoss: -1}}},
+			expectedError: "must be non-negative",
+		},
+		{
+			name:          "Unsupported year",
+			req:           core.LossRequest{Years: []core.LossYear{{Year: 2018, Income: 40000}}},
+			expectedError: "tax year 2018 is not supported",
+		},
+		{
+			name:          "Storage error",
+			req:           core.LossRequest{Years: []core.LossYear{{Year: 2020, Income: 40000}}},
+			mockErr:       errors.New("storage down"),
+			expectedError: "failed to fetch tax brackets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{brackets: brackets, err: tt.mockErr}
+
+			svc := service.NewTaxService(mock, service.WithCarryRules(tt.rules...))
+			result, err := svc.CalculateLosses(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedSaving, result.Saving, 0.0001)
+		})
+	}
+}
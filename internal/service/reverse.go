@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to find the gross income for a target net income or tax
+func (s *taxService) CalculateGrossIncome(ctx context.Context, req core.ReverseRequest) (core.ReverseResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
+	if err != nil {
+		return core.ReverseResult{}, err
+	}
+
+	if err := s.checkTaxYear(ctx, req.Year); err != nil {
+		return core.ReverseResult{}, err
+	}
+
+	if (req.TargetNet == nil) == (req.TargetTax == nil) {
+		logger.Log.Error().Msg("Exactly one of target net or target tax must be set")
+		return core.ReverseResult{}, fmt.Errorf("exactly one of target net or target tax is required")
+	}
+
+	year, err := s.taxYear(ctx, req.Jurisdiction, req.Year, nil)
+	if err != nil {
+		return core.ReverseResult{}, err
+	}
+
+	result, err := core.ComputeReverse(req, year)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to solve for gross income")
+		return core.ReverseResult{}, fmt.Errorf("failed to solve for gross income: %w", err)
+	}
+
+	logger.Log.Info().Msgf("Calculated gross income: %.2f for year: %d", result.GrossIncome, req.Year)
+
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateGrossIncome(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.25},
+	}
+	f := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name          string
+		req           core.ReverseRequest
+		brackets      []core.TaxBracket
+		mockErr       error
+		expectedError string
+		expectedGross float64
+	}{
+		{
+			name:          "Target net",
+			req:           core.ReverseRequest{Year: 2020, TargetNet: f(40000)},
+			brackets:      brackets,
+			expectedGross: 50000,
+		},
+		{
+			name:          "Target tax",
+			req:           core.ReverseRequest{Year: 2020, TargetTax: f(10000)},
+			brackets:      brackets,
+			expectedGross: 50000,
+		},
+		{
+			name:          "Both targets",
+			req:           core.ReverseRequest{Year: 2020, TargetNet: f(1), TargetTax: f(1)},
+			expectedError: "exactly one of target net or target tax is required",
+		},
+		{
+			name:          "No target",
+			req:           core.ReverseRequest{Year: 2020},
+			expectedError: "exactly one of target net or target tax is required",
+		},
+		{
+			name:          "Unsupported year",
+			req:           core.ReverseRequest{Year: 2040, TargetNet: f(1)},
+			expectedError: "tax year 2040 is not supported",
+		},
+		{
+			name:          "Unreachable target",
+			req:           core.ReverseRequest{Year: 2020, TargetTax: f(10000)},
+			brackets:      []core.TaxBracket{{Min: 0, Max: 10000, Rate: 0.1}},
+			expectedError: "target is not reachable",
+		},
+		{
+			name:          "Error from storage",
+			req:           core.ReverseRequest{Year: 2020, TargetNet: f(1)},
+			mockErr:       errors.New("failed to fetch"),
+			expectedError: "failed to fetch tax brackets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{
+				brackets: tt.brackets,
+				err:      tt.mockErr,
+			}
+
+			svc := service.NewTaxService(mock)
+			result, err := svc.CalculateGrossIncome(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedGross, result.GrossIncome, 0.0001)
+		})
+	}
+}
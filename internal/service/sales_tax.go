@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+	"github.com/haninamaryia/tax-calculator/internal/storage"
+)
+
+// Business logic to calculate the sales tax of an invoice
+func (s *taxService) CalculateSalesTax(ctx context.Context, req core.SalesTaxRequest) (core.SalesTaxResult, error) {
+	if s.salesTax == nil {
+		logger.Log.Error().Msg("Sales tax storage is not configured")
+		return core.SalesTaxResult{}, fmt.Errorf("sales tax rates are not configured")
+	}
+
+	if len(req.Items) == 0 {
+		logger.Log.Error().Msg("Sales tax request without items")
+		return core.SalesTaxResult{}, fmt.Errorf("at least one item is required")
+	}
+
+	rates, err := s.salesTax.FetchSalesTaxRates(ctx, req.Jurisdiction)
+	if errors.Is(err, storage.ErrNotFound) {
+		// A storage that knows nothing of the jurisdiction has no rate for it
+		logger.Log.Warn().Err(err).Msgf("No sales tax rates for %s", req.Jurisdiction)
+		return core.SalesTaxResult{}, fmt.Errorf("%w for %q: %w", core.ErrNoSalesTaxRate, req.Jurisdiction, err)
+	}
+	if err != nil {
+		logger.Log.Error().Err(err).Msgf("Failed to fetch sales tax rates for %s", req.Jurisdiction)
+		return core.SalesTaxResult{}, fmt.Errorf("failed to fetch sales tax rates: %w", err)
+	}
+
+	result, err := core.ComputeSalesTax(req, rates)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to compute sales tax")
+		return core.SalesTaxResult{}, err
+	}
+
+	logger.Log.Info().Msgf("Calculated sales tax: %.2f on %d items for %s on %s", result.Tax, len(req.Items), req.Jurisdiction, req.Date)
+
+	return result, nil
+}
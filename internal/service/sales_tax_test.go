@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/haninamaryia/tax-calculator/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSalesTaxStorage struct {
+	rates []core.SalesTaxRate
+	err   error
+}
+
+func (m *mockSalesTaxStorage) FetchSalesTaxRates(ctx context.Context, jurisdiction string) ([]core.SalesTaxRate, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.rates, nil
+}
+
+func TestCalculateSalesTax(t *testing.T) {
+	rates := []core.SalesTaxRate{{
+		Jurisdiction:  "ON",
+		EffectiveFrom: "2010-07-01",
+		Components:    []core.SalesTaxComponent{{Name: "federal", Rate: 0.05}, {Name: "provincial", Rate: 0.08}},
+	}}
+
+	tests := []struct {
+		name          string
+		req           core.SalesTaxRequest
+		noStorage     bool
+		mockErr       error
+		expectedError string
+		expectedTax   float64
+	}{
+		{
+			name:        "Valid invoice",
+			req:         core.SalesTaxRequest{Jurisdiction: "ON", Date: "2022-05-01", Items: []core.SalesTaxItem{{Amount: 200}}},
+			expectedTax: 26,
+		},
+		{
+			name:          "No items",
+			req:           core.SalesTaxRequest{Jurisdiction: "ON", Date: "2022-05-01"},
+			expectedError: "at least one item is required",
+		},
+		{
+			name:          "Date before any rate",
+			req:           core.SalesTaxRequest{Jurisdiction: "ON", Date: "2009-05-01", Items: []core.SalesTaxItem{{Amount: 200}}},
+			expectedError: "no sales tax rate in effect",
+		},
+		{
+			name:          "Unknown jurisdiction",
+			req:           core.SalesTaxRequest{Jurisdiction: "XX", Date: "2022-05-01", Items: []core.SalesTaxItem{{Amount: 200}}},
+			mockErr:       fmt.Errorf("%w: unexpected response status: 404 Not Found", storage.ErrNotFound),
+			expectedError: `no sales tax rate in effect for "XX"`,
+		},
+		{
+			name:          "Storage error",
+			req:           core.SalesTaxRequest{Jurisdiction: "ON", Date: "2022-05-01", Items: []core.SalesTaxItem{{Amount: 200}}},
+			mockErr:       errors.New("storage down"),
+			expectedError: "failed to fetch sales tax rates",
+		},
+		{
+			name:          "Storage not configured",
+			req:           core.SalesTaxRequest{Jurisdiction: "ON", Date: "2022-05-01", Items: []core.SalesTaxItem{{Amount: 200}}},
+			noStorage:     true,
+			expectedError: "sales tax rates are not configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []service.Option
+			if !tt.noStorage {
+				opts = append(opts, service.WithSalesTaxStorage(&mockSalesTaxStorage{rates: rates, err: tt.mockErr}))
+			}
+
+			svc := service.NewTaxService(&mockStorage{}, opts...)
+			result, err := svc.CalculateSalesTax(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTax, result.Tax, 0.0001)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to compare what-if scenarios with a base profile
+func (s *taxService) CalculateScenarios(ctx context.Context, req core.ScenarioRequest) (core.ScenarioResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
+	if err != nil {
+		return core.ScenarioResult{}, err
+	}
+
+	if len(req.Base) == 0 {
+		logger.Log.Error().Msg("Scenario request without a base profile")
+		return core.ScenarioResult{}, fmt.Errorf("the base profile needs at least one year")
+	}
+
+	if len(req.Scenarios) == 0 || len(req.Scenarios) > core.MaxScenarios {
+		logger.Log.Error().Msgf("Invalid number of scenarios: %d", len(req.Scenarios))
+		return core.ScenarioResult{}, fmt.Errorf("between 1 and %d scenarios are required", core.MaxScenarios)
+	}
+
+	if err := core.ValidateScenarios(req); err != nil {
+		logger.Log.Error().Err(err).Msg("Invalid scenarios")
+		return core.ScenarioResult{}, err
+	}
+
+	base, err := s.scenarioOutcome(ctx, "base", req.Base)
+	if err != nil {
+		return core.ScenarioResult{}, err
+	}
+
+	outcomes := make([]core.ScenarioOutcome, len(req.Scenarios))
+	for i, scenario := range req.Scenarios {
+		profile, err := core.ApplyScenario(req.Base, scenario)
+		if err != nil {
+			return core.ScenarioResult{}, err
+		}
+
+		outcomes[i], err = s.scenarioOutcome(ctx, scenario.Name, profile)
+		if err != nil {
+			return core.ScenarioResult{}, err
+		}
+	}
+
+	result := core.RankScenarios(base, outcomes)
+
+	logger.Log.Info().Msgf("Compared %d scenarios over %d years, best: %s", len(req.Scenarios), len(req.Base), result.Scenarios[0].Name)
+
+	return result, nil
+}
+
+// scenarioOutcome runs every year of a profile through the calculator
+func (s *taxService) scenarioOutcome(ctx context.Context, name string, profile []core.TaxRequest) (core.ScenarioOutcome, error) {
+	results := make([]core.TaxResult, len(profile))
+	for i, req := range profile {
+		result, err := s.Calculate(ctx, req)
+		if err != nil {
+			logger.Log.Error().Err(err).Msgf("Failed to calculate scenario %q for year %d", name, req.Year)
+			return core.ScenarioOutcome{}, fmt.Errorf("scenario %q, year %d: %w", name, req.Year, err)
+		}
+		results[i] = result
+	}
+	return core.NewScenarioOutcome(name, profile, results), nil
+}
@@ -0,0 +1,101 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateScenarios(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 50000, Rate: 0.1},
+		{Min: 50000, Max: 0, Rate: 0.3},
+	}
+	base := []core.TaxRequest{{Income: 40000, Year: 2021}, {Income: 60000, Year: 2022}}
+	bonus := func(name string, year int) core.Scenario {
+		return core.Scenario{Name: name, Modifications: []core.Modification{
+			{Year: year, Field: "income", Operation: core.OperationAdd, Value: 10000},
+		}}
+	}
+
+	tests := []struct {
+		name          string
+		req           core.ScenarioRequest
+		mockErr       error
+		expectedError string
+		expectedNames []string
+		expectedDiffs []float64
+	}{
+		{
+			name:          "Bonus this year or next",
+			req:           core.ScenarioRequest{Base: base, Scenarios: []core.Scenario{bonus("next year", 2022), bonus("this year", 2021)}},
+			expectedNames: []string{"this year", "next year"},
+			expectedDiffs: []float64{1000, 3000},
+		},
+		{
+			name:          "No base profile",
+			req:           core.ScenarioRequest{Scenarios: []core.Scenario{bonus("this year", 2021)}},
+			expectedError: "the base profile needs at least one year",
+		},
+		{
+			name:          "No scenarios",
+			req:           core.ScenarioRequest{Base: base},
+			expectedError: "between 1 and 20 scenarios are required",
+		},
+		{
+			name:          "Duplicate base year",
+			req:           core.ScenarioRequest{Base: []core.TaxRequest{base[0], base[0]}, Scenarios: []core.Scenario{bonus("this year", 2021)}},
+			expectedError: "year 2021 is listed more than once",
+		},
+		{
+			name:          "Duplicate scenario name",
+			req:           core.ScenarioRequest{Base: base, Scenarios: []core.Scenario{bonus("bonus", 2021), bonus("bonus", 2022)}},
+			expectedError: `scenario "bonus" is listed more than once`,
+		},
+		{
+			name:          "Invalid modification",
+			req:           core.ScenarioRequest{Base: base, Scenarios: []core.Scenario{bonus("earlier", 2019)}},
+			expectedError: "year 2019 is not in the base profile",
+		},
+		{
+			name:          "Unsupported year",
+			req:           core.ScenarioRequest{Base: []core.TaxRequest{{Income: 1, Year: 2018}}, Scenarios: []core.Scenario{bonus("this year", 2018)}},
+			expectedError: "tax year 2018 is not supported",
+		},
+		{
+			name:          "Storage error",
+			req:           core.ScenarioRequest{Base: base, Scenarios: []core.Scenario{bonus("this year", 2021)}},
+			mockErr:       errors.New("storage down"),
+			expectedError: "failed to fetch tax brackets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{brackets: brackets, err: tt.mockErr}
+
+			svc := service.NewTaxService(mock)
+			result, err := svc.CalculateScenarios(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, mock.latestCalls)
+			assert.InDelta(t, 4000+8000, result.Base.TotalTax, 0.0001)
+			assert.Len(t, result.Scenarios, len(tt.expectedNames))
+			for i, name := range tt.expectedNames {
+				assert.Equal(t, name, result.Scenarios[i].Name)
+				assert.Equal(t, i+1, result.Scenarios[i].Rank)
+				assert.InDelta(t, tt.expectedDiffs[i], result.Scenarios[i].Difference.TotalTax, 0.0001)
+			}
+		})
+	}
+}
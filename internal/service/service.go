@@ -4,38 +4,128 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/haninamaryia/tax-calculator/internal/core"
 	"github.com/haninamaryia/tax-calculator/internal/logger"
 	"github.com/haninamaryia/tax-calculator/internal/storage"
 )
 
-const SupportedYears = "2019, 2020, 2021, 2022"
+// MaxProjectionYears is how many years after the latest year published
+// upstream schedules are projected for
+const MaxProjectionYears = 10
 
 // Interface for the tax calculator service
 type TaxService interface {
 	CalculateTax(ctx context.Context, incomeStr string, yearStr string) (core.TaxResult, error)
-	ValidateTaxYear(year string) error
+	Calculate(ctx context.Context, req core.TaxRequest) (core.TaxResult, error)
+	ValidateTaxYear(ctx context.Context, year string) error
+	CalculateWithholding(ctx context.Context, req core.WithholdingRequest) (core.WithholdingResult, error)
+	CalculateGrossIncome(ctx context.Context, req core.ReverseRequest) (core.ReverseResult, error)
+	CalculateCurve(ctx context.Context, req core.CurveRequest) (core.CurveResult, error)
+	CompareYears(ctx context.Context, req core.CompareRequest) (core.CompareResult, error)
+	CalculateLosses(ctx context.Context, req core.LossRequest) (core.LossResult, error)
+	CalculateAveraging(ctx context.Context, req core.AveragingRequest) (core.AveragingResult, error)
+	CalculateContribution(ctx context.Context, req core.ContributionRequest) (core.ContributionResult, error)
+	CalculateHousehold(ctx context.Context, req core.HouseholdRequest) (core.HouseholdResult, error)
+	CalculateCorporateTax(ctx context.Context, req core.CorporateRequest) (core.CorporateResult, error)
+	CalculateSalesTax(ctx context.Context, req core.SalesTaxRequest) (core.SalesTaxResult, error)
+	CalculateLedger(ctx context.Context, req core.LedgerRequest) (core.LedgerResult, error)
+	CalculateScenarios(ctx context.Context, req core.ScenarioRequest) (core.ScenarioResult, error)
+	CalculateSimulation(ctx context.Context, req core.SimulationRequest) (core.SimulationResult, error)
 }
 
 // Struct implementing the interface
 type taxService struct {
-	storage storage.TaxStorage
+	storage         storage.TaxStorage
+	ruleSets        []core.RuleSet
+	prescribedRates []core.PrescribedRate
+	carryRules      []core.CarryRule
+	indexation      core.Indexation
+	salesTax        storage.SalesTaxStorage
+	corporate       storage.CorporateStorage
+	rounding        []core.RoundingPolicy
+}
+
+// Option configures the tax service
+type Option func(*taxService)
+
+// WithRuleSets sets the rule pipelines used per jurisdiction and year.
+// Years without a rule set only apply the progressive brackets.
+func WithRuleSets(sets ...core.RuleSet) Option {
+	return func(s *taxService) {
+		s.ruleSets = append(s.ruleSets, sets...)
+	}
+}
+
+// WithPrescribedRates sets the interest rates charged on late balances
+func WithPrescribedRates(rates ...core.PrescribedRate) Option {
+	return func(s *taxService) {
+		s.prescribedRates = append(s.prescribedRates, rates...)
+	}
+}
+
+// WithCarryRules sets how far losses of each year carry back and forward.
+// Years without a rule use core.DefaultCarryRule.
+func WithCarryRules(rules ...core.CarryRule) Option {
+	return func(s *taxService) {
+		s.carryRules = append(s.carryRules, rules...)
+	}
+}
+
+// WithIndexation sets how the schedules of years after the latest published
+// year are projected
+func WithIndexation(ix core.Indexation) Option {
+	return func(s *taxService) {
+		s.indexation = ix
+	}
+}
+
+// WithSalesTaxStorage sets where sales tax rates are loaded from. Without
+// it sales tax calculations fail.
+func WithSalesTaxStorage(st storage.SalesTaxStorage) Option {
+	return func(s *taxService) {
+		s.salesTax = st
+	}
+}
+
+// WithCorporateStorage sets where corporate tax parameters are loaded from.
+// Without it corporate calculations fail.
+func WithCorporateStorage(cs storage.CorporateStorage) Option {
+	return func(s *taxService) {
+		s.corporate = cs
+	}
+}
+
+// WithRoundingPolicies sets how the tax of each jurisdiction and year is
+// rounded. Years without a policy are not rounded.
+func WithRoundingPolicies(policies ...core.RoundingPolicy) Option {
+	return func(s *taxService) {
+		s.rounding = append(s.rounding, policies...)
+	}
 }
 
 // Constructor
-func NewTaxService(s storage.TaxStorage) TaxService {
-	return &taxService{
+func NewTaxService(s storage.TaxStorage, opts ...Option) TaxService {
+	svc := &taxService{
 		storage: s,
 	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc
 }
 
 // Business logic to calculate tax
 func (s *taxService) CalculateTax(ctx context.Context, incomeStr string, yearStr string) (core.TaxResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
+	if err != nil {
+		return core.TaxResult{}, err
+	}
 
 	// TODO: refactor this to be less redundant
 	// Validate the year first
-	if err := s.ValidateTaxYear(yearStr); err != nil {
+	if err := s.ValidateTaxYear(ctx, yearStr); err != nil {
 		logger.Log.Error().Err(err).Msgf("Invalid tax year: %s", yearStr)
 		return core.TaxResult{}, err
 	}
@@ -52,56 +142,270 @@ func (s *taxService) CalculateTax(ctx context.Context, incomeStr string, yearStr
 		return core.TaxResult{}, fmt.Errorf("invalid income")
 	}
 
-	// Fetch tax brackets from storage
-	brackets, err := s.storage.FetchTaxBrackets(ctx, year)
+	return s.Calculate(ctx, core.TaxRequest{Income: income, Year: year})
+}
+
+// Business logic to calculate tax from a full request. Without an as-of date
+// every schedule in effect during the year is blended by the days it applies.
+func (s *taxService) Calculate(ctx context.Context, req core.TaxRequest) (core.TaxResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
 	if err != nil {
-		logger.Log.Error().Err(err).Msg("Failed to fetch tax brackets")
-		return core.TaxResult{}, fmt.Errorf("failed to fetch tax brackets: %w", err)
+		return core.TaxResult{}, err
 	}
 
-	perBand := make(map[string]float64)
-	var totalTax float64
+	if err := s.checkTaxYear(ctx, req.Year); err != nil {
+		return core.TaxResult{}, err
+	}
+
+	if req.InflationRate != nil && (*req.InflationRate <= -1 || *req.InflationRate > 1) {
+		logger.Log.Error().Msgf("Invalid inflation rate: %v", *req.InflationRate)
+		return core.TaxResult{}, fmt.Errorf("inflation rate must be above -1 and at most 1")
+	}
+
+	if req.Income < 0 {
+		logger.Log.Error().Msgf("Invalid income: %.2f", req.Income)
+		return core.TaxResult{}, fmt.Errorf("invalid income")
+	}
+
+	if req.CapitalGains < 0 || req.AMTCarryForward < 0 {
+		logger.Log.Error().Msgf("Invalid capital gains %.2f or AMT carry-forward %.2f", req.CapitalGains, req.AMTCarryForward)
+		return core.TaxResult{}, fmt.Errorf("capital gains and AMT carry-forward must be non-negative")
+	}
+
+	if req.BusinessIncome < 0 {
+		logger.Log.Error().Msgf("Invalid business income: %.2f", req.BusinessIncome)
+		return core.TaxResult{}, fmt.Errorf("business income must be non-negative")
+	}
+
+	if req.Dividends < 0 {
+		logger.Log.Error().Msgf("Invalid dividends: %.2f", req.Dividends)
+		return core.TaxResult{}, fmt.Errorf("dividends must be non-negative")
+	}
+
+	if req.Withheld < 0 || req.Instalments < 0 || req.RefundableCredits < 0 {
+		logger.Log.Error().Msgf("Invalid payments: withheld %.2f, instalments %.2f, refundable credits %.2f", req.Withheld, req.Instalments, req.RefundableCredits)
+		return core.TaxResult{}, fmt.Errorf("withheld, instalments and refundable credits must be non-negative")
+	}
 
-	// TODO: put this in function and create unit test
-	// Calculate tax for each bracket
-	for _, b := range brackets {
-		if income <= b.Min {
-			break
+	if req.PaymentDate != "" {
+		if _, err := time.Parse(core.DateFormat, req.PaymentDate); err != nil {
+			logger.Log.Error().Err(err).Msgf("Invalid payment date: %s", req.PaymentDate)
+			return core.TaxResult{}, fmt.Errorf("invalid payment date, expected format %s", core.DateFormat)
 		}
+	}
 
-		upper := b.Max
-		if upper == 0 || income < upper {
-			upper = income
+	if req.AsOf != "" {
+		date, err := time.Parse(core.DateFormat, req.AsOf)
+		if err != nil {
+			logger.Log.Error().Err(err).Msgf("Invalid as-of date: %s", req.AsOf)
+			return core.TaxResult{}, fmt.Errorf("invalid as-of date, expected format %s", core.DateFormat)
+		}
+		if date.Year() != req.Year {
+			logger.Log.Error().Msgf("As-of date %s is outside tax year %d", req.AsOf, req.Year)
+			return core.TaxResult{}, fmt.Errorf("as-of date %s is outside tax year %d", req.AsOf, req.Year)
 		}
+	}
+
+	if _, err := core.ComputeResidency(req); err != nil {
+		logger.Log.Error().Err(err).Msgf("Invalid residency period %q to %q", req.ResidencyStart, req.ResidencyEnd)
+		return core.TaxResult{}, err
+	}
+
+	// Fetch tax schedules from storage, or project them for future years
+	year, err := s.taxYear(ctx, req.Jurisdiction, req.Year, req.InflationRate)
+	if err != nil {
+		return core.TaxResult{}, err
+	}
 
-		taxable := upper - b.Min
-		if taxable < 0 {
-			taxable = 0
+	// Business income without a contribution rule would silently skip the
+	// self-employment contribution
+	rulesYear := s.rulesYear(req.Jurisdiction, req.Year, year.Projection)
+	if req.BusinessIncome > 0 && !s.hasRule(req.Jurisdiction, rulesYear, core.RuleSelfEmployment) {
+		logger.Log.Error().Msgf("No self-employment rule for jurisdiction %q, year %d", req.Jurisdiction, req.Year)
+		return core.TaxResult{}, fmt.Errorf("no self-employment rule configured for jurisdiction %q in %d", req.Jurisdiction, req.Year)
+	}
+
+	result, err := core.Calculate(req, year.Schedules, year.Pipeline, s.prescribedRates)
+	if err != nil {
+		logger.Log.Error().Err(err).Msgf("Failed to calculate tax for year %d", req.Year)
+		return core.TaxResult{}, err
+	}
+
+	if year.Projection != nil {
+		result.Projected = true
+		result.Projection = year.Projection
+	}
+
+	logger.Log.Info().Msgf("Calculated tax: %.2f for income: %.2f, year: %d", result.TotalTax, req.Income, req.Year)
+
+	return result, nil
+}
+
+// taxYear fetches the schedules and builds the pipeline of one year. Years
+// after the latest published year are projected from it, with the rate
+// override if any, and carry the projection.
+func (s *taxService) taxYear(ctx context.Context, jurisdiction string, year int, override *float64) (core.TaxYear, error) {
+	if err := s.checkTaxYear(ctx, year); err != nil {
+		return core.TaxYear{}, err
+	}
+
+	schedules, projection, err := s.schedules(ctx, year, override)
+	if err != nil {
+		return core.TaxYear{}, err
+	}
+
+	pipeline, err := s.pipeline(jurisdiction, year, projection)
+	if err != nil {
+		logger.Log.Error().Err(err).Msgf("Invalid rules for jurisdiction %q, year %d", jurisdiction, year)
+		return core.TaxYear{}, err
+	}
+
+	return core.TaxYear{Year: year, Schedules: schedules, Pipeline: pipeline, Projection: projection}, nil
+}
+
+// schedules fetches the schedules of the year from storage. Years after the
+// latest published year are projected from it, with the rate override if
+// any.
+func (s *taxService) schedules(ctx context.Context, year int, override *float64) ([]core.TaxSchedule, *core.Projection, error) {
+	latest, err := s.latestTaxYear(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if year <= latest {
+		schedules, err := s.storage.FetchTaxSchedules(ctx, year)
+		if err != nil {
+			logger.Log.Error().Err(err).Msgf("Failed to fetch tax schedules for year %d", year)
+			return nil, nil, fmt.Errorf("failed to fetch tax brackets: %w", err)
 		}
+		return schedules, nil, nil
+	}
 
-		tax := taxable * b.Rate
-		totalTax += tax
-		perBand[fmt.Sprintf("%.2f-%.2f", b.Min, upper)] = tax
+	base, err := s.storage.FetchTaxSchedules(ctx, latest)
+	if err != nil {
+		logger.Log.Error().Err(err).Msgf("Failed to fetch tax schedules for year %d", latest)
+		return nil, nil, fmt.Errorf("failed to fetch tax brackets: %w", err)
 	}
 
-	effectiveRate := 0.0
-	if income > 0 {
-		effectiveRate = totalTax / income
+	schedules, projection, err := core.ProjectSchedules(base, latest, year, s.indexation, override)
+	if err != nil {
+		logger.Log.Error().Err(err).Msgf("Failed to project tax schedules for year %d", year)
+		return nil, nil, err
 	}
 
-	logger.Log.Info().Msgf("Calculated tax: %.2f for income: %.2f, year: %s", totalTax, income, yearStr)
+	logger.Log.Info().Msgf("Projected tax schedules for year %d from %d, factor: %.4f", year, latest, projection.Factor)
+	return schedules, &projection, nil
+}
+
+// latestTaxYearKey carries the latest published year in the context of a
+// request that has already resolved it
+type latestTaxYearKey struct{}
 
-	return core.TaxResult{
-		TotalTax:      totalTax,
-		PerBracket:    perBand,
-		EffectiveRate: effectiveRate,
-	}, nil
+// withLatestTaxYear resolves the latest published year once for a request.
+// The returned context carries it, so every year the request checks,
+// fetches or projects is measured against the same one.
+func (s *taxService) withLatestTaxYear(ctx context.Context) (context.Context, error) {
+	latest, err := s.latestTaxYear(ctx)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, latestTaxYearKey{}, latest), nil
 }
 
-func (s *taxService) ValidateTaxYear(year string) error {
-	supportedYears := map[string]bool{"2019": true, "2020": true, "2021": true, "2022": true}
+// latestTaxYear is the latest year published upstream, as resolved for the
+// request if it was
+func (s *taxService) latestTaxYear(ctx context.Context) (int, error) {
+	if latest, ok := ctx.Value(latestTaxYearKey{}).(int); ok {
+		return latest, nil
+	}
+	latest, err := s.storage.FetchLatestTaxYear(ctx)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to fetch the latest tax year")
+		return 0, fmt.Errorf("failed to fetch the latest tax year: %w", err)
+	}
+	return latest, nil
+}
+
+// checkTaxYear accepts the published years and the years up to
+// MaxProjectionYears after the latest of them
+func (s *taxService) checkTaxYear(ctx context.Context, year int) error {
+	latest, err := s.latestTaxYear(ctx)
+	if err != nil {
+		return err
+	}
+	if year > latest && year <= latest+MaxProjectionYears {
+		return nil
+	}
+	if err := validateTaxYear(strconv.Itoa(year), latest); err != nil {
+		logger.Log.Error().Err(err).Msgf("Invalid tax year: %d", year)
+		return err
+	}
+	return nil
+}
+
+// rulesYear is the year whose rule set applies. Projected years without
+// their own rule set keep the rules of the year they are projected from.
+func (s *taxService) rulesYear(jurisdiction string, year int, projection *core.Projection) int {
+	if projection == nil {
+		return year
+	}
+	if _, ok := core.FindRuleSet(s.ruleSets, jurisdiction, year); ok {
+		return year
+	}
+	return projection.BaseYear
+}
+
+// hasRule reports whether the rules of the jurisdiction and year include a
+// rule of the type
+func (s *taxService) hasRule(jurisdiction string, year int, ruleType string) bool {
+	specs, _ := core.FindRuleSet(s.ruleSets, jurisdiction, year)
+	for _, spec := range specs {
+		if spec.Type == ruleType {
+			return true
+		}
+	}
+	return false
+}
+
+// pipeline builds the rules configured for the jurisdiction and year. A
+// projected year without rules of its own uses those of the year it is
+// projected from, with their thresholds and amounts indexed like the
+// brackets.
+func (s *taxService) pipeline(jurisdiction string, taxYear int, projection *core.Projection) (core.Pipeline, error) {
+	year := s.rulesYear(jurisdiction, taxYear, projection)
+	pipeline := core.DefaultPipeline()
+	specs, ok := core.FindRuleSet(s.ruleSets, jurisdiction, year)
+	if ok && year != taxYear {
+		specs = core.ProjectRules(specs, *projection)
+	}
+	if ok {
+		var err error
+		if pipeline, err = core.NewPipeline(specs); err != nil {
+			return core.Pipeline{}, err
+		}
+	} else if jurisdiction != "" {
+		return core.Pipeline{}, fmt.Errorf("no rules configured for jurisdiction %q in %d", jurisdiction, year)
+	}
+
+	if policy, ok := core.FindRoundingPolicy(s.rounding, jurisdiction, year); ok {
+		pipeline = pipeline.WithRounding(policy)
+	}
+	return pipeline, nil
+}
+
+// ValidateTaxYear accepts the years published upstream, from
+// storage.FirstTaxYear to the latest one
+func (s *taxService) ValidateTaxYear(ctx context.Context, year string) error {
+	latest, err := s.latestTaxYear(ctx)
+	if err != nil {
+		return err
+	}
+	return validateTaxYear(year, latest)
+}
 
-	if !supportedYears[year] {
+func validateTaxYear(year string, latest int) error {
+	y, err := strconv.Atoi(year)
+	if err != nil || y < storage.FirstTaxYear || y > latest {
 		logger.Log.Warn().Msgf("Unsupported tax year: %s", year)
 		return fmt.Errorf("tax year %s is not supported", year)
 	}
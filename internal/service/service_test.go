@@ -12,10 +12,18 @@ import (
 
 // Mock storage
 type mockStorage struct {
-	brackets []core.TaxBracket
-	err      error
+	brackets  []core.TaxBracket
+	schedules []core.TaxSchedule
+	latest    int
+	err       error
+
+	// latestCalls counts the lookups of the latest tax year
+	latestCalls int
 }
 
+// latestTaxYear is the latest year the mock publishes unless it sets its own
+const latestTaxYear = 2022
+
 func (m *mockStorage) FetchTaxBrackets(ctx context.Context, year int) ([]core.TaxBracket, error) {
 	if m.err != nil {
 		return nil, m.err
@@ -23,6 +31,24 @@ func (m *mockStorage) FetchTaxBrackets(ctx context.Context, year int) ([]core.Ta
 	return m.brackets, nil
 }
 
+func (m *mockStorage) FetchTaxSchedules(ctx context.Context, year int) ([]core.TaxSchedule, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.schedules != nil {
+		return m.schedules, nil
+	}
+	return []core.TaxSchedule{core.WholeYearSchedule(year, m.brackets)}, nil
+}
+
+func (m *mockStorage) FetchLatestTaxYear(ctx context.Context) (int, error) {
+	m.latestCalls++
+	if m.latest != 0 {
+		return m.latest, nil
+	}
+	return latestTaxYear, nil
+}
+
 func TestCalculateTax(t *testing.T) {
 
 	tests := []struct {
@@ -156,7 +182,7 @@ func TestValidateTaxYear(t *testing.T) {
 			mock := &mockStorage{}
 
 			svc := service.NewTaxService(mock)
-			err := svc.ValidateTaxYear(tt.year)
+			err := svc.ValidateTaxYear(context.Background(), tt.year)
 
 			if tt.expectedError != "" {
 				assert.Error(t, err)
@@ -167,3 +193,471 @@ func TestValidateTaxYear(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculate(t *testing.T) {
+	schedules := []core.TaxSchedule{
+		{EffectiveFrom: "2022-01-01", EffectiveTo: "2022-07-01", Brackets: []core.TaxBracket{{Min: 0, Max: 0, Rate: 0.1}}},
+		{EffectiveFrom: "2022-07-02", EffectiveTo: "2022-12-31", Brackets: []core.TaxBracket{{Min: 0, Max: 0, Rate: 0.2}}},
+	}
+
+	tests := []struct {
+		name            string
+		req             core.TaxRequest
+		schedules       []core.TaxSchedule
+		mockErr         error
+		expectedError   string
+		expectedTotal   float64
+		expectedPeriods int
+		expectTrace     bool
+	}{
+		{
+			name:            "Blended over the year",
+			req:             core.TaxRequest{Income: 36500, Year: 2022},
+			schedules:       schedules,
+			expectedTotal:   3650*182/365.0 + 7300*183/365.0,
+			expectedPeriods: 2,
+		},
+		{
+			name:            "Explain returns the trace",
+			req:             core.TaxRequest{Income: 36500, Year: 2022, Explain: true},
+			schedules:       schedules,
+			expectedTotal:   3650*182/365.0 + 7300*183/365.0,
+			expectedPeriods: 2,
+			expectTrace:     true,
+		},
+		{
+			name:          "As of a date in the first schedule",
+			req:           core.TaxRequest{Income: 36500, Year: 2022, AsOf: "2022-03-15"},
+			schedules:     schedules,
+			expectedTotal: 3650,
+		},
+		{
+			name:          "As of a date in the second schedule",
+			req:           core.TaxRequest{Income: 36500, Year: 2022, AsOf: "2022-07-02"},
+			schedules:     schedules,
+			expectedTotal: 7300,
+		},
+		{
+			name:          "As-of date in another year",
+			req:           core.TaxRequest{Income: 36500, Year: 2022, AsOf: "2021-03-15"},
+			schedules:     schedules,
+			expectedError: "outside tax year 2022",
+		},
+		{
+			name:          "Malformed as-of date",
+			req:           core.TaxRequest{Income: 36500, Year: 2022, AsOf: "15/03/2022"},
+			schedules:     schedules,
+			expectedError: "invalid as-of date",
+		},
+		{
+			name:          "Residency ends before it starts",
+			req:           core.TaxRequest{Income: 36500, Year: 2022, ResidencyStart: "2022-07-01", ResidencyEnd: "2022-03-01"},
+			schedules:     schedules,
+			expectedError: "residency ends before it starts",
+		},
+		{
+			name:          "Schedules leave a gap",
+			req:           core.TaxRequest{Income: 36500, Year: 2022},
+			schedules:     schedules[:1],
+			expectedError: "no tax schedule in effect on 2022-07-02",
+		},
+		{
+			name:          "Negative income",
+			req:           core.TaxRequest{Income: -1, Year: 2022},
+			expectedError: "invalid income",
+		},
+		{
+			name:          "Negative capital gains",
+			req:           core.TaxRequest{Income: 36500, Year: 2022, CapitalGains: -1},
+			expectedError: "capital gains and AMT carry-forward must be non-negative",
+		},
+		{
+			name:          "Unsupported year",
+			req:           core.TaxRequest{Income: 36500, Year: 2018},
+			expectedError: "tax year 2018 is not supported",
+		},
+		{
+			name:          "Error from storage",
+			req:           core.TaxRequest{Income: 36500, Year: 2022},
+			mockErr:       errors.New("failed to fetch"),
+			expectedError: "failed to fetch tax brackets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{
+				schedules: tt.schedules,
+				err:       tt.mockErr,
+			}
+
+			svc := service.NewTaxService(mock)
+			result, err := svc.Calculate(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+			assert.Len(t, result.Periods, tt.expectedPeriods)
+			assert.Equal(t, tt.expectTrace, len(result.Trace) > 0)
+		})
+	}
+}
+
+func TestCalculate_RuleSets(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	sets := []core.RuleSet{
+		{
+			Jurisdiction: "ON",
+			Year:         2022,
+			Rules: []core.RuleSpec{
+				{Type: core.RuleCredit, Name: "basic", Rate: 0.1, Amount: 5000},
+				{Type: core.RuleBrackets},
+				{Type: core.RuleSurtax, Rate: 0.5, Threshold: 4000},
+			},
+		},
+		{
+			Jurisdiction: "AMT",
+			Year:         2022,
+			Rules: []core.RuleSpec{
+				{Type: core.RuleCapitalGains, Rate: 0.5},
+				{Type: core.RuleBrackets},
+				{Type: core.RuleAMT, Rate: 0.15, Threshold: 40000},
+			},
+		},
+		{
+			Jurisdiction: "SE",
+			Year:         2022,
+			Rules: []core.RuleSpec{
+				{Type: core.RuleSelfEmployment, Rate: 0.1, Threshold: 2000},
+				{Type: core.RuleBrackets},
+			},
+		},
+		{
+			Jurisdiction: "BAD",
+			Year:         2022,
+			Rules:        []core.RuleSpec{{Type: "unknown"}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		req           core.TaxRequest
+		expectedError string
+		expectedTotal float64
+		expectedLines int
+	}{
+		{
+			name:          "Default jurisdiction applies brackets only",
+			req:           core.TaxRequest{Income: 40000, Year: 2022},
+			expectedTotal: 6000,
+			expectedLines: 1,
+		},
+		{
+			name:          "Configured jurisdiction runs its pipeline in phase order",
+			req:           core.TaxRequest{Income: 40000, Year: 2022, Jurisdiction: "ON"},
+			expectedTotal: 6000 + 1000 - 500,
+			expectedLines: 3,
+		},
+		{
+			name:          "AMT applies to large capital gains",
+			req:           core.TaxRequest{Income: 30000, Year: 2022, Jurisdiction: "AMT", CapitalGains: 200000},
+			expectedTotal: 28500,
+			expectedLines: 2,
+		},
+		{
+			name:          "Self-employment contribution and half deducted",
+			req:           core.TaxRequest{Year: 2022, Jurisdiction: "SE", BusinessIncome: 42000},
+			expectedTotal: 4000 + (40000-10000)*0.2,
+			expectedLines: 2,
+		},
+		{
+			name:          "Business income without a self-employment rule",
+			req:           core.TaxRequest{Year: 2022, Jurisdiction: "ON", BusinessIncome: 42000},
+			expectedError: `no self-employment rule configured for jurisdiction "ON" in 2022`,
+		},
+		{
+			name:          "Jurisdiction without rules for the year",
+			req:           core.TaxRequest{Income: 40000, Year: 2021, Jurisdiction: "ON"},
+			expectedError: `no rules configured for jurisdiction "ON" in 2021`,
+		},
+		{
+			name:          "Invalid rule configuration",
+			req:           core.TaxRequest{Income: 40000, Year: 2022, Jurisdiction: "BAD"},
+			expectedError: `unknown rule type "unknown"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{brackets: brackets}
+
+			svc := service.NewTaxService(mock, service.WithRuleSets(sets...))
+			result, err := svc.Calculate(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+			assert.Len(t, result.Lines, tt.expectedLines)
+		})
+	}
+}
+
+func TestCalculate_Rounding(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 1002, Rate: 0.15},
+		{Min: 1002, Max: 0, Rate: 0.25},
+	}
+	sets := []core.RuleSet{{Jurisdiction: "ON", Year: 2022, Rules: []core.RuleSpec{{Type: core.RuleBrackets}}}}
+	policies := []core.RoundingPolicy{
+		{Jurisdiction: "", Year: 2022, Step: core.RoundTotal, Method: core.RoundTruncate, Increment: 1},
+		{Jurisdiction: "ON", Year: 2022, Step: core.RoundBracket, Increment: 1},
+	}
+
+	tests := []struct {
+		name          string
+		req           core.TaxRequest
+		expectedTotal float64
+	}{
+		{
+			name:          "Default jurisdiction truncates the total",
+			req:           core.TaxRequest{Income: 2003.6, Year: 2022},
+			expectedTotal: 400,
+		},
+		{
+			name:          "Configured jurisdiction rounds each bracket",
+			req:           core.TaxRequest{Income: 2007.6, Year: 2022, Jurisdiction: "ON"},
+			expectedTotal: 150 + 251,
+		},
+		{
+			name:          "Year without a policy is not rounded",
+			req:           core.TaxRequest{Income: 2003.6, Year: 2021},
+			expectedTotal: 400.7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{brackets: brackets}
+
+			svc := service.NewTaxService(mock, service.WithRuleSets(sets...), service.WithRoundingPolicies(policies...))
+			result, err := svc.Calculate(context.Background(), tt.req)
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 1e-9)
+		})
+	}
+}
+
+func TestCalculate_Balance(t *testing.T) {
+	brackets := []core.TaxBracket{{Min: 0, Max: 0, Rate: 0.1}}
+	rates := []core.PrescribedRate{{EffectiveFrom: "2023-01-01", Rate: 0.1}}
+
+	tests := []struct {
+		name           string
+		req            core.TaxRequest
+		expectedError  string
+		expectedOwing  float64
+		expectedRefund float64
+		expectInterest bool
+	}{
+		{
+			name:          "Balance owing after withholdings and instalments",
+			req:           core.TaxRequest{Income: 50000, Year: 2022, Withheld: 3000, Instalments: 1000},
+			expectedOwing: 1000,
+		},
+		{
+			name:           "Refund with refundable credits",
+			req:            core.TaxRequest{Income: 50000, Year: 2022, Withheld: 5000, RefundableCredits: 250},
+			expectedRefund: 250,
+		},
+		{
+			name:           "Interest on a late payment",
+			req:            core.TaxRequest{Income: 50000, Year: 2022, Withheld: 4000, PaymentDate: "2023-06-30"},
+			expectedOwing:  1000,
+			expectInterest: true,
+		},
+		{
+			name:          "Negative withholding",
+			req:           core.TaxRequest{Income: 50000, Year: 2022, Withheld: -1},
+			expectedError: "must be non-negative",
+		},
+		{
+			name:          "Malformed payment date",
+			req:           core.TaxRequest{Income: 50000, Year: 2022, PaymentDate: "30/06/2023"},
+			expectedError: "invalid payment date",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{brackets: brackets}
+
+			svc := service.NewTaxService(mock, service.WithPrescribedRates(rates...))
+			result, err := svc.Calculate(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, result.Balance)
+			assert.InDelta(t, tt.expectedOwing, result.Balance.BalanceOwing, 0.0001)
+			assert.InDelta(t, tt.expectedRefund, result.Balance.Refund, 0.0001)
+			if tt.expectInterest {
+				assert.NotNil(t, result.Balance.Interest)
+				assert.Equal(t, 61, result.Balance.Interest.Days)
+				assert.Greater(t, result.Balance.Interest.Amount, 0.0)
+			} else {
+				assert.Nil(t, result.Balance.Interest)
+			}
+		})
+	}
+}
+
+func TestCalculate_Projection(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	override := 0.0
+
+	tests := []struct {
+		name           string
+		req            core.TaxRequest
+		expectedError  string
+		expectedTotal  float64
+		expectedFactor float64
+		projected      bool
+		latest         int
+	}{
+		{
+			name:          "Published year is not projected",
+			req:           core.TaxRequest{Income: 22100, Year: 2022},
+			expectedTotal: 2420,
+		},
+		{
+			name:           "Thresholds indexed for each projected year",
+			req:            core.TaxRequest{Income: 22100, Year: 2024},
+			expectedTotal:  2000,
+			expectedFactor: 1.21,
+			projected:      true,
+		},
+		{
+			name:           "Request overrides the inflation rate",
+			req:            core.TaxRequest{Income: 22100, Year: 2024, InflationRate: &override},
+			expectedTotal:  2420,
+			expectedFactor: 1,
+			projected:      true,
+		},
+		{
+			name:           "Projected from the latest year in storage",
+			req:            core.TaxRequest{Income: 22100, Year: 2024},
+			latest:         2023,
+			expectedTotal:  2220,
+			expectedFactor: 1.1,
+			projected:      true,
+		},
+		{
+			name:           "Published year after the latest in storage",
+			req:            core.TaxRequest{Income: 22100, Year: 2022},
+			latest:         2021,
+			expectedTotal:  2220,
+			expectedFactor: 1.1,
+			projected:      true,
+		},
+		{
+			name:          "Beyond the projection horizon",
+			req:           core.TaxRequest{Income: 22100, Year: latestTaxYear + service.MaxProjectionYears + 1},
+			expectedError: "is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{brackets: brackets, latest: tt.latest}
+
+			svc := service.NewTaxService(mock, service.WithIndexation(core.Indexation{InflationRate: 0.1}))
+			result, err := svc.Calculate(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+			assert.Equal(t, tt.projected, result.Projected)
+			// The latest year is resolved once for the whole request
+			assert.Equal(t, 1, mock.latestCalls)
+			if tt.projected {
+				baseYear := latestTaxYear
+				if tt.latest != 0 {
+					baseYear = tt.latest
+				}
+				assert.Equal(t, baseYear, result.Projection.BaseYear)
+				assert.InDelta(t, tt.expectedFactor, result.Projection.Factor, 0.0001)
+			} else {
+				assert.Nil(t, result.Projection)
+			}
+		})
+	}
+}
+
+func TestCalculate_ProjectedRules(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 10000, Rate: 0},
+		{Min: 10000, Max: 0, Rate: 0.2},
+	}
+	credit := func(amount float64) []core.RuleSpec {
+		return []core.RuleSpec{{Type: core.RuleBrackets}, {Type: core.RuleCredit, Rate: 1, Amount: amount}}
+	}
+
+	tests := []struct {
+		name          string
+		sets          []core.RuleSet
+		expectedTotal float64
+	}{
+		{
+			name:          "Rules of the base year indexed with the brackets",
+			sets:          []core.RuleSet{{Jurisdiction: "ON", Year: latestTaxYear, Rules: credit(1000)}},
+			expectedTotal: 2000 - 1210,
+		},
+		{
+			name: "Rules of the projected year kept as configured",
+			sets: []core.RuleSet{
+				{Jurisdiction: "ON", Year: latestTaxYear, Rules: credit(1000)},
+				{Jurisdiction: "ON", Year: latestTaxYear + 2, Rules: credit(500)},
+			},
+			expectedTotal: 2000 - 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{brackets: brackets}
+
+			svc := service.NewTaxService(mock, service.WithRuleSets(tt.sets...), service.WithIndexation(core.Indexation{InflationRate: 0.1}))
+			result, err := svc.Calculate(context.Background(), core.TaxRequest{Income: 22100, Year: latestTaxYear + 2, Jurisdiction: "ON"})
+
+			assert.NoError(t, err)
+			assert.True(t, result.Projected)
+			assert.InDelta(t, tt.expectedTotal, result.TotalTax, 0.0001)
+		})
+	}
+}
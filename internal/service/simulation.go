@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to simulate the tax on uncertain income
+func (s *taxService) CalculateSimulation(ctx context.Context, req core.SimulationRequest) (core.SimulationResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
+	if err != nil {
+		return core.SimulationResult{}, err
+	}
+
+	if err := s.checkTaxYear(ctx, req.Year); err != nil {
+		return core.SimulationResult{}, err
+	}
+
+	if err := req.Distribution.Validate(); err != nil {
+		logger.Log.Error().Err(err).Msgf("Invalid income distribution %q", req.Distribution.Type)
+		return core.SimulationResult{}, err
+	}
+
+	year, err := s.taxYear(ctx, req.Jurisdiction, req.Year, nil)
+	if err != nil {
+		return core.SimulationResult{}, err
+	}
+
+	result, err := core.ComputeSimulation(ctx, req, year)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to run simulation")
+		return core.SimulationResult{}, fmt.Errorf("failed to run simulation: %w", err)
+	}
+
+	logger.Log.Info().Msgf("Simulated %d trials of %s income for year: %d, expected tax: %.2f", result.Trials, req.Distribution.Type, req.Year, result.ExpectedTax)
+
+	return result, nil
+}
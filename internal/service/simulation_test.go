@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateSimulation(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 50000, Rate: 0.1},
+		{Min: 50000, Max: 0, Rate: 0.3},
+	}
+
+	tests := []struct {
+		name          string
+		req           core.SimulationRequest
+		mockErr       error
+		expectedError string
+		expectedTax   float64
+	}{
+		{
+			name: "Empirical income",
+			req: core.SimulationRequest{
+				Year:         2022,
+				Distribution: core.Distribution{Type: core.DistributionEmpirical, Samples: []float64{60000}},
+				Trials:       10,
+			},
+			expectedTax: 8000,
+		},
+		{
+			name:          "Unsupported year",
+			req:           core.SimulationRequest{Year: 2018, Distribution: core.Distribution{Type: core.DistributionNormal, Mean: 1}},
+			expectedError: "tax year 2018 is not supported",
+		},
+		{
+			name:          "Invalid distribution",
+			req:           core.SimulationRequest{Year: 2022, Distribution: core.Distribution{Type: "uniform"}},
+			expectedError: `unknown distribution "uniform"`,
+		},
+		{
+			name:          "Too many trials",
+			req:           core.SimulationRequest{Year: 2022, Distribution: core.Distribution{Type: core.DistributionNormal, Mean: 1}, Trials: core.MaxTrials + 1},
+			expectedError: "simulation has too many trials",
+		},
+		{
+			name:          "Storage error",
+			req:           core.SimulationRequest{Year: 2022, Distribution: core.Distribution{Type: core.DistributionNormal, Mean: 1}},
+			mockErr:       errors.New("storage down"),
+			expectedError: "failed to fetch tax brackets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{brackets: brackets, err: tt.mockErr}
+
+			svc := service.NewTaxService(mock)
+			result, err := svc.CalculateSimulation(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedTax, result.ExpectedTax, 0.0001)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// Business logic to calculate the withholding for a single paycheque
+func (s *taxService) CalculateWithholding(ctx context.Context, req core.WithholdingRequest) (core.WithholdingResult, error) {
+	ctx, err := s.withLatestTaxYear(ctx)
+	if err != nil {
+		return core.WithholdingResult{}, err
+	}
+
+	if err := s.checkTaxYear(ctx, req.Year); err != nil {
+		return core.WithholdingResult{}, err
+	}
+
+	periods := req.Frequency.PeriodsPerYear()
+	if periods == 0 {
+		logger.Log.Error().Msgf("Invalid pay frequency: %s", req.Frequency)
+		return core.WithholdingResult{}, fmt.Errorf("invalid pay frequency %q", req.Frequency)
+	}
+
+	if req.Gross < 0 || req.YTDGross < 0 || req.YTDWithheld < 0 {
+		logger.Log.Error().Msgf("Invalid pay amounts: gross %.2f, ytd gross %.2f, ytd withheld %.2f", req.Gross, req.YTDGross, req.YTDWithheld)
+		return core.WithholdingResult{}, fmt.Errorf("pay amounts must be non-negative")
+	}
+
+	if req.PayPeriod < 0 || req.PayPeriod > periods {
+		logger.Log.Error().Msgf("Invalid pay period %d for frequency %s", req.PayPeriod, req.Frequency)
+		return core.WithholdingResult{}, fmt.Errorf("pay period must be between 1 and %d", periods)
+	}
+
+	if req.PayPeriod == 0 && (req.YTDGross > 0 || req.YTDWithheld > 0) {
+		logger.Log.Error().Msg("Year-to-date amounts supplied without a pay period")
+		return core.WithholdingResult{}, fmt.Errorf("pay period is required with year-to-date amounts")
+	}
+
+	year, err := s.taxYear(ctx, req.Jurisdiction, req.Year, nil)
+	if err != nil {
+		return core.WithholdingResult{}, err
+	}
+
+	result, err := core.ComputeWithholding(req, year)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to calculate withholding")
+		return core.WithholdingResult{}, err
+	}
+
+	logger.Log.Info().Msgf("Calculated withholding: %.2f for gross: %.2f, frequency: %s, year: %d", result.Withholding, req.Gross, req.Frequency, req.Year)
+
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateWithholding(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 12000, Rate: 0},
+		{Min: 12000, Max: 0, Rate: 0.2},
+	}
+
+	tests := []struct {
+		name                string
+		req                 core.WithholdingRequest
+		mockErr             error
+		expectedError       string
+		expectedWithholding float64
+	}{
+		{
+			name:                "Monthly paycheque",
+			req:                 core.WithholdingRequest{Gross: 5000, Frequency: core.Monthly, Year: 2021},
+			expectedWithholding: 800,
+		},
+		{
+			name: "Last paycheque with year-to-date",
+			req: core.WithholdingRequest{
+				Gross:       10000,
+				Frequency:   core.Monthly,
+				Year:        2021,
+				PayPeriod:   12,
+				YTDGross:    55000,
+				YTDWithheld: 8000,
+			},
+			expectedWithholding: 2600,
+		},
+		{
+			name:                "Rules of the jurisdiction",
+			req:                 core.WithholdingRequest{Gross: 5000, Frequency: core.Monthly, Year: 2021, Jurisdiction: "ON"},
+			expectedWithholding: 800 + 250,
+		},
+		{
+			name:                "Rounding of the jurisdiction",
+			req:                 core.WithholdingRequest{Gross: 5050, Frequency: core.Monthly, Year: 2021, Jurisdiction: "NB"},
+			expectedWithholding: 9700.0 / 12, // 9720 rounded to the hundred
+		},
+		{
+			name:          "Jurisdiction without rules",
+			req:           core.WithholdingRequest{Gross: 5000, Frequency: core.Monthly, Year: 2021, Jurisdiction: "QC"},
+			expectedError: `no rules configured for jurisdiction "QC" in 2021`,
+		},
+		{
+			name:          "Unsupported year",
+			req:           core.WithholdingRequest{Gross: 5000, Frequency: core.Monthly, Year: 2010},
+			expectedError: "tax year 2010 is not supported",
+		},
+		{
+			name:          "Unknown frequency",
+			req:           core.WithholdingRequest{Gross: 5000, Frequency: "daily", Year: 2021},
+			expectedError: "invalid pay frequency",
+		},
+		{
+			name:          "Negative gross",
+			req:           core.WithholdingRequest{Gross: -1, Frequency: core.Monthly, Year: 2021},
+			expectedError: "pay amounts must be non-negative",
+		},
+		{
+			name:          "Pay period out of range",
+			req:           core.WithholdingRequest{Gross: 5000, Frequency: core.Monthly, Year: 2021, PayPeriod: 13},
+			expectedError: "pay period must be between 1 and 12",
+		},
+		{
+			name:          "Year-to-date without pay period",
+			req:           core.WithholdingRequest{Gross: 5000, Frequency: core.Monthly, Year: 2021, YTDGross: 10000},
+			expectedError: "pay period is required",
+		},
+		{
+			name:          "Error from storage",
+			req:           core.WithholdingRequest{Gross: 5000, Frequency: core.Monthly, Year: 2021},
+			mockErr:       errors.New("failed to fetch"),
+			expectedError: "failed to fetch tax brackets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockStorage{
+				brackets: brackets,
+				err:      tt.mockErr,
+			}
+
+			svc := service.NewTaxService(mock,
+				service.WithRuleSets(
+					core.RuleSet{
+						Jurisdiction: "ON",
+						Year:         2021,
+						Rules:        []core.RuleSpec{{Type: core.RuleBrackets}, {Type: core.RuleFlat, Rate: 0.05}},
+					},
+					core.RuleSet{Jurisdiction: "NB", Year: 2021, Rules: []core.RuleSpec{{Type: core.RuleBrackets}}},
+				),
+				service.WithRoundingPolicies(core.RoundingPolicy{Jurisdiction: "NB", Year: 2021, Step: core.RoundTotal, Increment: 100}),
+			)
+			result, err := svc.CalculateWithholding(context.Background(), tt.req)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedWithholding, result.Withholding, 0.01)
+		})
+	}
+}
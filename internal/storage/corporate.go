@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type CorporateStorage interface {
+	FetchCorporateParameters(ctx context.Context, year int) (core.CorporateParameters, error)
+}
+
+// corporateParameters holds the corporate parameters of each year in memory
+type corporateParameters map[int]core.CorporateParameters
+
+// LoadCorporateParameters reads the corporate parameters from a local CSV
+// file with one "year,small_business_rate,general_rate,business_limit,
+// passive_threshold,grind_rate" row per year. Lines starting with # and a
+// header row are skipped.
+func LoadCorporateParameters(path string) (CorporateStorage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Log.Error().Err(err).Msgf("Failed to open corporate parameters %s", path)
+		return nil, fmt.Errorf("failed to open corporate parameters: %w", err)
+	}
+	defer file.Close()
+
+	return ParseCorporateParameters(file, path)
+}
+
+// ParseCorporateParameters reads corporate parameters in the format of
+// LoadCorporateParameters. Source is recorded on every year.
+func ParseCorporateParameters(r io.Reader, source string) (CorporateStorage, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 6
+	reader.TrimLeadingSpace = true
+
+	params := make(corporateParameters)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid corporate parameters: %w", err)
+		}
+
+		year, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("invalid corporate parameters year %q on line %d", record[0], line)
+		}
+		if _, ok := params[year]; ok {
+			return nil, fmt.Errorf("corporate parameters for %d are listed more than once", year)
+		}
+
+		values := make([]float64, 5)
+		for i, field := range record[1:] {
+			if values[i], err = strconv.ParseFloat(strings.TrimSpace(field), 64); err != nil {
+				return nil, fmt.Errorf("invalid corporate parameter %q for year %d", field, year)
+			}
+		}
+		p := core.CorporateParameters{
+			SmallBusinessRate: values[0],
+			GeneralRate:       values[1],
+			BusinessLimit:     values[2],
+			PassiveThreshold:  values[3],
+			GrindRate:         values[4],
+			Source:            source,
+		}
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid corporate parameters for year %d: %w", year, err)
+		}
+		params[year] = p
+	}
+
+	return params, nil
+}
+
+// Fetch the corporate tax parameters of the year
+func (p corporateParameters) FetchCorporateParameters(ctx context.Context, year int) (core.CorporateParameters, error) {
+	params, ok := p[year]
+	if !ok {
+		return core.CorporateParameters{}, fmt.Errorf("%w for %d", core.ErrNoCorporateParameters, year)
+	}
+	return params, nil
+}
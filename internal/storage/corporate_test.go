@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCorporateParameters(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      map[int]core.CorporateParameters
+		expectedError string
+	}{
+		{
+			name:  "With header and comments",
+			input: "year,small_business_rate,general_rate,business_limit,passive_threshold,grind_rate\n# federal\n2022,0.09,0.15,500000,50000,5\n2023, 0.09, 0.15, 500000, 50000, 5\n",
+			expected: map[int]core.CorporateParameters{
+				2022: {SmallBusinessRate: 0.09, GeneralRate: 0.15, BusinessLimit: 500000, PassiveThreshold: 50000, GrindRate: 5, Source: "test"},
+				2023: {SmallBusinessRate: 0.09, GeneralRate: 0.15, BusinessLimit: 500000, PassiveThreshold: 50000, GrindRate: 5, Source: "test"},
+			},
+		},
+		{
+			name:          "Invalid year",
+			input:         "2022,0.09,0.15,500000,50000,5\nnext,0.09,0.15,500000,50000,5\n",
+			expectedError: `invalid corporate parameters year "next"`,
+		},
+		{
+			name:          "Invalid amount",
+			input:         "2022,0.09,high,500000,50000,5\n",
+			expectedError: `invalid corporate parameter "high" for year 2022`,
+		},
+		{
+			name:          "Rate above one",
+			input:         "2022,0.09,1.5,500000,50000,5\n",
+			expectedError: "invalid corporate parameters for year 2022",
+		},
+		{
+			name:          "Duplicate year",
+			input:         "2022,0.09,0.15,500000,50000,5\n2022,0.09,0.15,500000,50000,5\n",
+			expectedError: "listed more than once",
+		},
+		{
+			name:          "Wrong number of fields",
+			input:         "2022,0.09,0.15\n",
+			expectedError: "invalid corporate parameters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, err := ParseCorporateParameters(strings.NewReader(tt.input), "test")
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			for year, expected := range tt.expected {
+				params, err := storage.FetchCorporateParameters(context.Background(), year)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, params)
+			}
+		})
+	}
+}
+
+func TestLoadCorporateParameters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corporate.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("2022,0.09,0.15,500000,50000,5\n"), 0o644))
+
+	storage, err := LoadCorporateParameters(path)
+	assert.NoError(t, err)
+
+	params, err := storage.FetchCorporateParameters(context.Background(), 2022)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.15, params.GeneralRate)
+	assert.Equal(t, path, params.Source)
+
+	_, err = storage.FetchCorporateParameters(context.Background(), 2021)
+	assert.ErrorIs(t, err, core.ErrNoCorporateParameters)
+
+	_, err = LoadCorporateParameters(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.ErrorContains(t, err, "failed to open corporate parameters")
+}
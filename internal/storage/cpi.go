@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+// LoadCPISeries reads a CPI series from a local CSV file with one
+// "year,index" row per year. Lines starting with # and a header row are
+// skipped.
+func LoadCPISeries(path string) (core.CPISeries, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Log.Error().Err(err).Msgf("Failed to open CPI series %s", path)
+		return nil, fmt.Errorf("failed to open CPI series: %w", err)
+	}
+	defer file.Close()
+
+	return ParseCPISeries(file)
+}
+
+// ParseCPISeries reads a CPI series in the format of LoadCPISeries
+func ParseCPISeries(r io.Reader) (core.CPISeries, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	series := make(core.CPISeries)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPI series: %w", err)
+		}
+
+		year, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("invalid CPI year %q on line %d", record[0], line)
+		}
+		index, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil || index <= 0 {
+			return nil, fmt.Errorf("invalid CPI index %q for year %d", record[1], year)
+		}
+		series[year] = index
+	}
+
+	return series, nil
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCPISeries(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      core.CPISeries
+		expectedError string
+	}{
+		{
+			name:     "With header and comments",
+			input:    "year,index\n# forecast from 2023\n2022,151.2\n2023, 157.0\n",
+			expected: core.CPISeries{2022: 151.2, 2023: 157.0},
+		},
+		{
+			name:     "Without header",
+			input:    "2022,151.2\n",
+			expected: core.CPISeries{2022: 151.2},
+		},
+		{
+			name:          "Invalid year",
+			input:         "2022,151.2\nnext,157.0\n",
+			expectedError: `invalid CPI year "next"`,
+		},
+		{
+			name:          "Invalid index",
+			input:         "2022,-1\n",
+			expectedError: "invalid CPI index",
+		},
+		{
+			name:          "Wrong number of fields",
+			input:         "2022,151.2,extra\n",
+			expectedError: "invalid CPI series",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, err := ParseCPISeries(strings.NewReader(tt.input))
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, series)
+		})
+	}
+}
+
+func TestLoadCPISeries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cpi.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("2022,151.2\n2023,157.0\n"), 0o644))
+
+	series, err := LoadCPISeries(path)
+	assert.NoError(t, err)
+	assert.Equal(t, core.CPISeries{2022: 151.2, 2023: 157.0}, series)
+
+	_, err = LoadCPISeries(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.ErrorContains(t, err, "failed to open CPI series")
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/haninamaryia/tax-calculator/internal/logger"
+)
+
+type SalesTaxStorage interface {
+	FetchSalesTaxRates(ctx context.Context, jurisdiction string) ([]core.SalesTaxRate, error)
+}
+
+// salesTaxRates holds every effective-dated rate of each jurisdiction in
+// memory
+type salesTaxRates map[string][]core.SalesTaxRate
+
+// LoadSalesTaxRates reads the sales tax rates from a local CSV file with one
+// "jurisdiction,effective_from,effective_to,component,rate,compound" row per
+// component. Consecutive rows of a jurisdiction with the same dates make up
+// one rate, in the order its components are levied. effective_to and
+// compound may be empty. Lines starting with # and a header row are skipped.
+func LoadSalesTaxRates(path string) (SalesTaxStorage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Log.Error().Err(err).Msgf("Failed to open sales tax rates %s", path)
+		return nil, fmt.Errorf("failed to open sales tax rates: %w", err)
+	}
+	defer file.Close()
+
+	return ParseSalesTaxRates(file, path)
+}
+
+// ParseSalesTaxRates reads sales tax rates in the format of
+// LoadSalesTaxRates. Source is recorded on every rate.
+func ParseSalesTaxRates(r io.Reader, source string) (SalesTaxStorage, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 6
+	reader.TrimLeadingSpace = true
+
+	rates := make(salesTaxRates)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid sales tax rates: %w", err)
+		}
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		jurisdiction, from, to, name := record[0], record[1], record[2], record[3]
+
+		rate, err := strconv.ParseFloat(record[4], 64)
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("invalid sales tax rate %q on line %d", record[4], line)
+		}
+		compound := false
+		if record[5] != "" {
+			if compound, err = strconv.ParseBool(record[5]); err != nil {
+				return nil, fmt.Errorf("invalid compound flag %q on line %d", record[5], line)
+			}
+		}
+		component := core.SalesTaxComponent{Name: name, Rate: rate, Compound: compound}
+
+		existing := rates[jurisdiction]
+		if n := len(existing); n > 0 && existing[n-1].EffectiveFrom == from && existing[n-1].EffectiveTo == to {
+			existing[n-1].Components = append(existing[n-1].Components, component)
+			continue
+		}
+		rates[jurisdiction] = append(existing, core.SalesTaxRate{
+			Jurisdiction:  jurisdiction,
+			EffectiveFrom: from,
+			EffectiveTo:   to,
+			Components:    []core.SalesTaxComponent{component},
+			Source:        source,
+		})
+	}
+
+	for jurisdiction, list := range rates {
+		for _, rate := range list {
+			if err := rate.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid sales tax rate for %q from %s: %w", jurisdiction, rate.EffectiveFrom, err)
+			}
+		}
+	}
+
+	return rates, nil
+}
+
+// Fetch the sales tax rates of the jurisdiction
+func (s salesTaxRates) FetchSalesTaxRates(ctx context.Context, jurisdiction string) ([]core.SalesTaxRate, error) {
+	rates, ok := s[jurisdiction]
+	if !ok {
+		return nil, fmt.Errorf("%w for %q", core.ErrNoSalesTaxRate, jurisdiction)
+	}
+	return rates, nil
+}
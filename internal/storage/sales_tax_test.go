@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/haninamaryia/tax-calculator/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSalesTaxRates(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      map[string][]core.SalesTaxRate
+		expectedError string
+	}{
+		{
+			name: "Stacked components and dated rates",
+			input: "jurisdiction,effective_from,effective_to,component,rate,compound\n" +
+				"# harmonized from July 2010\n" +
+				"ON,2008-01-01,2010-06-30,federal,0.05,\n" +
+				"ON,2010-07-01,,federal,0.05,\n" +
+				"ON,2010-07-01,,provincial,0.08,\n" +
+				"QC,2013-01-01,,federal,0.05,false\n" +
+				"QC,2013-01-01,,provincial,0.09975,true\n",
+			expected: map[string][]core.SalesTaxRate{
+				"ON": {
+					{Jurisdiction: "ON", EffectiveFrom: "2008-01-01", EffectiveTo: "2010-06-30", Source: "test",
+						Components: []core.SalesTaxComponent{{Name: "federal", Rate: 0.05}}},
+					{Jurisdiction: "ON", EffectiveFrom: "2010-07-01", Source: "test",
+						Components: []core.SalesTaxComponent{{Name: "federal", Rate: 0.05}, {Name: "provincial", Rate: 0.08}}},
+				},
+				"QC": {
+					{Jurisdiction: "QC", EffectiveFrom: "2013-01-01", Source: "test",
+						Components: []core.SalesTaxComponent{{Name: "federal", Rate: 0.05}, {Name: "provincial", Rate: 0.09975, Compound: true}}},
+				},
+			},
+		},
+		{
+			name:          "Invalid rate",
+			input:         "ON,2010-07-01,,federal,0.05,\nON,2010-07-01,,provincial,high,\n",
+			expectedError: `invalid sales tax rate "high" on line 2`,
+		},
+		{
+			name:          "Invalid compound flag",
+			input:         "ON,2010-07-01,,federal,0.05,maybe\n",
+			expectedError: `invalid compound flag "maybe"`,
+		},
+		{
+			name:          "Invalid date",
+			input:         "ON,07/01/2010,,federal,0.05,\n",
+			expectedError: `invalid sales tax rate for "ON"`,
+		},
+		{
+			name:          "Wrong number of fields",
+			input:         "ON,2010-07-01,federal,0.05\n",
+			expectedError: "invalid sales tax rates",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, err := ParseSalesTaxRates(strings.NewReader(tt.input), "test")
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			for jurisdiction, expected := range tt.expected {
+				rates, err := storage.FetchSalesTaxRates(context.Background(), jurisdiction)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, rates)
+			}
+		})
+	}
+}
+
+func TestLoadSalesTaxRates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sales_tax.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("ON,2010-07-01,,federal,0.05,\n"), 0o644))
+
+	storage, err := LoadSalesTaxRates(path)
+	assert.NoError(t, err)
+
+	rates, err := storage.FetchSalesTaxRates(context.Background(), "ON")
+	assert.NoError(t, err)
+	assert.Len(t, rates, 1)
+	assert.Equal(t, path, rates[0].Source)
+
+	_, err = storage.FetchSalesTaxRates(context.Background(), "XX")
+	assert.ErrorIs(t, err, core.ErrNoSalesTaxRate)
+
+	_, err = LoadSalesTaxRates(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.ErrorContains(t, err, "failed to open sales tax rates")
+}
@@ -3,9 +3,11 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/haninamaryia/tax-calculator/internal/core"
@@ -14,11 +16,53 @@ import (
 
 type TaxStorage interface {
 	FetchTaxBrackets(ctx context.Context, year int) ([]core.TaxBracket, error)
+	FetchTaxSchedules(ctx context.Context, year int) ([]core.TaxSchedule, error)
+	FetchLatestTaxYear(ctx context.Context) (int, error)
 }
 
+// FirstTaxYear is the first year the API publishes
+const FirstTaxYear = 2019
+
+// ErrNotFound is returned when the API has nothing at the requested URL,
+// such as a tax year it does not publish
+var ErrNotFound = errors.New("not found")
+
+// latestTTL is how long the latest published tax year is trusted before it
+// is probed again, so that a newly published year is picked up
+const latestTTL = time.Hour
+
+// latestRetry is how long the previous latest year keeps being served after
+// a probe fails, before the next attempt
+const latestRetry = time.Minute
+
 type taxAPIClient struct {
 	baseURL string
 	client  *http.Client
+
+	// latest is the latest published tax year, 0 until it is fetched, and
+	// expires when it is probed again. mu guards them but is never held
+	// across a probe: probe is the one in flight, if any, and callers
+	// arriving meanwhile wait for it instead of starting their own.
+	mu      sync.Mutex
+	latest  int
+	expires time.Time
+	probe   *latestProbe
+}
+
+// latestProbe is a probe for the latest tax year, shared by every caller
+// waiting on it. year and err are set before done is closed.
+type latestProbe struct {
+	done chan struct{}
+	year int
+	err  error
+}
+
+// taxYearResponse is the payload returned by the API for a tax year.
+// Schedules is only present when the year has effective-dated schedules.
+type taxYearResponse struct {
+	TaxBrackets []core.TaxBracket  `json:"tax_brackets"`
+	Schedules   []core.TaxSchedule `json:"schedules"`
+	Version     string             `json:"version"`
 }
 
 // Constructor to initialize the taxAPIClient
@@ -31,9 +75,140 @@ func NewTaxAPIClient(baseURL string) TaxStorage {
 
 // Fetch the tax brackets from the API for the specified year
 func (t *taxAPIClient) FetchTaxBrackets(ctx context.Context, year int) ([]core.TaxBracket, error) {
+	response, body, err := t.fetchTaxYear(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response.TaxBrackets) == 0 {
+		logger.Log.Warn().Msgf("Missing or invalid tax brackets in response: %s", string(body))
+		return nil, fmt.Errorf("missing or invalid tax brackets in the response: %s", string(body))
+	}
+
+	logger.Log.Info().Msgf("Fetched tax brackets for year %d successfully", year)
+	return response.TaxBrackets, nil
+}
+
+// Fetch the effective-dated schedules from the API for the specified year.
+// Years without dated schedules are returned as a single whole-year schedule.
+func (t *taxAPIClient) FetchTaxSchedules(ctx context.Context, year int) ([]core.TaxSchedule, error) {
+	response, body, err := t.fetchTaxYear(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := response.Schedules
+	if len(schedules) == 0 {
+		if len(response.TaxBrackets) == 0 {
+			logger.Log.Warn().Msgf("Missing or invalid tax schedules in response: %s", string(body))
+			return nil, fmt.Errorf("missing or invalid tax schedules in the response: %s", string(body))
+		}
+		schedules = []core.TaxSchedule{core.WholeYearSchedule(year, response.TaxBrackets)}
+	}
+
+	// Record where each schedule came from for the calculation audit trail
+	for i := range schedules {
+		if schedules[i].Source == "" {
+			schedules[i].Source = t.taxYearURL(year)
+		}
+		if schedules[i].Version == "" {
+			schedules[i].Version = response.Version
+		}
+	}
+
+	logger.Log.Info().Msgf("Fetched %d tax schedules for year %d successfully", len(schedules), year)
+	return schedules, nil
+}
 
+// Fetch the latest tax year the API publishes. The answer is cached for
+// latestTTL. A single probe refreshes it while other callers wait, and when
+// a refresh fails the previous answer is served until latestRetry has
+// passed.
+func (t *taxAPIClient) FetchLatestTaxYear(ctx context.Context) (int, error) {
+	t.mu.Lock()
+	if t.latest != 0 && time.Now().Before(t.expires) {
+		latest := t.latest
+		t.mu.Unlock()
+		return latest, nil
+	}
+	p := t.probe
+	if p == nil {
+		p = &latestProbe{done: make(chan struct{})}
+		t.probe = p
+		go t.runProbe(p)
+	}
+	t.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return p.year, p.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// runProbe probes the latest tax year for every caller waiting on p. It
+// does not run under any caller's context, so one caller giving up does not
+// fail the others.
+func (t *taxAPIClient) runProbe(p *latestProbe) {
+	year, err := t.probeLatestTaxYear(context.Background())
+
+	t.mu.Lock()
+	switch {
+	case err == nil:
+		t.latest, t.expires = year, time.Now().Add(latestTTL)
+	case t.latest != 0:
+		logger.Log.Warn().Err(err).Msgf("Failed to refresh the latest tax year, keeping %d", t.latest)
+		year, err = t.latest, nil
+		t.expires = time.Now().Add(latestRetry)
+	}
+	t.probe = nil
+	p.year, p.err = year, err
+	t.mu.Unlock()
+	close(p.done)
+}
+
+// probeLatestTaxYear probes years upward from FirstTaxYear until one is not
+// found, up to the next calendar year
+func (t *taxAPIClient) probeLatestTaxYear(ctx context.Context) (int, error) {
+	latest := FirstTaxYear - 1
+	for latest <= time.Now().Year() {
+		_, _, err := t.fetchTaxYear(ctx, latest+1)
+		if errors.Is(err, ErrNotFound) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		latest++
+	}
+	if latest < FirstTaxYear {
+		return 0, fmt.Errorf("no tax year published from %d: %w", FirstTaxYear, ErrNotFound)
+	}
+
+	logger.Log.Info().Msgf("Latest published tax year is %d", latest)
+	return latest, nil
+}
+
+func (t *taxAPIClient) taxYearURL(year int) string {
 	//TODO: put this in config
-	url := fmt.Sprintf("%s/tax-calculator/tax-year/%d", t.baseURL, year)
+	return fmt.Sprintf("%s/tax-calculator/tax-year/%d", t.baseURL, year)
+}
+
+// fetchTaxYear retrieves and decodes the API payload for the specified year.
+// The raw body is returned alongside for logging.
+func (t *taxAPIClient) fetchTaxYear(ctx context.Context, year int) (taxYearResponse, []byte, error) {
+	var response taxYearResponse
+	body, err := t.getJSON(ctx, t.taxYearURL(year), "tax brackets", &response)
+	if err != nil {
+		return taxYearResponse{}, nil, err
+	}
+	return response, body, nil
+}
+
+// getJSON retrieves url and decodes its JSON body into v. What names the
+// resource in errors. The raw body is returned alongside for logging.
+func (t *taxAPIClient) getJSON(ctx context.Context, url, what string, v any) ([]byte, error) {
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -44,7 +219,7 @@ func (t *taxAPIClient) FetchTaxBrackets(ctx context.Context, year int) ([]core.T
 	resp, err := t.client.Do(req)
 	if err != nil {
 		logger.Log.Error().Err(err).Msg("Failed to make HTTP request")
-		return nil, fmt.Errorf("failed to fetch tax brackets: %w", err)
+		return nil, fmt.Errorf("failed to fetch %s: %w", what, err)
 	}
 	defer resp.Body.Close()
 
@@ -53,11 +228,11 @@ func (t *taxAPIClient) FetchTaxBrackets(ctx context.Context, year int) ([]core.T
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		logger.Log.Warn().Msgf("Unexpected status code %d, response body: %s", resp.StatusCode, string(body))
-		return nil, fmt.Errorf("unexpected response status: %s. Response body: %s", resp.Status, string(body))
-	}
-
-	var response struct {
-		TaxBrackets []core.TaxBracket `json:"tax_brackets"`
+		err := fmt.Errorf("unexpected response status: %s. Response body: %s", resp.Status, string(body))
+		if resp.StatusCode == http.StatusNotFound {
+			err = fmt.Errorf("%w: %w", ErrNotFound, err)
+		}
+		return nil, err
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -66,16 +241,10 @@ func (t *taxAPIClient) FetchTaxBrackets(ctx context.Context, year int) ([]core.T
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := json.Unmarshal(body, v); err != nil {
 		logger.Log.Error().Err(err).Msg("Failed to decode response body")
 		return nil, fmt.Errorf("failed to decode response body: %w", err)
 	}
 
-	if len(response.TaxBrackets) == 0 {
-		logger.Log.Warn().Msgf("Missing or invalid tax brackets in response: %s", string(body))
-		return nil, fmt.Errorf("missing or invalid tax brackets in the response: %s", string(body))
-	}
-
-	logger.Log.Info().Msgf("Fetched tax brackets for year %d successfully", year)
-	return response.TaxBrackets, nil
+	return body, nil
 }
@@ -3,8 +3,11 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -119,3 +122,236 @@ func TestFetchTaxBrackets(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchTaxSchedules(t *testing.T) {
+	brackets := []core.TaxBracket{
+		{Min: 0, Max: 50000, Rate: 0.1},
+		{Min: 50000, Max: 0, Rate: 0.2},
+	}
+	schedules := []core.TaxSchedule{
+		{EffectiveFrom: "2023-01-01", EffectiveTo: "2023-06-30", Brackets: brackets},
+		{EffectiveFrom: "2023-07-01", EffectiveTo: "2023-12-31", Brackets: brackets[:1]},
+	}
+
+	tests := []struct {
+		name           string
+		serverBehavior func(w http.ResponseWriter, r *http.Request)
+		expectedError  string
+		expectedResult []core.TaxSchedule
+	}{
+		{
+			name: "Dated schedules",
+			serverBehavior: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/tax-calculator/tax-year/2023", r.URL.Path)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"tax_brackets": brackets,
+					"schedules":    schedules,
+					"version":      "v3",
+				})
+			},
+			expectedResult: []core.TaxSchedule{
+				{EffectiveFrom: "2023-01-01", EffectiveTo: "2023-06-30", Brackets: brackets, Version: "v3"},
+				{EffectiveFrom: "2023-07-01", EffectiveTo: "2023-12-31", Brackets: brackets[:1], Version: "v3"},
+			},
+		},
+		{
+			name: "Brackets only fall back to a whole-year schedule",
+			serverBehavior: func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"tax_brackets": brackets,
+				})
+			},
+			expectedResult: []core.TaxSchedule{
+				{EffectiveFrom: "2023-01-01", EffectiveTo: "2023-12-31", Brackets: brackets},
+			},
+		},
+		{
+			name: "Empty response",
+			serverBehavior: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("{}"))
+			},
+			expectedError: "missing or invalid tax schedules",
+		},
+		{
+			name: "Bad status code",
+			serverBehavior: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "not found", http.StatusNotFound)
+			},
+			expectedError: "unexpected response status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(tt.serverBehavior))
+			defer server.Close()
+
+			client := NewTaxAPIClient(server.URL)
+			result, err := client.FetchTaxSchedules(context.Background(), 2023)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				for i := range tt.expectedResult {
+					tt.expectedResult[i].Source = server.URL + "/tax-calculator/tax-year/2023"
+				}
+				assert.Equal(t, tt.expectedResult, result)
+			}
+		})
+	}
+}
+
+// published serves the years up to latest and 404 after it
+func published(latest int) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var year int
+		fmt.Sscanf(r.URL.Path, "/tax-calculator/tax-year/%d", &year)
+		if year > latest {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"tax_brackets": [{"min": 0, "rate": 0.15}]}`))
+	}
+}
+
+func TestFetchLatestTaxYear(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		serverBehavior func(w http.ResponseWriter, r *http.Request)
+		expectedError  string
+		expectedResult int
+	}{
+		{
+			name:           "Latest published year",
+			serverBehavior: published(2022),
+			expectedResult: 2022,
+		},
+		{
+			name:           "No year published",
+			serverBehavior: published(FirstTaxYear - 1),
+			expectedError:  "no tax year published from 2019",
+		},
+		{
+			name: "Server error",
+			serverBehavior: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			},
+			expectedError: "unexpected response status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				tt.serverBehavior(w, r)
+			}))
+			defer server.Close()
+
+			client := NewTaxAPIClient(server.URL)
+			result, err := client.FetchLatestTaxYear(context.Background())
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+
+			// The latest year is only probed once
+			probes := calls.Load()
+			result, err = client.FetchLatestTaxYear(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+			assert.Equal(t, probes, calls.Load())
+		})
+	}
+}
+
+func TestFetchLatestTaxYear_Refresh(t *testing.T) {
+	var latest, failing atomic.Int32
+	latest.Store(2022)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() != 0 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		published(int(latest.Load()))(w, r)
+	}))
+	defer server.Close()
+
+	client := NewTaxAPIClient(server.URL)
+	expire := func() {
+		api := client.(*taxAPIClient)
+		api.mu.Lock()
+		api.expires = time.Now()
+		api.mu.Unlock()
+	}
+
+	result, err := client.FetchLatestTaxYear(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2022, result)
+
+	// A year published later is seen once the cached answer expires
+	latest.Store(2023)
+	result, err = client.FetchLatestTaxYear(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2022, result)
+	expire()
+	result, err = client.FetchLatestTaxYear(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2023, result)
+
+	// A failed refresh keeps serving the previous answer
+	failing.Store(1)
+	expire()
+	result, err = client.FetchLatestTaxYear(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2023, result)
+}
+
+func TestFetchLatestTaxYear_Concurrent(t *testing.T) {
+	var probes atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == fmt.Sprintf("/tax-calculator/tax-year/%d", FirstTaxYear) {
+			probes.Add(1)
+			<-release
+		}
+		published(2022)(w, r)
+	}))
+	defer server.Close()
+
+	client := NewTaxAPIClient(server.URL)
+
+	// A caller giving up does not hold the others back or fail them
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.FetchLatestTaxYear(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = client.FetchLatestTaxYear(context.Background())
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Every caller shares a single probe
+	assert.Equal(t, int32(1), probes.Load())
+	for _, result := range results {
+		assert.Equal(t, 2022, result)
+	}
+}
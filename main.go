@@ -3,6 +3,8 @@ package main
 import (
 	"log"
 
+	"github.com/haninamaryia/tax-calculator/config"
+	"github.com/haninamaryia/tax-calculator/internal/core"
 	"github.com/haninamaryia/tax-calculator/internal/handler"
 	"github.com/haninamaryia/tax-calculator/internal/logger"
 	"github.com/haninamaryia/tax-calculator/internal/service"
@@ -13,12 +15,52 @@ func main() {
 	// Initialize logger before anything else
 	logger.InitLogger()
 
+	// Load the configuration, including the tax rules per jurisdiction and year
+	cfg := config.GetConfig()
+
 	// Initialize the storage client that talks to the API
 	storageClient := storage.NewTaxAPIClient("http://localhost:5001")
 
-	// Initialize the tax service with the storage client
-	// TODO: when there is more config, pass the config here
-	taxService := service.NewTaxService(storageClient)
+	// Index projected years with the CPI series when one is configured
+	indexation := core.Indexation{InflationRate: cfg.Projection.InflationRate}
+	if cfg.Projection.CPIFile != "" {
+		series, err := storage.LoadCPISeries(cfg.Projection.CPIFile)
+		if err != nil {
+			log.Fatal("Error loading CPI series: ", err)
+		}
+		indexation.CPI = series
+	}
+
+	// Initialize the tax service with the storage client and its configuration
+	opts := []service.Option{
+		service.WithRuleSets(cfg.RuleSets...),
+		service.WithPrescribedRates(cfg.PrescribedRates...),
+		service.WithCarryRules(cfg.CarryRules...),
+		service.WithIndexation(indexation),
+		service.WithRoundingPolicies(cfg.Rounding...),
+	}
+
+	// Sales tax rates are not served by the API and are read from a local
+	// file
+	if cfg.Data.SalesTaxFile != "" {
+		salesTax, err := storage.LoadSalesTaxRates(cfg.Data.SalesTaxFile)
+		if err != nil {
+			log.Fatal("Error loading sales tax rates: ", err)
+		}
+		opts = append(opts, service.WithSalesTaxStorage(salesTax))
+	}
+
+	// Corporate parameters are not served by the API and are read from a
+	// local file
+	if cfg.Data.CorporateFile != "" {
+		corporate, err := storage.LoadCorporateParameters(cfg.Data.CorporateFile)
+		if err != nil {
+			log.Fatal("Error loading corporate parameters: ", err)
+		}
+		opts = append(opts, service.WithCorporateStorage(corporate))
+	}
+
+	taxService := service.NewTaxService(storageClient, opts...)
 
 	// Initialize the HTTP handler with the tax service
 	taxHandler := handler.NewServer(8080, taxService)